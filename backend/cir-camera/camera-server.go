@@ -0,0 +1,39 @@
+package circamera
+
+import (
+	"net/http"
+
+	"github.com/One-Hundred-Eighty/Circle/backend/cir-camera/gateway"
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	dartmasterlogger "github.com/One-Hundred-Eighty/Circle/pkg/dartmaster-logger"
+	"github.com/One-Hundred-Eighty/Circle/utils"
+	"github.com/gorilla/mux"
+)
+
+func NewServer(logger *dartmasterlogger.DartmasterLogger, cameraAdmin gateway.CameraAdmin, calibrationStore *calibration.Store, intrinsicsStore *calibration.IntrinsicsStore, detectionPipeline gateway.DetectionPipeline, port string) *http.Server {
+	router := mux.NewRouter()
+	cameraGateway := gateway.NewCameraGateway(logger, cameraAdmin, calibrationStore, intrinsicsStore, detectionPipeline)
+
+	// initiate camera uris
+	router.Path("/camera/cameras").HandlerFunc(cameraGateway.Cameras()).Methods(http.MethodGet)
+	router.Path("/camera/takeout").HandlerFunc(cameraGateway.Takeout()).Methods(http.MethodPost)
+	router.Path("/camera/{cameraID:[0-9]+}/info").HandlerFunc(cameraGateway.Info()).Methods(http.MethodGet)
+	router.Path("/camera/{cameraID:[0-9]+}/stream").HandlerFunc(cameraGateway.Stream()).Methods(http.MethodGet)
+	router.Path("/camera/{cameraID:[0-9]+}/snapshot").HandlerFunc(cameraGateway.Snapshot()).Methods(http.MethodGet)
+	router.Path("/camera/{cameraID:[0-9]+}/calibration").HandlerFunc(cameraGateway.GetCalibration()).Methods(http.MethodGet)
+	router.Path("/camera/{cameraID:[0-9]+}/calibration").HandlerFunc(cameraGateway.Calibrate()).Methods(http.MethodPost)
+	router.Path("/camera/{cameraID:[0-9]+}/calibration").HandlerFunc(cameraGateway.DeleteCalibration()).Methods(http.MethodDelete)
+	router.Path("/camera/{cameraID:[0-9]+}/calibration/auto").HandlerFunc(cameraGateway.AutoCalibrate()).Methods(http.MethodPost)
+	router.Path("/camera/{cameraID:[0-9]+}/intrinsics").HandlerFunc(cameraGateway.GetIntrinsics()).Methods(http.MethodGet)
+	router.Path("/camera/{cameraID:[0-9]+}/intrinsics").HandlerFunc(cameraGateway.CalibrateIntrinsics()).Methods(http.MethodPost)
+	router.Path("/camera/{cameraID:[0-9]+}/intrinsics").HandlerFunc(cameraGateway.DeleteIntrinsics()).Methods(http.MethodDelete)
+	router.Path("/camera/{cameraID:[0-9]+}/intrinsics/checkerboard").HandlerFunc(cameraGateway.AddCheckerboard()).Methods(http.MethodPost)
+
+	// initiate http server
+	httpServer := utils.NewHttpServer(router, port)
+
+	// print the registered routes for debugging-purposes
+	logger.PrintRegisteredRouterPaths("camera", "", router, port)
+
+	return httpServer
+}
@@ -0,0 +1,598 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/jpeg"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/device"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+	dartmasterlogger "github.com/One-Hundred-Eighty/Circle/pkg/dartmaster-logger"
+	"github.com/gorilla/mux"
+)
+
+// snapshotTimeout is the maximum time to wait for a camera frame.
+const snapshotTimeout = 5 * time.Second
+
+// streamBoundary separates the jpeg frames of an mjpeg stream.
+const streamBoundary = "frame"
+
+// CameraAdmin is the part of the camera-admin that is served by the camera gateway.
+type CameraAdmin interface {
+	CameraIDs() []int
+	Info(cameraID int) (device.Info, error)
+	Identity(cameraID int) (string, error)
+	ROI(cameraID int) v4l2.Rect
+	Snapshot(cameraID int, timeout time.Duration) (frame.Frame, error)
+	Subscribe(cameraID int, subscriberName string) <-chan frame.Frame
+	Unsubscribe(cameraID int, frameCh <-chan frame.Frame, subscriberName string)
+	SubscribeOverlay(cameraID int, subscriberName string) (<-chan frame.Frame, error)
+	UnsubscribeOverlay(cameraID int, frameCh <-chan frame.Frame, subscriberName string)
+}
+
+// DetectionPipeline is the part of the detection pipeline that is served by the camera gateway. It is nil if the
+// detection pipeline is not running.
+type DetectionPipeline interface {
+	Takeout()
+}
+
+type cameraGateway struct {
+	logger            *dartmasterlogger.DartmasterLogger
+	cameraAdmin       CameraAdmin
+	calibrationStore  *calibration.Store
+	intrinsicsStore   *calibration.IntrinsicsStore
+	detectionPipeline DetectionPipeline
+	checkerboardsMu   sync.Mutex
+	checkerboards     map[int][]calibration.Checkerboard // collected checkerboard snapshots of the running lens calibrations
+}
+
+type calibrationRequest struct {
+	ImageWidth  int                          `json:"imageWidth"`
+	ImageHeight int                          `json:"imageHeight"`
+	Points      []calibration.Correspondence `json:"points"`
+}
+
+type autoCalibrationResponse struct {
+	Calibration calibration.Calibration    `json:"calibration"`
+	Detection   calibration.BoardDetection `json:"detection"`
+}
+
+type checkerboardResponse struct {
+	Snapshots    int                      `json:"snapshots"` // number of collected snapshots
+	Required     int                      `json:"required"`  // minimum number of snapshots for the lens calibration
+	Checkerboard calibration.Checkerboard `json:"checkerboard"`
+}
+
+func NewCameraGateway(logger *dartmasterlogger.DartmasterLogger, cameraAdmin CameraAdmin, calibrationStore *calibration.Store, intrinsicsStore *calibration.IntrinsicsStore, detectionPipeline DetectionPipeline) *cameraGateway {
+	return &cameraGateway{
+		logger:            logger,
+		cameraAdmin:       cameraAdmin,
+		calibrationStore:  calibrationStore,
+		intrinsicsStore:   intrinsicsStore,
+		detectionPipeline: detectionPipeline,
+		checkerboards:     make(map[int][]calibration.Checkerboard),
+	}
+}
+
+// Cameras returns the device info of all cameras.
+func (g *cameraGateway) Cameras() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		var infos []device.Info
+		for _, cameraID := range g.cameraAdmin.CameraIDs() {
+			info, err := g.cameraAdmin.Info(cameraID)
+			if err != nil {
+				g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+				return
+			}
+			infos = append(infos, info)
+		}
+		g.writeJSON(w, http.StatusOK, infos)
+	}
+}
+
+// Info returns the device info (capabilities and active format) of a camera.
+func (g *cameraGateway) Info() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		info, err := g.cameraAdmin.Info(cameraID)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+		g.writeJSON(w, http.StatusOK, info)
+	}
+}
+
+// Snapshot returns the current frame of a camera as jpeg. The snapshot is used to mark the reference points of a calibration.
+func (g *cameraGateway) Snapshot() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		f, err := g.cameraAdmin.Snapshot(cameraID, snapshotTimeout)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		data, err := encodeJPEG(f)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Write(data)
+	}
+}
+
+// Stream serves the live view of a camera as mjpeg stream (multipart/x-mixed-replace).
+// With the query parameter overlay=true the annotated overlay stream (board segments, roi and detected darts) is served.
+func (g *cameraGateway) Stream() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+			return
+		}
+
+		subscriberName := "stream " + r.RemoteAddr
+		var frameCh <-chan frame.Frame
+		if withOverlay, _ := strconv.ParseBool(r.URL.Query().Get("overlay")); withOverlay {
+			frameCh, err = g.cameraAdmin.SubscribeOverlay(cameraID, subscriberName)
+			if err != nil {
+				g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+				return
+			}
+			defer g.cameraAdmin.UnsubscribeOverlay(cameraID, frameCh, subscriberName)
+		} else {
+			frameCh = g.cameraAdmin.Subscribe(cameraID, subscriberName)
+			defer g.cameraAdmin.Unsubscribe(cameraID, frameCh, subscriberName)
+		}
+
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+streamBoundary)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		for {
+			select {
+			case <-r.Context().Done():
+				// --> client has closed the connection
+				return
+			case f, ok := <-frameCh:
+				if !ok {
+					// --> camera was shut down
+					return
+				}
+				if f.IsEmpty() {
+					continue
+				}
+				data, err := encodeJPEG(f)
+				if err != nil {
+					g.logger.PrintlnErr(err)
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", streamBoundary, len(data)); err != nil {
+					return
+				}
+				if _, err := w.Write(data); err != nil {
+					return
+				}
+				if _, err := w.Write([]byte("\r\n")); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// GetCalibration returns the calibration of a camera.
+func (g *cameraGateway) GetCalibration() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		c, ok := g.calibrationStore.Get(cameraID)
+		if !ok {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, fmt.Errorf("camera %d is not calibrated", cameraID))
+			return
+		}
+		g.writeJSON(w, http.StatusOK, c)
+	}
+}
+
+// Calibrate computes, validates and persists the calibration of a camera from the reference points marked in a snapshot.
+//
+// body: {"imageWidth": 1920, "imageHeight": 1080, "points": [{"name": "20/1", "image": {"x": 1012.5, "y": 130.0}}, ...]}
+func (g *cameraGateway) Calibrate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+
+		var req calibrationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, fmt.Errorf("invalid calibration request: %v", err))
+			return
+		}
+		if req.ImageWidth == 0 || req.ImageHeight == 0 {
+			// --> use the size of the current frames
+			f, err := g.cameraAdmin.Snapshot(cameraID, snapshotTimeout)
+			if err != nil {
+				g.logger.LogAndWriteHttpRequestError(w, http.StatusServiceUnavailable, err)
+				return
+			}
+			req.ImageWidth, req.ImageHeight = int(f.PixFormat.Width), int(f.PixFormat.Height)
+		}
+
+		opts, err := g.calibrationOptions(cameraID)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+		c, err := calibration.Calibrate(cameraID, req.ImageWidth, req.ImageHeight, req.Points, opts...)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		if err := g.calibrationStore.Save(c); err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+		g.logger.Printf("camera %d calibrated (reprojection error: %.2f mm)", cameraID, c.ReprojectionError)
+		g.writeJSON(w, http.StatusOK, c)
+	}
+}
+
+// AutoCalibrate detects the board in a snapshot of the camera and persists the resulting calibration.
+// The response contains the calibration and the detection (bull, ring ellipses, wire angles and confidence).
+func (g *cameraGateway) AutoCalibrate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		f, err := g.cameraAdmin.Snapshot(cameraID, snapshotTimeout)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		img, err := f.Image()
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		opts, err := g.calibrationOptions(cameraID)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+		c, detection, err := calibration.AutoCalibrate(cameraID, img, calibration.DefaultDetectionConfig(), opts...)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		if err := g.calibrationStore.Save(c); err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+		g.logger.Printf("camera %d calibrated automatically (confidence: %.2f, reprojection error: %.2f mm)", cameraID, detection.Confidence, c.ReprojectionError)
+		g.writeJSON(w, http.StatusOK, autoCalibrationResponse{Calibration: c, Detection: detection})
+	}
+}
+
+// DeleteCalibration removes the calibration of a camera.
+func (g *cameraGateway) DeleteCalibration() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		if err := g.calibrationStore.Delete(cameraID); err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// AddCheckerboard detects a checkerboard in a snapshot of the camera and collects it for the lens calibration.
+// The size of the checkerboard (inner corners) is set by the query parameters columns and rows (default: 9x6).
+// The checkerboard should be moved between the snapshots, so the corners cover the whole image (especially the borders).
+func (g *cameraGateway) AddCheckerboard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		columns, err := queryInt(r, "columns", 9)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, err)
+			return
+		}
+		rows, err := queryInt(r, "rows", 6)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, err)
+			return
+		}
+		if g.cameraAdmin.ROI(cameraID) != (v4l2.Rect{}) {
+			// --> the lens distortion refers to the full camera frame
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusConflict, fmt.Errorf("camera %d: reset the region of interest for the lens calibration", cameraID))
+			return
+		}
+
+		f, err := g.cameraAdmin.Snapshot(cameraID, snapshotTimeout)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		img, err := f.Image()
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+		checkerboard, err := calibration.FindCheckerboard(img, columns, rows)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+
+		g.checkerboardsMu.Lock()
+		g.checkerboards[cameraID] = append(g.checkerboards[cameraID], checkerboard)
+		snapshots := len(g.checkerboards[cameraID])
+		g.checkerboardsMu.Unlock()
+
+		g.writeJSON(w, http.StatusOK, checkerboardResponse{Snapshots: snapshots, Required: calibration.MinCheckerboardSnapshots, Checkerboard: checkerboard})
+	}
+}
+
+// CalibrateIntrinsics computes the lens distortion from the collected checkerboard snapshots and persists it for the camera device.
+// An existing board calibration of the camera is recomputed with the new lens distortion.
+func (g *cameraGateway) CalibrateIntrinsics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		identity, err := g.cameraAdmin.Identity(cameraID)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		f, err := g.cameraAdmin.Snapshot(cameraID, snapshotTimeout)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+
+		g.checkerboardsMu.Lock()
+		checkerboards := g.checkerboards[cameraID]
+		g.checkerboardsMu.Unlock()
+
+		intrinsics, err := calibration.CalibrateIntrinsics(identity, int(f.PixFormat.Width), int(f.PixFormat.Height), checkerboards)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		if err := g.intrinsicsStore.Save(intrinsics); err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+		g.checkerboardsMu.Lock()
+		delete(g.checkerboards, cameraID)
+		g.checkerboardsMu.Unlock()
+
+		g.logger.Printf("camera %d: lens calibrated (%s, k1: %.4f, k2: %.4f, p1: %.4f, p2: %.4f, error: %.2f px, max. correction: %.1f px)",
+			cameraID, identity, intrinsics.K1, intrinsics.K2, intrinsics.P1, intrinsics.P2, intrinsics.Error, intrinsics.MaxShift)
+		g.recalibrate(cameraID)
+		g.writeJSON(w, http.StatusOK, intrinsics)
+	}
+}
+
+// GetIntrinsics returns the lens distortion of a camera.
+func (g *cameraGateway) GetIntrinsics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		identity, err := g.cameraAdmin.Identity(cameraID)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		intrinsics, ok := g.intrinsicsStore.Get(identity)
+		if !ok {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, fmt.Errorf("the lens of camera %d (%s) is not calibrated", cameraID, identity))
+			return
+		}
+		g.writeJSON(w, http.StatusOK, intrinsics)
+	}
+}
+
+// DeleteIntrinsics removes the lens distortion and the collected checkerboard snapshots of a camera.
+// An existing board calibration of the camera is recomputed without lens distortion.
+func (g *cameraGateway) DeleteIntrinsics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		cameraID, err := g.cameraID(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		g.checkerboardsMu.Lock()
+		delete(g.checkerboards, cameraID)
+		g.checkerboardsMu.Unlock()
+
+		identity, err := g.cameraAdmin.Identity(cameraID)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		if err := g.intrinsicsStore.Delete(identity); err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, err)
+			return
+		}
+		g.recalibrate(cameraID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Takeout signals manually that the darts were removed from the board (e.g. if the automatic takeout detection failed).
+func (g *cameraGateway) Takeout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		if g.detectionPipeline == nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusServiceUnavailable, fmt.Errorf("detection pipeline is not running"))
+			return
+		}
+		g.detectionPipeline.Takeout()
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// calibrationOptions returns the options of a board calibration, i.e. the lens distortion of the camera device if it is known.
+func (g *cameraGateway) calibrationOptions(cameraID int) ([]calibration.Option, error) {
+	identity, err := g.cameraAdmin.Identity(cameraID)
+	if err != nil {
+		return nil, err
+	}
+	intrinsics, ok := g.intrinsicsStore.Get(identity)
+	if !ok {
+		return nil, nil
+	}
+	roi := g.cameraAdmin.ROI(cameraID)
+	return []calibration.Option{calibration.WithIntrinsics(intrinsics, calibration.Point{X: float64(roi.Left), Y: float64(roi.Top)})}, nil
+}
+
+// recalibrate recomputes the board calibration of a camera from its reference points after the lens distortion changed.
+// If the recomputed calibration is invalid, the previous calibration is kept.
+func (g *cameraGateway) recalibrate(cameraID int) {
+	previous, ok := g.calibrationStore.Get(cameraID)
+	if !ok {
+		return
+	}
+	opts, err := g.calibrationOptions(cameraID)
+	if err != nil {
+		g.logger.PrintlnErr(err)
+		return
+	}
+	correspondences := append([]calibration.Correspondence(nil), previous.Correspondences...)
+	c, err := calibration.Calibrate(cameraID, previous.ImageWidth, previous.ImageHeight, correspondences, opts...)
+	if err != nil {
+		g.logger.PrintfErr("camera %d: board calibration not updated to the lens calibration, please recalibrate: %v", cameraID, err)
+		return
+	}
+	if err := g.calibrationStore.Save(c); err != nil {
+		g.logger.PrintlnErr(err)
+		return
+	}
+	g.logger.Printf("camera %d: board calibration updated (reprojection error: %.2f mm -> %.2f mm)", cameraID, previous.ReprojectionError, c.ReprojectionError)
+}
+
+// cameraID returns the camera-id of the request path and checks that the camera exists.
+func (g *cameraGateway) cameraID(r *http.Request) (int, error) {
+	cameraID, err := strconv.Atoi(mux.Vars(r)["cameraID"])
+	if err != nil {
+		return 0, fmt.Errorf("invalid camera-id: %v", err)
+	}
+	for _, id := range g.cameraAdmin.CameraIDs() {
+		if id == cameraID {
+			return cameraID, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown camera-id: %d", cameraID)
+}
+
+// queryInt returns an integer query parameter or the default value if it is not set.
+func queryInt(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid query parameter %s: %v", name, err)
+	}
+	return i, nil
+}
+
+// writeJSON writes the hand-overed value as json response.
+func (g *cameraGateway) writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, fmt.Errorf("JSON Marshal Error: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// encodeJPEG returns the frame as jpeg. MJPEG frames are returned without re-encoding.
+func encodeJPEG(f frame.Frame) ([]byte, error) {
+	if f.PixFormat.PixelFormat == v4l2.PixelFmtMJPEG || f.PixFormat.PixelFormat == v4l2.PixelFmtJPEG {
+		return f.Data, nil
+	}
+	img, err := f.Image()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, fmt.Errorf("encoding jpeg: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,241 @@
+package checkout
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MaxDarts is the number of darts of a visit.
+const MaxDarts = 3
+
+// bullSegment is the segment of the outer (single) and inner (double) bull.
+const bullSegment = 25
+
+// Rule is the out-rule a checkout has to satisfy.
+type Rule string
+
+const (
+	// RuleSingle finishes with every dart.
+	RuleSingle Rule = "single"
+	// RuleDouble finishes with a double (including the bull).
+	RuleDouble Rule = "double"
+	// RuleMaster finishes with a double or a treble (including the bull).
+	RuleMaster Rule = "master"
+)
+
+// DefaultDoubles is the order of the finishing doubles if no favourite double fits: doubles that leave a double after a
+// single hit (D20, D16, D8, ...) come first, the bull comes last.
+var DefaultDoubles = []int{20, 16, 8, 12, 10, 18, 4, 6, 14, 2, 3, 5, 7, 9, 11, 13, 15, 17, 19, 1, bullSegment}
+
+// Dart is a dart of a checkout.
+type Dart struct {
+	Segment    int `json:"segment"`    // 1-20, 25 for the bull
+	Multiplier int `json:"multiplier"` // 1-3
+}
+
+// Value returns the points of the dart.
+func (d Dart) Value() int {
+	return d.Segment * d.Multiplier
+}
+
+// String returns the label of the dart like "T20", "D16", "S5", "25" (outer bull) or "BULL".
+func (d Dart) String() string {
+	switch {
+	case d.Segment == bullSegment && d.Multiplier == 2:
+		return "BULL"
+	case d.Segment == bullSegment:
+		return "25"
+	case d.Multiplier == 3:
+		return fmt.Sprintf("T%d", d.Segment)
+	case d.Multiplier == 2:
+		return fmt.Sprintf("D%d", d.Segment)
+	default:
+		return fmt.Sprintf("S%d", d.Segment)
+	}
+}
+
+// Options configures the checkout calculation.
+type Options struct {
+	Out Rule `json:"out"`
+	// FavouriteDoubles are the preferred finishing doubles (segments, 25 for the bull), the most preferred first.
+	FavouriteDoubles []int `json:"favouriteDoubles,omitempty"`
+}
+
+// Validate checks the options. A missing out-rule defaults to double-out.
+func (o *Options) Validate() error {
+	switch o.Out {
+	case "":
+		o.Out = RuleDouble
+	case RuleSingle, RuleDouble, RuleMaster:
+	default:
+		return fmt.Errorf("invalid out-rule %q", o.Out)
+	}
+	for _, segment := range o.FavouriteDoubles {
+		if (segment < 1 || segment > 20) && segment != bullSegment {
+			return fmt.Errorf("invalid favourite double %d", segment)
+		}
+	}
+	return nil
+}
+
+// Suggest returns the recommended checkout of the score with the hand-overed number of darts. A checkout with fewer darts
+// is always preferred; among the checkouts with the same number of darts the finishing dart follows the favourite doubles
+// (then the default doubles) and the setup darts prefer singles and high trebles. It returns false if the score cannot be
+// finished with the darts (e.g. 169 or a score above 170).
+func Suggest(score, darts int, options Options) ([]Dart, bool) {
+	if err := options.Validate(); err != nil || score < 1 || darts < 1 {
+		return nil, false
+	}
+	darts = min(darts, MaxDarts)
+
+	key := cacheKey{score: score, darts: darts, out: options.Out, favourites: fmt.Sprint(options.FavouriteDoubles)}
+	cacheMu.Lock()
+	suggestion, ok := cache[key]
+	cacheMu.Unlock()
+	if !ok {
+		suggestion = suggest(score, darts, options)
+		cacheMu.Lock()
+		cache[key] = suggestion
+		cacheMu.Unlock()
+	}
+	if suggestion == nil {
+		return nil, false
+	}
+	return append([]Dart(nil), suggestion...), true
+}
+
+// cacheKey identifies a calculated suggestion.
+type cacheKey struct {
+	score      int
+	darts      int
+	out        Rule
+	favourites string
+}
+
+// the suggestions are cached since the state of a game is requested after every dart
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[cacheKey][]Dart)
+)
+
+// suggest calculates the checkout of the score with the hand-overed number of darts or returns nil.
+func suggest(score, darts int, options Options) []Dart {
+	if score > darts*60 {
+		return nil
+	}
+	finishes := finishingDarts(options)
+	setups := setupDarts()
+	for n := 1; n <= darts; n++ {
+		var best []Dart
+		bestCost := 0.0
+		for _, finish := range finishes {
+			rest := score - finish.dart.Value()
+			if rest < 0 {
+				continue
+			}
+			for _, setup := range combinations(setups, rest, n-1) {
+				cost := finish.cost
+				for _, d := range setup {
+					cost += setupCost(d)
+				}
+				if best == nil || cost < bestCost {
+					best = append(append([]Dart(nil), setup...), finish.dart)
+					bestCost = cost
+				}
+			}
+		}
+		if best != nil {
+			return best
+		}
+	}
+	return nil
+}
+
+// finish is a finishing dart with its cost (lower is preferred).
+type finish struct {
+	dart Dart
+	cost float64
+}
+
+// finishingDarts returns the darts that satisfy the out-rule ordered by preference.
+func finishingDarts(options Options) []finish {
+	rank := make(map[int]float64)
+	for i, segment := range options.FavouriteDoubles {
+		if _, ok := rank[segment]; !ok {
+			rank[segment] = float64(i) * 0.01
+		}
+	}
+	for i, segment := range DefaultDoubles {
+		if _, ok := rank[segment]; !ok {
+			rank[segment] = 1 + float64(i)*0.1
+		}
+	}
+
+	var finishes []finish
+	for _, d := range allDarts() {
+		switch {
+		case d.Multiplier == 2:
+			finishes = append(finishes, finish{dart: d, cost: rank[d.Segment]})
+		case d.Multiplier == 3 && options.Out == RuleMaster:
+			finishes = append(finishes, finish{dart: d, cost: 4 + float64(20-d.Segment)*0.01})
+		case options.Out == RuleSingle:
+			finishes = append(finishes, finish{dart: d, cost: 4 + setupCost(d)})
+		}
+	}
+	sort.SliceStable(finishes, func(i, j int) bool { return finishes[i].cost < finishes[j].cost })
+	return finishes
+}
+
+// setupCost rates a dart that sets up the finish: singles are the safest, high trebles are preferred over low trebles,
+// the bull and doubles are a last resort.
+func setupCost(d Dart) float64 {
+	switch {
+	case d.Segment == bullSegment:
+		return 5
+	case d.Multiplier == 1:
+		return 1
+	case d.Multiplier == 3:
+		return 2 + float64(20-d.Segment)*0.05
+	default:
+		return 6
+	}
+}
+
+// setupDarts returns all darts ordered by value (highest first).
+func setupDarts() []Dart {
+	darts := allDarts()
+	sort.SliceStable(darts, func(i, j int) bool { return darts[i].Value() > darts[j].Value() })
+	return darts
+}
+
+// combinations returns all combinations of n darts (ordered by value, highest first) that score the hand-overed points.
+func combinations(darts []Dart, points, n int) [][]Dart {
+	if n == 0 {
+		if points == 0 {
+			return [][]Dart{nil}
+		}
+		return nil
+	}
+	var result [][]Dart
+	for i, d := range darts {
+		if d.Value() > points {
+			continue
+		}
+		for _, rest := range combinations(darts[i:], points-d.Value(), n-1) {
+			result = append(result, append([]Dart{d}, rest...))
+		}
+	}
+	return result
+}
+
+// allDarts returns every dart that scores on the board.
+func allDarts() []Dart {
+	var darts []Dart
+	for segment := 1; segment <= 20; segment++ {
+		for multiplier := 1; multiplier <= 3; multiplier++ {
+			darts = append(darts, Dart{Segment: segment, Multiplier: multiplier})
+		}
+	}
+	return append(darts, Dart{Segment: bullSegment, Multiplier: 1}, Dart{Segment: bullSegment, Multiplier: 2})
+}
@@ -0,0 +1,106 @@
+package checkout
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		score   int
+		darts   int
+		options Options
+		want    string // labels of the checkout, empty if the score cannot be finished
+	}{
+		// the big finishes
+		{score: 170, darts: 3, want: "[T20 T20 BULL]"},
+		{score: 167, darts: 3, want: "[T20 T19 BULL]"},
+		{score: 121, darts: 3, want: "[T18 T17 D8]"},
+		{score: 101, darts: 3, want: "[T17 BULL]"},
+		{score: 100, darts: 3, want: "[T20 D20]"},
+		{score: 81, darts: 3, want: "[T19 D12]"},
+		// fewer darts are preferred, then the default doubles
+		{score: 40, darts: 3, want: "[D20]"},
+		{score: 32, darts: 3, want: "[D16]"},
+		{score: 50, darts: 1, want: "[BULL]"},
+		{score: 60, darts: 3, want: "[S20 D20]"},
+		{score: 52, darts: 3, want: "[S12 D20]"},
+		{score: 41, darts: 3, want: "[S1 D20]"},
+		{score: 25, darts: 2, want: "[S9 D8]"},
+		{score: 3, darts: 3, want: "[S1 D1]"},
+		{score: 2, darts: 1, want: "[D1]"},
+		// favourite doubles
+		{score: 52, darts: 3, options: Options{FavouriteDoubles: []int{16}}, want: "[S20 D16]"},
+		{score: 40, darts: 3, options: Options{FavouriteDoubles: []int{10}}, want: "[D20]"},
+		// out-rules
+		{score: 60, darts: 1, options: Options{Out: RuleMaster}, want: "[T20]"},
+		{score: 59, darts: 2, options: Options{Out: RuleMaster}, want: "[S19 D20]"},
+		{score: 57, darts: 1, options: Options{Out: RuleSingle}, want: "[T19]"},
+		{score: 7, darts: 1, options: Options{Out: RuleSingle}, want: "[S7]"},
+		{score: 180, darts: 3, options: Options{Out: RuleSingle}, want: "[T20 T20 T20]"},
+		// no checkout
+		{score: 169, darts: 3},
+		{score: 171, darts: 3},
+		{score: 100, darts: 1},
+		{score: 41, darts: 1},
+		{score: 1, darts: 3},
+		{score: 0, darts: 3},
+		{score: 50, darts: 0},
+		{score: 50, darts: 3, options: Options{Out: "triple"}},
+	}
+	for _, tt := range tests {
+		suggestion, ok := Suggest(tt.score, tt.darts, tt.options)
+		got := ""
+		if ok {
+			got = fmt.Sprint(suggestion)
+		}
+		if got != tt.want {
+			t.Errorf("Suggest(%d, %d, %+v) = %q, want %q", tt.score, tt.darts, tt.options, got, tt.want)
+		}
+	}
+}
+
+// every suggestion has to finish the score with the out-rule
+func TestSuggestFinishes(t *testing.T) {
+	for _, out := range []Rule{RuleSingle, RuleDouble, RuleMaster} {
+		for score := 1; score <= 180; score++ {
+			suggestion, ok := Suggest(score, MaxDarts, Options{Out: out})
+			if !ok {
+				continue
+			}
+			total := 0
+			for _, d := range suggestion {
+				total += d.Value()
+			}
+			last := suggestion[len(suggestion)-1]
+			if total != score {
+				t.Errorf("%s-out %d: %v scores %d", out, score, suggestion, total)
+			}
+			if out == RuleDouble && last.Multiplier != 2 || out == RuleMaster && last.Multiplier < 2 {
+				t.Errorf("%s-out %d: %v does not finish with the out-rule", out, score, suggestion)
+			}
+		}
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		options Options
+		valid   bool
+	}{
+		{options: Options{}, valid: true},
+		{options: Options{Out: RuleMaster, FavouriteDoubles: []int{16, 25}}, valid: true},
+		{options: Options{Out: "triple"}},
+		{options: Options{FavouriteDoubles: []int{21}}},
+		{options: Options{FavouriteDoubles: []int{0}}},
+	}
+	for _, tt := range tests {
+		options := tt.options
+		if err := options.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate(%+v) = %v, valid: %v", tt.options, err, tt.valid)
+		}
+		if tt.valid && options.Out == "" {
+			t.Errorf("Validate(%+v) did not default the out-rule", tt.options)
+		}
+	}
+}
@@ -9,12 +9,25 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func NewServer(logger *dartmasterlogger.DartmasterLogger, port string) *http.Server {
+func NewServer(logger *dartmasterlogger.DartmasterLogger, detectionPipeline gateway.DetectionPipeline, matchStore gateway.MatchStore, port string) *http.Server {
 	router := mux.NewRouter()
-	dartcounterGateway := gateway.NewDartcounterGateway(logger)
+	dartcounterGateway := gateway.NewDartcounterGateway(logger, detectionPipeline, matchStore)
 
 	// initiate dartcounter uris
 	router.Path("/dartcounter/sse").HandlerFunc(dartcounterGateway.SSE()).Methods(http.MethodGet)
+	router.Path("/dartcounter/modes").HandlerFunc(dartcounterGateway.GameModes()).Methods(http.MethodGet)
+	router.Path("/dartcounter/matches").HandlerFunc(dartcounterGateway.Matches()).Methods(http.MethodGet)
+	router.Path("/dartcounter/matches").HandlerFunc(dartcounterGateway.CreateMatch()).Methods(http.MethodPost)
+	router.Path("/dartcounter/matches/{matchID:[0-9]+}").HandlerFunc(dartcounterGateway.GetMatch()).Methods(http.MethodGet)
+	router.Path("/dartcounter/matches/{matchID:[0-9]+}/log").HandlerFunc(dartcounterGateway.MatchLog()).Methods(http.MethodGet)
+	router.Path("/dartcounter/matches/{matchID:[0-9]+}/inputs").HandlerFunc(dartcounterGateway.AllowedInputs()).Methods(http.MethodGet)
+	router.Path("/dartcounter/matches/{matchID:[0-9]+}/throws").HandlerFunc(dartcounterGateway.SubmitThrows()).Methods(http.MethodPost)
+	router.Path("/dartcounter/matches/{matchID:[0-9]+}/undo").HandlerFunc(dartcounterGateway.UndoMatchThrow()).Methods(http.MethodPost)
+	router.Path("/dartcounter/matches/{matchID:[0-9]+}/correction").HandlerFunc(dartcounterGateway.CorrectMatchThrow()).Methods(http.MethodPost)
+	router.Path("/dartcounter/matches/{matchID:[0-9]+}/abandon").HandlerFunc(dartcounterGateway.AbandonMatch()).Methods(http.MethodPost)
+	router.Path("/dartcounter/matches/{matchID:[0-9]+}/pending").HandlerFunc(dartcounterGateway.PendingThrows()).Methods(http.MethodGet)
+	router.Path("/dartcounter/matches/{matchID:[0-9]+}/pending/confirm").HandlerFunc(dartcounterGateway.ConfirmThrow()).Methods(http.MethodPost)
+	router.Path("/dartcounter/matches/{matchID:[0-9]+}/pending/reject").HandlerFunc(dartcounterGateway.RejectThrow()).Methods(http.MethodPost)
 
 	// initiate http server
 	httpServer := utils.NewHttpServer(router, port)
@@ -0,0 +1,140 @@
+package gameengine
+
+import (
+	"fmt"
+)
+
+// AroundTheClockOptions configures a game of around the clock.
+type AroundTheClockOptions struct {
+	IncludeBull     bool `json:"includeBull"`     // the bull is the last target
+	MultiplierSkips bool `json:"multiplierSkips"` // a double (treble) advances by two (three) targets
+}
+
+// AroundTheClockPlayerState is the state of a player of around the clock.
+type AroundTheClockPlayerState struct {
+	Name        string  `json:"name"`
+	Target      int     `json:"target"`   // segment the player has to hit next, 0 if the player is through
+	Progress    int     `json:"progress"` // number of hit targets
+	Hits        int     `json:"hits"`
+	DartsThrown int     `json:"dartsThrown"`
+	HitRate     float64 `json:"hitRate"` // hits per dart
+}
+
+// AroundTheClockState contains the details of an around the clock snapshot.
+type AroundTheClockState struct {
+	Options AroundTheClockOptions       `json:"options"`
+	Targets []int                       `json:"targets"`
+	Players []AroundTheClockPlayerState `json:"players"`
+}
+
+// AroundTheClock is played on the numbers 1 to 20 in order (optionally followed by the bull). The first player who hit all targets wins.
+type AroundTheClock struct {
+	turn
+	names   []string
+	options AroundTheClockOptions
+	targets []int
+	players []AroundTheClockPlayerState
+}
+
+// NewAroundTheClock starts a game of around the clock for the hand-overed players.
+func NewAroundTheClock(players []string, options AroundTheClockOptions) (*AroundTheClock, error) {
+	if err := validatePlayers(players); err != nil {
+		return nil, fmt.Errorf("NewAroundTheClock() - error: %v", err)
+	}
+	g := &AroundTheClock{
+		turn:    newTurn(len(players)),
+		names:   append([]string(nil), players...),
+		options: options,
+	}
+	for segment := 1; segment <= 20; segment++ {
+		g.targets = append(g.targets, segment)
+	}
+	if options.IncludeBull {
+		g.targets = append(g.targets, BullSegment)
+	}
+	for _, name := range players {
+		g.players = append(g.players, AroundTheClockPlayerState{Name: name, Target: g.targets[0]})
+	}
+	return g, nil
+}
+
+// Mode returns the game mode.
+func (g *AroundTheClock) Mode() Mode {
+	return ModeAroundTheClock
+}
+
+// Throw counts a dart of the current player. A hit on the target advances the player to the next target.
+func (g *AroundTheClock) Throw(d Dart) ([]Event, error) {
+	if err := g.checkThrow(d); err != nil {
+		return nil, err
+	}
+
+	p := &g.players[g.current]
+	g.startThrow(d)
+	p.DartsThrown++
+
+	marks := 0
+	if !d.IsMiss() && d.Segment == p.Target {
+		steps := 1
+		if g.options.MultiplierSkips && d.Segment != BullSegment {
+			steps = d.Multiplier
+		}
+		p.Progress = min(p.Progress+steps, len(g.targets))
+		p.Hits++
+		p.Target = 0
+		if p.Progress < len(g.targets) {
+			p.Target = g.targets[p.Progress]
+		}
+		marks = steps
+	}
+	p.HitRate = float64(p.Hits) / float64(p.DartsThrown)
+
+	events := []Event{g.throwEvent(d, 0, marks)}
+	if p.Progress == len(g.targets) {
+		return append(events, g.win(g.current)...), nil
+	}
+	return append(events, g.endVisitIfComplete()...), nil
+}
+
+// EndVisit ends the running visit early. The missing darts count as misses.
+func (g *AroundTheClock) EndVisit() ([]Event, error) {
+	misses, ok, err := g.checkEndVisit()
+	if !ok {
+		return nil, err
+	}
+	p := &g.players[g.current]
+	p.DartsThrown += misses
+	p.HitRate = float64(p.Hits) / float64(p.DartsThrown)
+	return g.endVisit(), nil
+}
+
+// Undo reverts the last dart (or the early end of a visit).
+func (g *AroundTheClock) Undo() ([]Event, error) {
+	fresh, err := NewAroundTheClock(g.names, g.options)
+	if err != nil {
+		return nil, err
+	}
+	event, err := g.undo(fresh)
+	if err != nil {
+		return nil, err
+	}
+	*g = *fresh
+	return []Event{event}, nil
+}
+
+// State returns a snapshot of the game.
+func (g *AroundTheClock) State() State {
+	return g.state(ModeAroundTheClock, AroundTheClockState{
+		Options: g.options,
+		Targets: append([]int(nil), g.targets...),
+		Players: append([]AroundTheClockPlayerState(nil), g.players...),
+	})
+}
+
+// AllowedInputs returns the darts on the target of the current player.
+func (g *AroundTheClock) AllowedInputs() []Dart {
+	if g.over {
+		return nil
+	}
+	return segmentDarts(g.players[g.current].Target)
+}
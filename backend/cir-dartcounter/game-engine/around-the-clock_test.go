@@ -0,0 +1,62 @@
+package gameengine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAroundTheClock(t *testing.T) {
+	tests := []struct {
+		name    string
+		options AroundTheClockOptions
+		darts   string
+		targets []int // next target per player
+		winner  int   // -1 if the game is running
+	}{
+		{"hits in order", AroundTheClockOptions{}, "S1 T2 S4", []int{3, 1}, -1},
+		{"multiplier skips", AroundTheClockOptions{MultiplierSkips: true}, "S1 D2 T4 | S1", []int{7, 2}, -1},
+		{"through with a treble", AroundTheClockOptions{MultiplierSkips: true},
+			"S1 D2 T4 | MISS | T7 T10 T13 | MISS | T16 T19", []int{0, 1}, 0},
+		{"misses", AroundTheClockOptions{}, "MISS S2 S20 | S1", []int{1, 2}, -1},
+	}
+	for _, tt := range tests {
+		g, err := NewAroundTheClock([]string{"A", "B"}, tt.options)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		play(t, g, tt.darts)
+		var targets []int
+		for _, player := range g.State().Details.(AroundTheClockState).Players {
+			targets = append(targets, player.Target)
+		}
+		if !reflect.DeepEqual(targets, tt.targets) {
+			t.Errorf("%s: targets %v, want %v", tt.name, targets, tt.targets)
+		}
+		winner, won := g.Winner()
+		if !won {
+			winner = -1
+		}
+		if winner != tt.winner {
+			t.Errorf("%s: winner %d, want %d", tt.name, winner, tt.winner)
+		}
+	}
+}
+
+func TestAroundTheClockBull(t *testing.T) {
+	g, _ := NewAroundTheClock([]string{"A"}, AroundTheClockOptions{IncludeBull: true})
+	for segment := 1; segment <= 20; segment++ {
+		if _, err := g.Throw(Dart{Segment: segment, Multiplier: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, won := g.Winner(); won {
+		t.Fatal("won without the bull")
+	}
+	if target := g.State().Details.(AroundTheClockState).Players[0].Target; target != BullSegment {
+		t.Fatalf("target %d, want the bull", target)
+	}
+	play(t, g, "25")
+	if w, won := g.Winner(); !won || w != 0 {
+		t.Errorf("winner %d (%v) after the bull", w, won)
+	}
+}
@@ -0,0 +1,182 @@
+package gameengine
+
+import (
+	"fmt"
+	"math"
+)
+
+// Bobs27StartScore is the score every player starts with.
+const Bobs27StartScore = 27
+
+// Bobs27Options configures a game of Bob's 27.
+type Bobs27Options struct {
+	IncludeBull bool `json:"includeBull"` // the bull is the last round
+}
+
+// Bobs27PlayerState is the state of a player of Bob's 27.
+type Bobs27PlayerState struct {
+	Name        string `json:"name"`
+	Score       int    `json:"score"`
+	Target      int    `json:"target"` // segment of the double the player plays next, 0 if the player is through
+	Hits        int    `json:"hits"`
+	Eliminated  bool   `json:"eliminated"`
+	DartsThrown int    `json:"dartsThrown"`
+}
+
+// Bobs27State contains the details of a Bob's 27 snapshot.
+type Bobs27State struct {
+	Options Bobs27Options       `json:"options"`
+	Targets []int               `json:"targets"`
+	Players []Bobs27PlayerState `json:"players"`
+}
+
+// Bobs27 is a doubles training: round n is played on double n. Every hit adds the value of the double; a visit without a hit
+// subtracts it. A player whose score drops to zero or below is out. A solo player wins by surviving all rounds; with more
+// players the last player in the game (or the highest score after the last round) wins.
+type Bobs27 struct {
+	turn
+	names   []string
+	options Bobs27Options
+	targets []int
+	players []Bobs27PlayerState
+	rounds  []int // completed rounds per player
+	hits    int   // hits of the running visit
+}
+
+// NewBobs27 starts a game of Bob's 27 for the hand-overed players.
+func NewBobs27(players []string, options Bobs27Options) (*Bobs27, error) {
+	if err := validatePlayers(players); err != nil {
+		return nil, fmt.Errorf("NewBobs27() - error: %v", err)
+	}
+	g := &Bobs27{
+		turn:    newTurn(len(players)),
+		names:   append([]string(nil), players...),
+		options: options,
+		rounds:  make([]int, len(players)),
+	}
+	for segment := 1; segment <= 20; segment++ {
+		g.targets = append(g.targets, segment)
+	}
+	if options.IncludeBull {
+		g.targets = append(g.targets, BullSegment)
+	}
+	for _, name := range players {
+		g.players = append(g.players, Bobs27PlayerState{Name: name, Score: Bobs27StartScore, Target: g.targets[0]})
+	}
+	return g, nil
+}
+
+// Mode returns the game mode.
+func (g *Bobs27) Mode() Mode {
+	return ModeBobs27
+}
+
+// Throw counts a dart of the current player. Only the double of the round counts.
+func (g *Bobs27) Throw(d Dart) ([]Event, error) {
+	if err := g.checkThrow(d); err != nil {
+		return nil, err
+	}
+
+	p := &g.players[g.current]
+	g.startThrow(d)
+	p.DartsThrown++
+
+	points := 0
+	if d.IsDouble() && d.Segment == p.Target {
+		points = d.Value()
+		p.Hits++
+		g.hits++
+	}
+	p.Score += points
+	g.visitPoints += points
+
+	events := []Event{g.throwEvent(d, points, 0)}
+	if len(g.visit) == DartsPerVisit {
+		events = append(events, g.completeVisit()...)
+	}
+	return events, nil
+}
+
+// EndVisit ends the running visit early. The missing darts count as misses.
+func (g *Bobs27) EndVisit() ([]Event, error) {
+	misses, ok, err := g.checkEndVisit()
+	if !ok {
+		return nil, err
+	}
+	g.players[g.current].DartsThrown += misses
+	return g.completeVisit(), nil
+}
+
+// Undo reverts the last dart (or the early end of a visit).
+func (g *Bobs27) Undo() ([]Event, error) {
+	fresh, err := NewBobs27(g.names, g.options)
+	if err != nil {
+		return nil, err
+	}
+	event, err := g.undo(fresh)
+	if err != nil {
+		return nil, err
+	}
+	*g = *fresh
+	return []Event{event}, nil
+}
+
+// State returns a snapshot of the game.
+func (g *Bobs27) State() State {
+	return g.state(ModeBobs27, Bobs27State{
+		Options: g.options,
+		Targets: append([]int(nil), g.targets...),
+		Players: append([]Bobs27PlayerState(nil), g.players...),
+	})
+}
+
+// AllowedInputs returns the double of the round of the current player.
+func (g *Bobs27) AllowedInputs() []Dart {
+	if g.over {
+		return nil
+	}
+	return []Dart{{Segment: g.players[g.current].Target, Multiplier: 2}}
+}
+
+// completeVisit subtracts the double if the visit missed it, eliminates a player without points and moves the current player
+// to the next round.
+func (g *Bobs27) completeVisit() []Event {
+	p := &g.players[g.current]
+	if g.hits == 0 {
+		lost := Dart{Segment: p.Target, Multiplier: 2}.Value()
+		p.Score -= lost
+		g.visitPoints = -lost
+	}
+	g.hits = 0
+	g.rounds[g.current]++
+	p.Target = 0
+	if g.rounds[g.current] < len(g.targets) {
+		p.Target = g.targets[g.rounds[g.current]]
+	}
+
+	var events []Event
+	if p.Score <= 0 {
+		p.Eliminated = true
+		g.eliminated[g.current] = true
+		events = append(events, Event{Type: EventPlayerEliminated, Player: g.current})
+	}
+
+	remaining := g.remaining()
+	switch {
+	case len(remaining) == 0:
+		return append(events, g.finish()...)
+	case len(remaining) == 1 && len(g.players) > 1:
+		return append(events, g.win(remaining[0])...)
+	}
+	for _, i := range remaining {
+		if g.rounds[i] < len(g.targets) {
+			return append(events, g.endVisit()...)
+		}
+	}
+	return append(events, g.win(g.highest(func(i int) int {
+		if g.players[i].Eliminated {
+			return math.MinInt
+		}
+		return g.players[i].Score
+	}))...)
+}
@@ -0,0 +1,45 @@
+package gameengine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBobs27(t *testing.T) {
+	tests := []struct {
+		name     string
+		players  int
+		darts    string
+		score    int // score of the first player
+		target   int // next double of the first player
+		finished bool
+		winner   int // -1 if there is no winner
+	}{
+		{"hits add the double", 1, "D1 D1 MISS", 31, 2, false, -1},
+		{"a visit without a hit subtracts the double", 1, "D1 D1 MISS | MISS |", 27, 3, false, -1},
+		{"solo player out", 1, "D1 D1 MISS | MISS | " + strings.Repeat("MISS ", 12), -9, 7, true, -1},
+		{"last player in the game wins", 2, "D1 MISS MISS | " + strings.Repeat("MISS ", 27), 1, 6, true, 0},
+	}
+	for _, tt := range tests {
+		g, err := NewBobs27([]string{"A", "B"}[:tt.players], Bobs27Options{})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		play(t, g, tt.darts)
+		state := g.State()
+		player := state.Details.(Bobs27State).Players[0]
+		if player.Score != tt.score || player.Target != tt.target {
+			t.Errorf("%s: score %d (target %d), want %d (%d)", tt.name, player.Score, player.Target, tt.score, tt.target)
+		}
+		if state.Finished != tt.finished {
+			t.Errorf("%s: finished %v, want %v", tt.name, state.Finished, tt.finished)
+		}
+		winner, won := g.Winner()
+		if !won {
+			winner = -1
+		}
+		if winner != tt.winner {
+			t.Errorf("%s: winner %d, want %d", tt.name, winner, tt.winner)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package gameengine
+
+import (
+	"fmt"
+)
+
+// MarksToClose is the number of marks a player needs to close a cricket number.
+const MarksToClose = 3
+
+// CricketNumbers are the numbers of a cricket game.
+var CricketNumbers = []int{20, 19, 18, 17, 16, 15, BullSegment}
+
+// CricketOptions configures a cricket game.
+type CricketOptions struct {
+	// CutThroat adds the points to the opponents who have not closed the number yet; the lowest score wins.
+	CutThroat bool `json:"cutThroat"`
+}
+
+// CricketPlayerState is the state of a player of a cricket game.
+type CricketPlayerState struct {
+	Name          string  `json:"name"`
+	Score         int     `json:"score"`
+	Marks         []int   `json:"marks"` // marks per cricket number (0-3, same order as the numbers)
+	DartsThrown   int     `json:"dartsThrown"`
+	TotalMarks    int     `json:"totalMarks"`    // counted marks (closing and scoring)
+	MarksPerRound float64 `json:"marksPerRound"` // marks per visit of three darts
+}
+
+// CricketState contains the details of a cricket game snapshot.
+type CricketState struct {
+	Options CricketOptions       `json:"options"`
+	Numbers []int                `json:"numbers"`
+	Closed  []bool               `json:"closed"` // numbers closed by all players (no more points possible)
+	Players []CricketPlayerState `json:"players"`
+}
+
+// Cricket counts a leg of cricket (15-20 and the bull). Three marks close a number; further marks on a closed number score
+// its value as long as an opponent has not closed it. The player who closed all numbers with the highest score wins
+// (with cut-throat: the lowest score).
+type Cricket struct {
+	turn
+	names   []string
+	options CricketOptions
+	players []CricketPlayerState
+}
+
+// NewCricket starts a cricket game for the hand-overed players.
+func NewCricket(players []string, options CricketOptions) (*Cricket, error) {
+	if err := validatePlayers(players); err != nil {
+		return nil, fmt.Errorf("NewCricket() - error: %v", err)
+	}
+	g := &Cricket{
+		turn:    newTurn(len(players)),
+		names:   append([]string(nil), players...),
+		options: options,
+	}
+	for _, name := range players {
+		g.players = append(g.players, CricketPlayerState{
+			Name:  name,
+			Marks: make([]int, len(CricketNumbers)),
+		})
+	}
+	return g, nil
+}
+
+// Mode returns the game mode.
+func (g *Cricket) Mode() Mode {
+	if g.options.CutThroat {
+		return ModeCutThroat
+	}
+	return ModeCricket
+}
+
+// Throw counts a dart of the current player. The visit ends after the third dart or if the player won.
+func (g *Cricket) Throw(d Dart) ([]Event, error) {
+	if err := g.checkThrow(d); err != nil {
+		return nil, err
+	}
+
+	p := &g.players[g.current]
+	g.startThrow(d)
+	p.DartsThrown++
+
+	marks, points := 0, 0
+	if idx := cricketIndex(d.Segment); idx >= 0 && !d.IsMiss() {
+		closing := min(d.Multiplier, MarksToClose-p.Marks[idx])
+		extra := d.Multiplier - closing
+		p.Marks[idx] += closing
+		marks = closing
+
+		if extra > 0 {
+			opponents := g.openOpponents(idx)
+			if len(opponents) > 0 {
+				marks += extra
+				points = extra * d.Segment
+				if g.options.CutThroat {
+					for _, o := range opponents {
+						g.players[o].Score += points
+					}
+				} else {
+					p.Score += points
+				}
+			}
+		}
+	}
+	p.TotalMarks += marks
+	p.MarksPerRound = average(p.TotalMarks, p.DartsThrown)
+	g.visitPoints += points
+
+	events := []Event{g.throwEvent(d, points, marks)}
+	if g.hasWon(g.current) {
+		return append(events, g.win(g.current)...), nil
+	}
+	return append(events, g.endVisitIfComplete()...), nil
+}
+
+// EndVisit ends the running visit early (e.g. the darts were taken out of the board). The missing darts count as misses.
+// Nothing happens if the current player has not thrown yet.
+func (g *Cricket) EndVisit() ([]Event, error) {
+	misses, ok, err := g.checkEndVisit()
+	if !ok {
+		return nil, err
+	}
+	p := &g.players[g.current]
+	p.DartsThrown += misses
+	p.MarksPerRound = average(p.TotalMarks, p.DartsThrown)
+	return g.endVisit(), nil
+}
+
+// Undo reverts the last dart (or the early end of a visit).
+func (g *Cricket) Undo() ([]Event, error) {
+	fresh, err := NewCricket(g.names, g.options)
+	if err != nil {
+		return nil, err
+	}
+	event, err := g.undo(fresh)
+	if err != nil {
+		return nil, err
+	}
+	*g = *fresh
+	return []Event{event}, nil
+}
+
+// State returns a snapshot of the game.
+func (g *Cricket) State() State {
+	players := make([]CricketPlayerState, len(g.players))
+	for i, p := range g.players {
+		players[i] = p
+		players[i].Marks = append([]int(nil), p.Marks...)
+	}
+	closed := make([]bool, len(CricketNumbers))
+	for idx := range CricketNumbers {
+		closed[idx] = g.closedByAll(idx)
+	}
+	return g.state(g.Mode(), CricketState{
+		Options: g.options,
+		Numbers: append([]int(nil), CricketNumbers...),
+		Closed:  closed,
+		Players: players,
+	})
+}
+
+// AllowedInputs returns the darts on the cricket numbers that are not closed by all players.
+func (g *Cricket) AllowedInputs() []Dart {
+	if g.over {
+		return nil
+	}
+	var darts []Dart
+	for idx, n := range CricketNumbers {
+		if !g.closedByAll(idx) {
+			darts = append(darts, segmentDarts(n)...)
+		}
+	}
+	return darts
+}
+
+// hasWon reports whether a player closed all numbers and leads the score.
+func (g *Cricket) hasWon(player int) bool {
+	p := g.players[player]
+	for _, m := range p.Marks {
+		if m < MarksToClose {
+			return false
+		}
+	}
+	for i, o := range g.players {
+		if i == player {
+			continue
+		}
+		if g.options.CutThroat && o.Score < p.Score {
+			return false
+		}
+		if !g.options.CutThroat && o.Score > p.Score {
+			return false
+		}
+	}
+	return true
+}
+
+// openOpponents returns the opponents of the current player who have not closed the number yet.
+func (g *Cricket) openOpponents(idx int) []int {
+	var opponents []int
+	for i, o := range g.players {
+		if i != g.current && o.Marks[idx] < MarksToClose {
+			opponents = append(opponents, i)
+		}
+	}
+	return opponents
+}
+
+// closedByAll reports whether all players closed the number.
+func (g *Cricket) closedByAll(idx int) bool {
+	for _, p := range g.players {
+		if p.Marks[idx] < MarksToClose {
+			return false
+		}
+	}
+	return true
+}
+
+// cricketIndex returns the index of a segment inside the cricket numbers or -1 if the segment does not count.
+func cricketIndex(segment int) int {
+	for i, n := range CricketNumbers {
+		if n == segment {
+			return i
+		}
+	}
+	return -1
+}
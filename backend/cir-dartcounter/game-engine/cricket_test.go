@@ -0,0 +1,85 @@
+package gameengine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCricket(t *testing.T) {
+	tests := []struct {
+		name     string
+		players  int
+		options  CricketOptions
+		darts    string
+		scores   []int
+		marks20  []int // marks on the 20 per player
+		closed20 bool
+		winner   int // -1 if the game is running
+	}{
+		{"closing and scoring", 2, CricketOptions{}, "T20 T20 S20", []int{80, 0}, []int{3, 0}, false, -1},
+		{"closed by all players scores no points", 2, CricketOptions{}, "T20 T20 S20 | T20 | T20", []int{80, 0}, []int{3, 3}, true, -1},
+		{"no points on numbers outside of cricket", 2, CricketOptions{}, "T1 D14 S5", []int{0, 0}, []int{0, 0}, false, -1},
+		{"cut-throat scores to the opponents", 3, CricketOptions{CutThroat: true}, "T20 T20 MISS", []int{0, 60, 60}, []int{3, 0, 0}, false, -1},
+		{"cut-throat spares opponents who closed", 3, CricketOptions{CutThroat: true},
+			"T20 T20 MISS | T20 MISS MISS | MISS MISS MISS | S20", []int{0, 60, 80}, []int{3, 3, 0}, false, -1},
+		{"all numbers closed wins", 2, CricketOptions{},
+			"T20 T19 T18 | MISS MISS MISS | T17 T16 T15 | MISS MISS MISS | BULL 25", []int{0, 0}, []int{3, 0}, false, 0},
+		{"all numbers closed but behind", 2, CricketOptions{},
+			"MISS MISS MISS | T20 T20 T20 | T20 T19 T18 | MISS MISS MISS | T17 T16 T15 | MISS MISS MISS | BULL 25",
+			[]int{0, 120}, []int{3, 3}, true, -1},
+		{"closing the gap wins", 2, CricketOptions{},
+			"MISS MISS MISS | T20 T20 T20 | T20 T19 T18 | MISS MISS MISS | T17 T16 T15 | MISS MISS MISS | BULL 25 MISS | MISS MISS MISS | T19 T19 T19",
+			[]int{171, 120}, []int{3, 3}, true, 0},
+		{"solo", 1, CricketOptions{}, "T20 T20 T19 T18 T17 T16 T15 BULL 25", []int{0}, []int{3}, true, 0},
+	}
+	for _, tt := range tests {
+		g, err := NewCricket([]string{"A", "B", "C"}[:tt.players], tt.options)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		play(t, g, tt.darts)
+		state := g.State().Details.(CricketState)
+		var scores, marks20 []int
+		for _, player := range state.Players {
+			scores = append(scores, player.Score)
+			marks20 = append(marks20, player.Marks[0])
+		}
+		if !reflect.DeepEqual(scores, tt.scores) || !reflect.DeepEqual(marks20, tt.marks20) || state.Closed[0] != tt.closed20 {
+			t.Errorf("%s: scores %v, marks on 20 %v (closed %v), want %v, %v (closed %v)", tt.name, scores, marks20, state.Closed[0],
+				tt.scores, tt.marks20, tt.closed20)
+		}
+		winner, won := g.Winner()
+		if !won {
+			winner = -1
+		}
+		if winner != tt.winner {
+			t.Errorf("%s: winner %d, want %d", tt.name, winner, tt.winner)
+		}
+	}
+}
+
+func TestCricketMarks(t *testing.T) {
+	g, _ := NewCricket([]string{"A", "B"}, CricketOptions{})
+	events := play(t, g, "T20 S20 D19")
+	var marks []int
+	for _, e := range events {
+		if e.Type == EventThrowRegistered {
+			marks = append(marks, e.Marks)
+		}
+	}
+	// --> marks on a number closed by the player count as long as the opponent has not closed it
+	if !reflect.DeepEqual(marks, []int{3, 1, 2}) {
+		t.Errorf("marks of the darts %v, want [3 1 2]", marks)
+	}
+	player := g.State().Details.(CricketState).Players[0]
+	if player.TotalMarks != 6 || player.MarksPerRound != 6 {
+		t.Errorf("total marks %d (%v per round), want 6", player.TotalMarks, player.MarksPerRound)
+	}
+	if g.Mode() != ModeCricket {
+		t.Errorf("mode %s", g.Mode())
+	}
+	cutThroat, _ := NewCricket([]string{"A"}, CricketOptions{CutThroat: true})
+	if cutThroat.Mode() != ModeCutThroat {
+		t.Errorf("cut-throat mode %s", cutThroat.Mode())
+	}
+}
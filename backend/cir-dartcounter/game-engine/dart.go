@@ -0,0 +1,125 @@
+package gameengine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+// BullSegment is the segment of the outer (single) and inner (double) bull.
+const BullSegment = 25
+
+// Dart is a single thrown dart.
+type Dart struct {
+	Segment    int `json:"segment"`    // 1-20, 25 for the bull, 0 for a miss
+	Multiplier int `json:"multiplier"` // 1-3, 0 for a miss
+}
+
+// Miss is a dart outside the scoring area (or a bounce-out).
+var Miss = Dart{}
+
+// NewDart returns a validated dart.
+func NewDart(segment, multiplier int) (Dart, error) {
+	d := Dart{Segment: segment, Multiplier: multiplier}
+	if err := d.Validate(); err != nil {
+		return Dart{}, err
+	}
+	return d, nil
+}
+
+// DartFromScore converts the score of a detected dart.
+func DartFromScore(s dartboard.Score) Dart {
+	if s.Ring == dartboard.RingMiss {
+		return Miss
+	}
+	return Dart{Segment: s.Segment, Multiplier: s.Multiplier}
+}
+
+// ParseDart parses a dart label like "T20", "D16", "S5", "5", "BULL" (or "DB"), "25" (or "SB") and "MISS" (or "0").
+func ParseDart(label string) (Dart, error) {
+	label = strings.ToUpper(strings.TrimSpace(label))
+	switch label {
+	case "MISS", "M", "0":
+		return Miss, nil
+	case "BULL", "DB", "D25", "50":
+		return Dart{Segment: BullSegment, Multiplier: 2}, nil
+	case "SB", "S25", "25":
+		return Dart{Segment: BullSegment, Multiplier: 1}, nil
+	case "":
+		return Dart{}, fmt.Errorf("ParseDart() - error: empty label")
+	}
+
+	multiplier := 1
+	number := label
+	switch label[0] {
+	case 'S':
+		number = label[1:]
+	case 'D':
+		multiplier, number = 2, label[1:]
+	case 'T':
+		multiplier, number = 3, label[1:]
+	}
+	segment, err := strconv.Atoi(number)
+	if err != nil {
+		return Dart{}, fmt.Errorf("ParseDart() - error: invalid label %q", label)
+	}
+	d := Dart{Segment: segment, Multiplier: multiplier}
+	if err := d.Validate(); err != nil {
+		return Dart{}, fmt.Errorf("ParseDart() - error: invalid label %q: %v", label, err)
+	}
+	return d, nil
+}
+
+// Validate checks that the dart exists on a board.
+func (d Dart) Validate() error {
+	switch {
+	case d == Miss:
+		return nil
+	case d.Segment >= 1 && d.Segment <= 20 && d.Multiplier >= 1 && d.Multiplier <= 3:
+		return nil
+	case d.Segment == BullSegment && (d.Multiplier == 1 || d.Multiplier == 2):
+		return nil
+	default:
+		return fmt.Errorf("invalid dart (segment: %d, multiplier: %d)", d.Segment, d.Multiplier)
+	}
+}
+
+// Value returns the points of the dart.
+func (d Dart) Value() int {
+	return d.Segment * d.Multiplier
+}
+
+// IsMiss reports whether the dart missed the scoring area.
+func (d Dart) IsMiss() bool {
+	return d.Multiplier == 0
+}
+
+// IsDouble reports whether the dart hit a double (including the inner bull).
+func (d Dart) IsDouble() bool {
+	return d.Multiplier == 2
+}
+
+// IsTreble reports whether the dart hit a treble.
+func (d Dart) IsTreble() bool {
+	return d.Multiplier == 3
+}
+
+// String returns the label of the dart, e.g. "T20", "D16", "S5", "BULL", "25" or "MISS".
+func (d Dart) String() string {
+	switch {
+	case d.IsMiss():
+		return "MISS"
+	case d.Segment == BullSegment && d.Multiplier == 2:
+		return "BULL"
+	case d.Segment == BullSegment:
+		return "25"
+	case d.Multiplier == 3:
+		return fmt.Sprintf("T%d", d.Segment)
+	case d.Multiplier == 2:
+		return fmt.Sprintf("D%d", d.Segment)
+	default:
+		return fmt.Sprintf("S%d", d.Segment)
+	}
+}
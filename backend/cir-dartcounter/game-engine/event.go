@@ -0,0 +1,45 @@
+package gameengine
+
+type EventType string
+
+const (
+	// EventThrowRegistered is emitted for every dart that was counted.
+	EventThrowRegistered EventType = "throw-registered"
+	// EventBust is emitted if the visit exceeded the remaining score. The score is reverted to the start of the visit.
+	EventBust EventType = "bust"
+	// EventVisitEnded is emitted after the last dart of a visit (three darts, bust, checkout or takeout).
+	EventVisitEnded EventType = "visit-ended"
+	// EventPlayerChanged is emitted when the next player is up.
+	EventPlayerChanged EventType = "player-changed"
+	// EventLegWon is emitted when a player won the leg.
+	EventLegWon EventType = "leg-won"
+	// EventLegStarted is emitted when a leg of a match starts. The player throws first.
+	EventLegStarted EventType = "leg-started"
+	// EventSetWon is emitted when a player won a set of a match.
+	EventSetWon EventType = "set-won"
+	// EventMatchWon is emitted when a player won the match.
+	EventMatchWon EventType = "match-won"
+	// EventMatchEnded is emitted when a match ended without a winner because nobody won the leg (e.g. a lost solo training leg).
+	EventMatchEnded EventType = "match-ended"
+	// EventMatchAbandoned is emitted when a match was abandoned without a winner.
+	EventMatchAbandoned EventType = "match-abandoned"
+	// EventPlayerEliminated is emitted when a player is out of the game (e.g. lost the last life in killer).
+	EventPlayerEliminated EventType = "player-eliminated"
+	// EventThrowUndone is emitted when the last dart (or the early end of a visit) was undone.
+	EventThrowUndone EventType = "throw-undone"
+	// EventCorrection is emitted when a dart of the running or the previous visit was corrected. All following state was recomputed.
+	EventCorrection EventType = "correction"
+)
+
+// Event describes a change of a game caused by a dart or the end of a visit.
+type Event struct {
+	Type     EventType `json:"type"`
+	Player   int       `json:"player"`             // index of the player the event refers to
+	Dart     *Dart     `json:"dart,omitempty"`     // the counted dart (throw-registered, correction)
+	Points   int       `json:"points"`             // points of the dart (throw-registered) or the visit (visit-ended, negative if points were lost)
+	Marks    int       `json:"marks,omitempty"`    // marks of the dart on a cricket number (throw-registered)
+	Visit    []Dart    `json:"visit,omitempty"`    // all darts of the visit (bust, visit-ended, correction)
+	Previous *Dart     `json:"previous,omitempty"` // the replaced dart (correction)
+	Set      int       `json:"set,omitempty"`      // number of the set (leg-started, set-won)
+	Leg      int       `json:"leg,omitempty"`      // number of the leg inside the set (leg-started)
+}
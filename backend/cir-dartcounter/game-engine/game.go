@@ -0,0 +1,210 @@
+package gameengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DartsPerVisit is the number of darts a player throws per visit.
+const DartsPerVisit = 3
+
+var ErrGameOver = errors.New("game is over")
+
+var ErrUnknownMode = errors.New("unknown game mode")
+
+// Mode is the game mode of a leg.
+type Mode string
+
+const (
+	ModeX01            Mode = "x01"
+	ModeCricket        Mode = "cricket"
+	ModeCutThroat      Mode = "cut-throat"
+	ModeAroundTheClock Mode = "around-the-clock"
+	ModeShanghai       Mode = "shanghai"
+	ModeKiller         Mode = "killer"
+	ModeHalveIt        Mode = "halve-it"
+	ModeBobs27         Mode = "bobs-27"
+)
+
+// Rules are the rules of a game mode applied to a running leg.
+// Implementations are not safe for concurrent use.
+type Rules interface {
+	// Mode returns the game mode.
+	Mode() Mode
+	// Throw counts a dart of the current player.
+	Throw(d Dart) ([]Event, error)
+	// EndVisit ends the running visit early (e.g. the darts were taken out of the board). The missing darts count as misses.
+	EndVisit() ([]Event, error)
+	// SetStarter hands the first visit to the player. It fails once the leg is running.
+	SetStarter(player int) error
+	// Undo reverts the last dart (or the early end of a visit).
+	Undo() ([]Event, error)
+	// State returns a snapshot of the game.
+	State() State
+	// Winner returns the index of the player who won the leg.
+	Winner() (int, bool)
+	// AllowedInputs returns the darts that count for the current player. All other darts are accepted but do not count.
+	AllowedInputs() []Dart
+}
+
+// State is a snapshot of a game. The details depend on the game mode (e.g. X01State or CricketState).
+type State struct {
+	Mode          Mode   `json:"mode"`
+	CurrentPlayer int    `json:"currentPlayer"`
+	Visit         []Dart `json:"visit"` // darts of the running visit
+	VisitPoints   int    `json:"visitPoints"`
+	Winner        int    `json:"winner"`   // index of the winner, -1 while the game is running or if nobody won
+	Finished      bool   `json:"finished"` // the game is over
+	Details       any    `json:"details"`
+}
+
+// Factory creates the rules of a game mode for the hand-overed players. The options are json encoded and may be empty (defaults).
+type Factory func(players []string, options json.RawMessage) (Rules, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[Mode]Factory{
+		ModeX01: func(players []string, options json.RawMessage) (Rules, error) {
+			o := DefaultX01Options()
+			if err := decodeOptions(options, &o); err != nil {
+				return nil, err
+			}
+			return NewX01(players, o)
+		},
+		ModeCricket: func(players []string, options json.RawMessage) (Rules, error) {
+			var o CricketOptions
+			if err := decodeOptions(options, &o); err != nil {
+				return nil, err
+			}
+			return NewCricket(players, o)
+		},
+		ModeCutThroat: func(players []string, options json.RawMessage) (Rules, error) {
+			o := CricketOptions{CutThroat: true}
+			if err := decodeOptions(options, &o); err != nil {
+				return nil, err
+			}
+			o.CutThroat = true
+			return NewCricket(players, o)
+		},
+		ModeAroundTheClock: func(players []string, options json.RawMessage) (Rules, error) {
+			var o AroundTheClockOptions
+			if err := decodeOptions(options, &o); err != nil {
+				return nil, err
+			}
+			return NewAroundTheClock(players, o)
+		},
+		ModeShanghai: func(players []string, options json.RawMessage) (Rules, error) {
+			o := DefaultShanghaiOptions()
+			if err := decodeOptions(options, &o); err != nil {
+				return nil, err
+			}
+			return NewShanghai(players, o)
+		},
+		ModeKiller: func(players []string, options json.RawMessage) (Rules, error) {
+			o := DefaultKillerOptions()
+			if err := decodeOptions(options, &o); err != nil {
+				return nil, err
+			}
+			return NewKiller(players, o)
+		},
+		ModeHalveIt: func(players []string, options json.RawMessage) (Rules, error) {
+			o := DefaultHalveItOptions()
+			if err := decodeOptions(options, &o); err != nil {
+				return nil, err
+			}
+			return NewHalveIt(players, o)
+		},
+		ModeBobs27: func(players []string, options json.RawMessage) (Rules, error) {
+			var o Bobs27Options
+			if err := decodeOptions(options, &o); err != nil {
+				return nil, err
+			}
+			return NewBobs27(players, o)
+		},
+	}
+)
+
+// Register adds a game mode (or replaces the rules of an existing one).
+func Register(mode Mode, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[mode] = factory
+}
+
+// Modes returns all registered game modes.
+func Modes() []Mode {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	var modes []Mode
+	for mode := range factories {
+		modes = append(modes, mode)
+	}
+	sort.Slice(modes, func(i, j int) bool { return modes[i] < modes[j] })
+	return modes
+}
+
+// NewGame starts a game of the hand-overed mode. The options are json encoded and may be empty (defaults of the mode).
+func NewGame(mode Mode, players []string, options json.RawMessage) (Rules, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[mode]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("NewGame() - error: %q: %w", mode, ErrUnknownMode)
+	}
+	return factory(players, options)
+}
+
+// decodeOptions decodes json encoded options into the hand-overed defaults. Unknown fields are rejected.
+func decodeOptions(options json.RawMessage, v any) error {
+	if len(bytes.TrimSpace(options)) == 0 || bytes.Equal(bytes.TrimSpace(options), []byte("null")) {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(options))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid options: %v", err)
+	}
+	return nil
+}
+
+// validatePlayers checks that there is at least one player and that every player has a name.
+func validatePlayers(players []string) error {
+	if len(players) == 0 {
+		return fmt.Errorf("no players")
+	}
+	for i, name := range players {
+		if name == "" {
+			return fmt.Errorf("player %d without name", i+1)
+		}
+	}
+	return nil
+}
+
+// allDarts returns every dart that scores on the board.
+func allDarts() []Dart {
+	var darts []Dart
+	for segment := 1; segment <= 20; segment++ {
+		darts = append(darts, segmentDarts(segment)...)
+	}
+	return append(darts, segmentDarts(BullSegment)...)
+}
+
+// segmentDarts returns the single, double and treble of a segment (single and double for the bull).
+func segmentDarts(segment int) []Dart {
+	if segment == BullSegment {
+		return []Dart{{Segment: BullSegment, Multiplier: 1}, {Segment: BullSegment, Multiplier: 2}}
+	}
+	return []Dart{{Segment: segment, Multiplier: 1}, {Segment: segment, Multiplier: 2}, {Segment: segment, Multiplier: 3}}
+}
+
+// average returns the three-dart average of points (or marks).
+func average(points, darts int) float64 {
+	if darts == 0 {
+		return 0
+	}
+	return float64(points) / float64(darts) * DartsPerVisit
+}
@@ -0,0 +1,149 @@
+package gameengine
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// play throws the darts of the space separated labels, "|" ends the visit early (takeout). It returns all emitted events.
+func play(t *testing.T, g thrower, labels string) []Event {
+	t.Helper()
+	var events []Event
+	for _, label := range strings.Fields(labels) {
+		if label == "|" {
+			e, err := g.EndVisit()
+			if err != nil {
+				t.Fatalf("EndVisit() after %q: %v", labels, err)
+			}
+			events = append(events, e...)
+			continue
+		}
+		d, err := ParseDart(label)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := g.Throw(d)
+		if err != nil {
+			t.Fatalf("Throw(%s) in %q: %v", label, labels, err)
+		}
+		events = append(events, e...)
+	}
+	return events
+}
+
+// eventTypes returns the comma separated types of the events.
+func eventTypes(events []Event) string {
+	var types []string
+	for _, e := range events {
+		types = append(types, string(e.Type))
+	}
+	return strings.Join(types, ",")
+}
+
+func TestParseDart(t *testing.T) {
+	tests := []struct {
+		label string
+		want  Dart
+	}{
+		{"T20", Dart{Segment: 20, Multiplier: 3}},
+		{"t20", Dart{Segment: 20, Multiplier: 3}},
+		{"D16", Dart{Segment: 16, Multiplier: 2}},
+		{"S5", Dart{Segment: 5, Multiplier: 1}},
+		{"5", Dart{Segment: 5, Multiplier: 1}},
+		{"25", Dart{Segment: BullSegment, Multiplier: 1}},
+		{"BULL", Dart{Segment: BullSegment, Multiplier: 2}},
+		{"D25", Dart{Segment: BullSegment, Multiplier: 2}},
+		{"MISS", Miss},
+		{"miss", Miss},
+	}
+	for _, tt := range tests {
+		d, err := ParseDart(tt.label)
+		if err != nil {
+			t.Errorf("ParseDart(%q): %v", tt.label, err)
+			continue
+		}
+		if d != tt.want {
+			t.Errorf("ParseDart(%q) = %+v, want %+v", tt.label, d, tt.want)
+		}
+	}
+	for _, label := range []string{"", "T25", "D21", "X3", "S0"} {
+		if _, err := ParseDart(label); err == nil {
+			t.Errorf("ParseDart(%q) is valid", label)
+		}
+	}
+}
+
+func TestNewGame(t *testing.T) {
+	tests := []struct {
+		mode    Mode
+		players []string
+		options string
+		valid   bool
+	}{
+		{ModeX01, []string{"A"}, ``, true},
+		{ModeX01, []string{"A"}, `null`, true},
+		{ModeX01, []string{"A"}, `{"startScore":301,"in":"double"}`, true},
+		{ModeX01, []string{"A"}, `{"foo":1}`, false},
+		{ModeX01, []string{"A"}, `{"in":"triple"}`, false},
+		{ModeX01, nil, ``, false},
+		{ModeCutThroat, []string{"A"}, `{}`, true},
+		{ModeKiller, []string{"A"}, ``, false},
+		{ModeKiller, []string{"A", "B"}, `{"lives":3,"numbers":[1,1]}`, false},
+		{"foo", []string{"A"}, ``, false},
+	}
+	for _, tt := range tests {
+		g, err := NewGame(tt.mode, tt.players, json.RawMessage(tt.options))
+		if (err == nil) != tt.valid {
+			t.Errorf("NewGame(%s, %v, %s): err = %v, want valid %v", tt.mode, tt.players, tt.options, err, tt.valid)
+			continue
+		}
+		if err == nil && g.Mode() != tt.mode {
+			t.Errorf("NewGame(%s).Mode() = %s", tt.mode, g.Mode())
+		}
+	}
+}
+
+// Undo of every mode has to restore the state before the last dart (the games are replayed without it).
+func TestUndo(t *testing.T) {
+	const darts = "T20 S19 | D16 S1 D1 T1 S20 D20 S25 D25 MISS T19"
+	players := []string{"A", "B", "C"}
+	for _, mode := range Modes() {
+		want, err := NewGame(mode, players, nil)
+		if err != nil {
+			t.Fatal(mode, err)
+		}
+		g, _ := NewGame(mode, players, nil)
+		play(t, want, darts)
+		if want.State().Finished {
+			t.Fatalf("%s: the game must not be over after %q", mode, darts)
+		}
+		play(t, g, darts+" D3")
+
+		events, err := g.Undo()
+		if err != nil {
+			t.Fatalf("%s: Undo(): %v", mode, err)
+		}
+		if eventTypes(events) != string(EventThrowUndone) {
+			t.Errorf("%s: Undo() events = %s", mode, eventTypes(events))
+		}
+		if !reflect.DeepEqual(g.State(), want.State()) {
+			t.Errorf("%s: state after Undo() = %+v, want %+v", mode, g.State(), want.State())
+		}
+
+		// undo the takeout and all darts
+		for i := 0; i < 13; i++ {
+			if _, err := g.Undo(); err != nil {
+				t.Fatalf("%s: Undo() #%d: %v", mode, i+2, err)
+			}
+		}
+		fresh, _ := NewGame(mode, players, nil)
+		if !reflect.DeepEqual(g.State(), fresh.State()) {
+			t.Errorf("%s: state after undoing everything = %+v, want %+v", mode, g.State(), fresh.State())
+		}
+		if _, err := g.Undo(); err == nil {
+			t.Errorf("%s: Undo() of a new game succeeded", mode)
+		}
+	}
+}
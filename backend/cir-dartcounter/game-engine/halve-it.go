@@ -0,0 +1,204 @@
+package gameengine
+
+import (
+	"fmt"
+)
+
+// HalveItTarget is the target of a halve-it round. A zero segment (multiplier) matches every segment (multiplier).
+type HalveItTarget struct {
+	Segment    int `json:"segment"`
+	Multiplier int `json:"multiplier"`
+}
+
+// matches reports whether the dart hit the target.
+func (t HalveItTarget) matches(d Dart) bool {
+	if d.IsMiss() {
+		return false
+	}
+	return (t.Segment == 0 || t.Segment == d.Segment) && (t.Multiplier == 0 || t.Multiplier == d.Multiplier)
+}
+
+// darts returns all darts that hit the target.
+func (t HalveItTarget) darts() []Dart {
+	var darts []Dart
+	for _, d := range allDarts() {
+		if t.matches(d) {
+			darts = append(darts, d)
+		}
+	}
+	return darts
+}
+
+func (t HalveItTarget) validate() error {
+	if t.Segment < 0 || (t.Segment > 20 && t.Segment != BullSegment) {
+		return fmt.Errorf("invalid segment %d", t.Segment)
+	}
+	if t.Multiplier < 0 || t.Multiplier > 3 || (t.Segment == BullSegment && t.Multiplier > 2) {
+		return fmt.Errorf("invalid multiplier %d", t.Multiplier)
+	}
+	return nil
+}
+
+// HalveItOptions configures a game of halve-it.
+type HalveItOptions struct {
+	StartScore int             `json:"startScore"`
+	Targets    []HalveItTarget `json:"targets"` // one target per round
+}
+
+// DefaultHalveItOptions returns the classic sequence: 20, 16, any double, 17, any treble, 19 and the bull.
+func DefaultHalveItOptions() HalveItOptions {
+	return HalveItOptions{
+		StartScore: 40,
+		Targets: []HalveItTarget{
+			{Segment: 20},
+			{Segment: 16},
+			{Multiplier: 2},
+			{Segment: 17},
+			{Multiplier: 3},
+			{Segment: 19},
+			{Segment: BullSegment},
+		},
+	}
+}
+
+// HalveItPlayerState is the state of a player of halve-it.
+type HalveItPlayerState struct {
+	Name        string `json:"name"`
+	Score       int    `json:"score"`
+	Round       int    `json:"round"` // index of the target the player plays next
+	Halved      int    `json:"halved"`
+	DartsThrown int    `json:"dartsThrown"`
+}
+
+// HalveItState contains the details of a halve-it snapshot.
+type HalveItState struct {
+	Options HalveItOptions       `json:"options"`
+	Players []HalveItPlayerState `json:"players"`
+}
+
+// HalveIt is played over a sequence of targets, one per round. Darts on the target add their value; a visit without a hit
+// halves the score (rounded down). The highest score after the last round wins; a tie is won by the player who threw first.
+type HalveIt struct {
+	turn
+	names   []string
+	options HalveItOptions
+	players []HalveItPlayerState
+	hits    int // hits of the running visit
+}
+
+// NewHalveIt starts a game of halve-it for the hand-overed players.
+func NewHalveIt(players []string, options HalveItOptions) (*HalveIt, error) {
+	if options.StartScore < 0 {
+		return nil, fmt.Errorf("NewHalveIt() - error: invalid start score %d", options.StartScore)
+	}
+	if len(options.Targets) == 0 {
+		return nil, fmt.Errorf("NewHalveIt() - error: no targets")
+	}
+	for i, t := range options.Targets {
+		if err := t.validate(); err != nil {
+			return nil, fmt.Errorf("NewHalveIt() - error: target %d: %v", i+1, err)
+		}
+	}
+	if err := validatePlayers(players); err != nil {
+		return nil, fmt.Errorf("NewHalveIt() - error: %v", err)
+	}
+	g := &HalveIt{
+		turn:    newTurn(len(players)),
+		names:   append([]string(nil), players...),
+		options: options,
+	}
+	for _, name := range players {
+		g.players = append(g.players, HalveItPlayerState{Name: name, Score: options.StartScore})
+	}
+	return g, nil
+}
+
+// Mode returns the game mode.
+func (g *HalveIt) Mode() Mode {
+	return ModeHalveIt
+}
+
+// Throw counts a dart of the current player.
+func (g *HalveIt) Throw(d Dart) ([]Event, error) {
+	if err := g.checkThrow(d); err != nil {
+		return nil, err
+	}
+
+	p := &g.players[g.current]
+	g.startThrow(d)
+	p.DartsThrown++
+
+	points := 0
+	if g.options.Targets[p.Round].matches(d) {
+		points = d.Value()
+		g.hits++
+	}
+	p.Score += points
+	g.visitPoints += points
+
+	events := []Event{g.throwEvent(d, points, 0)}
+	if len(g.visit) == DartsPerVisit {
+		events = append(events, g.completeVisit()...)
+	}
+	return events, nil
+}
+
+// EndVisit ends the running visit early. The missing darts count as misses.
+func (g *HalveIt) EndVisit() ([]Event, error) {
+	misses, ok, err := g.checkEndVisit()
+	if !ok {
+		return nil, err
+	}
+	g.players[g.current].DartsThrown += misses
+	return g.completeVisit(), nil
+}
+
+// Undo reverts the last dart (or the early end of a visit).
+func (g *HalveIt) Undo() ([]Event, error) {
+	fresh, err := NewHalveIt(g.names, g.options)
+	if err != nil {
+		return nil, err
+	}
+	event, err := g.undo(fresh)
+	if err != nil {
+		return nil, err
+	}
+	*g = *fresh
+	return []Event{event}, nil
+}
+
+// State returns a snapshot of the game.
+func (g *HalveIt) State() State {
+	options := g.options
+	options.Targets = append([]HalveItTarget(nil), g.options.Targets...)
+	return g.state(ModeHalveIt, HalveItState{
+		Options: options,
+		Players: append([]HalveItPlayerState(nil), g.players...),
+	})
+}
+
+// AllowedInputs returns the darts on the target of the current round.
+func (g *HalveIt) AllowedInputs() []Dart {
+	if g.over {
+		return nil
+	}
+	return g.options.Targets[g.players[g.current].Round].darts()
+}
+
+// completeVisit halves the score if the visit missed the target and moves the current player to the next round. The game ends
+// after the last round of the last player.
+func (g *HalveIt) completeVisit() []Event {
+	p := &g.players[g.current]
+	if g.hits == 0 {
+		lost := p.Score - p.Score/2
+		p.Score -= lost
+		p.Halved++
+		g.visitPoints = -lost
+	}
+	g.hits = 0
+	p.Round++
+	if g.current == g.lastPlayer() && p.Round == len(g.options.Targets) {
+		return g.win(g.highest(func(i int) int { return g.players[i].Score }))
+	}
+	return g.endVisit()
+}
@@ -0,0 +1,58 @@
+package gameengine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHalveIt(t *testing.T) {
+	options := HalveItOptions{StartScore: 40, Targets: []HalveItTarget{{Segment: 20}, {Multiplier: 2}}}
+	tests := []struct {
+		name   string
+		darts  string
+		scores []int
+		halved []int
+		winner int // -1 if the game is running
+	}{
+		{"hits on the target", "T20 MISS MISS", []int{100, 40}, []int{0, 0}, -1},
+		{"a visit without a hit halves the score", "T20 MISS MISS | S19 |", []int{100, 20}, []int{0, 1}, -1},
+		{"any double", "T20 MISS MISS | S19 | D5 S5 |", []int{110, 20}, []int{0, 1}, -1},
+		{"highest score after the last target", "T20 MISS MISS | S19 | D5 S5 | S3 |", []int{110, 10}, []int{0, 2}, 0},
+		{"rounded down", "MISS | MISS | MISS | MISS |", []int{10, 10}, []int{2, 2}, 0},
+	}
+	for _, tt := range tests {
+		g, err := NewHalveIt([]string{"A", "B"}, options)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		play(t, g, tt.darts)
+		var scores, halved []int
+		for _, player := range g.State().Details.(HalveItState).Players {
+			scores = append(scores, player.Score)
+			halved = append(halved, player.Halved)
+		}
+		if !reflect.DeepEqual(scores, tt.scores) || !reflect.DeepEqual(halved, tt.halved) {
+			t.Errorf("%s: scores %v (halved %v), want %v (%v)", tt.name, scores, halved, tt.scores, tt.halved)
+		}
+		winner, won := g.Winner()
+		if !won {
+			winner = -1
+		}
+		if winner != tt.winner {
+			t.Errorf("%s: winner %d, want %d", tt.name, winner, tt.winner)
+		}
+	}
+}
+
+func TestHalveItAllowedInputs(t *testing.T) {
+	g, _ := NewHalveIt([]string{"A"}, DefaultHalveItOptions())
+	want := []Dart{{Segment: 20, Multiplier: 1}, {Segment: 20, Multiplier: 2}, {Segment: 20, Multiplier: 3}}
+	if inputs := g.AllowedInputs(); !reflect.DeepEqual(inputs, want) {
+		t.Errorf("allowed inputs %v, want %v", inputs, want)
+	}
+	g, _ = NewHalveIt([]string{"A"}, HalveItOptions{StartScore: 40, Targets: []HalveItTarget{{Segment: 20}}})
+	play(t, g, "S20 |")
+	if inputs := g.AllowedInputs(); len(inputs) != 0 {
+		t.Errorf("allowed inputs of a finished game %v", inputs)
+	}
+}
@@ -0,0 +1,192 @@
+package gameengine
+
+import (
+	"fmt"
+)
+
+// KillerOptions configures a game of killer.
+type KillerOptions struct {
+	Lives   int   `json:"lives"`   // lives of every player
+	Numbers []int `json:"numbers"` // number of every player (distinct, 1-20), spread over the board if empty
+}
+
+// DefaultKillerOptions returns the options of the classic game with three lives.
+func DefaultKillerOptions() KillerOptions {
+	return KillerOptions{Lives: 3}
+}
+
+// KillerPlayerState is the state of a player of killer.
+type KillerPlayerState struct {
+	Name        string `json:"name"`
+	Number      int    `json:"number"`
+	Lives       int    `json:"lives"`
+	Killer      bool   `json:"killer"` // the player hit the double of the own number
+	Eliminated  bool   `json:"eliminated"`
+	DartsThrown int    `json:"dartsThrown"`
+}
+
+// KillerState contains the details of a killer snapshot.
+type KillerState struct {
+	Options KillerOptions       `json:"options"`
+	Players []KillerPlayerState `json:"players"`
+}
+
+// Killer assigns a number to every player. Hitting the double of the own number makes a player a killer; a killer takes a life
+// from an opponent with every hit on the double of the opponent's number (and loses a life on the own double). Players without
+// lives are out; the last player in the game wins.
+type Killer struct {
+	turn
+	names   []string
+	options KillerOptions
+	players []KillerPlayerState
+}
+
+// NewKiller starts a game of killer for the hand-overed players (at least two).
+func NewKiller(players []string, options KillerOptions) (*Killer, error) {
+	if err := validatePlayers(players); err != nil {
+		return nil, fmt.Errorf("NewKiller() - error: %v", err)
+	}
+	if len(players) < 2 || len(players) > 20 {
+		return nil, fmt.Errorf("NewKiller() - error: killer needs 2-20 players, got %d", len(players))
+	}
+	if options.Lives < 1 {
+		return nil, fmt.Errorf("NewKiller() - error: invalid number of lives %d", options.Lives)
+	}
+	numbers := options.Numbers
+	if len(numbers) == 0 {
+		numbers = make([]int, len(players))
+		for i := range numbers {
+			numbers[i] = 1 + i*20/len(players)
+		}
+	}
+	if len(numbers) != len(players) {
+		return nil, fmt.Errorf("NewKiller() - error: %d numbers for %d players", len(numbers), len(players))
+	}
+	seen := make(map[int]bool)
+	for _, n := range numbers {
+		if n < 1 || n > 20 || seen[n] {
+			return nil, fmt.Errorf("NewKiller() - error: invalid or duplicate number %d", n)
+		}
+		seen[n] = true
+	}
+
+	g := &Killer{
+		turn:    newTurn(len(players)),
+		names:   append([]string(nil), players...),
+		options: options,
+	}
+	for i, name := range players {
+		g.players = append(g.players, KillerPlayerState{Name: name, Number: numbers[i], Lives: options.Lives})
+	}
+	return g, nil
+}
+
+// Mode returns the game mode.
+func (g *Killer) Mode() Mode {
+	return ModeKiller
+}
+
+// Throw counts a dart of the current player. Only doubles count.
+func (g *Killer) Throw(d Dart) ([]Event, error) {
+	if err := g.checkThrow(d); err != nil {
+		return nil, err
+	}
+
+	p := &g.players[g.current]
+	g.startThrow(d)
+	p.DartsThrown++
+
+	victim := -1
+	marks := 0
+	if d.IsDouble() {
+		victim = g.owner(d.Segment)
+	}
+	switch {
+	case victim < 0:
+	case victim == g.current && !p.Killer:
+		p.Killer = true
+		marks = 1
+		victim = -1
+	case victim == g.current || p.Killer:
+		g.players[victim].Lives--
+		marks = 1
+	default:
+		victim = -1
+	}
+
+	events := []Event{g.throwEvent(d, 0, marks)}
+	if victim >= 0 && g.players[victim].Lives == 0 {
+		g.players[victim].Eliminated = true
+		g.eliminated[victim] = true
+		events = append(events, Event{Type: EventPlayerEliminated, Player: victim})
+
+		if remaining := g.remaining(); len(remaining) == 1 {
+			return append(events, g.win(remaining[0])...), nil
+		}
+		if victim == g.current {
+			return append(events, g.endVisit()...), nil
+		}
+	}
+	return append(events, g.endVisitIfComplete()...), nil
+}
+
+// EndVisit ends the running visit early. The missing darts count as misses.
+func (g *Killer) EndVisit() ([]Event, error) {
+	misses, ok, err := g.checkEndVisit()
+	if !ok {
+		return nil, err
+	}
+	g.players[g.current].DartsThrown += misses
+	return g.endVisit(), nil
+}
+
+// Undo reverts the last dart (or the early end of a visit).
+func (g *Killer) Undo() ([]Event, error) {
+	fresh, err := NewKiller(g.names, g.options)
+	if err != nil {
+		return nil, err
+	}
+	event, err := g.undo(fresh)
+	if err != nil {
+		return nil, err
+	}
+	*g = *fresh
+	return []Event{event}, nil
+}
+
+// State returns a snapshot of the game.
+func (g *Killer) State() State {
+	return g.state(ModeKiller, KillerState{
+		Options: g.options,
+		Players: append([]KillerPlayerState(nil), g.players...),
+	})
+}
+
+// AllowedInputs returns the own double as long as the current player is no killer, afterwards the doubles of the opponents
+// who are still in the game.
+func (g *Killer) AllowedInputs() []Dart {
+	if g.over {
+		return nil
+	}
+	p := g.players[g.current]
+	if !p.Killer {
+		return []Dart{{Segment: p.Number, Multiplier: 2}}
+	}
+	var darts []Dart
+	for i, o := range g.players {
+		if i != g.current && !o.Eliminated {
+			darts = append(darts, Dart{Segment: o.Number, Multiplier: 2})
+		}
+	}
+	return darts
+}
+
+// owner returns the player in the game who owns the number or -1.
+func (g *Killer) owner(number int) int {
+	for i, p := range g.players {
+		if p.Number == number && !p.Eliminated {
+			return i
+		}
+	}
+	return -1
+}
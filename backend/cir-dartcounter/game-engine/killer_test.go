@@ -0,0 +1,90 @@
+package gameengine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKiller(t *testing.T) {
+	tests := []struct {
+		name    string
+		darts   string
+		lives   []int
+		killers []bool
+		current int
+		winner  int // -1 if the game is running
+	}{
+		{"own double makes a killer", "D1 S1 T1", []int{2, 2, 2}, []bool{true, false, false}, 1, -1},
+		{"doubles of opponents without being a killer", "D2 D3 |", []int{2, 2, 2}, []bool{false, false, false}, 1, -1},
+		{"killer takes lives", "D1 D2 D2 |", []int{2, 0, 2}, []bool{true, false, false}, 2, -1},
+		{"eliminated players are skipped", "D1 D2 D2 | D3 |", []int{2, 0, 2}, []bool{true, false, true}, 0, -1},
+		{"killer loses lives on the own double", "D1 D2 D2 | D3 | D1 D1", []int{0, 0, 2}, []bool{true, false, true}, 0, 2},
+	}
+	for _, tt := range tests {
+		g, err := NewKiller([]string{"A", "B", "C"}, KillerOptions{Lives: 2, Numbers: []int{1, 2, 3}})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		play(t, g, tt.darts)
+		state := g.State()
+		var lives []int
+		var killers []bool
+		for _, player := range state.Details.(KillerState).Players {
+			lives = append(lives, player.Lives)
+			killers = append(killers, player.Killer)
+		}
+		if !reflect.DeepEqual(lives, tt.lives) || !reflect.DeepEqual(killers, tt.killers) {
+			t.Errorf("%s: lives %v (killers %v), want %v (%v)", tt.name, lives, killers, tt.lives, tt.killers)
+		}
+		winner, won := g.Winner()
+		if !won {
+			winner = -1
+		}
+		if winner != tt.winner {
+			t.Errorf("%s: winner %d, want %d", tt.name, winner, tt.winner)
+		}
+		if !state.Finished && state.CurrentPlayer != tt.current {
+			t.Errorf("%s: current player %d, want %d", tt.name, state.CurrentPlayer, tt.current)
+		}
+	}
+}
+
+func TestKillerEliminationEvent(t *testing.T) {
+	g, _ := NewKiller([]string{"A", "B", "C"}, KillerOptions{Lives: 1, Numbers: []int{1, 2, 3}})
+	events := play(t, g, "D1 D2")
+	var eliminated []int
+	for _, e := range events {
+		if e.Type == EventPlayerEliminated {
+			eliminated = append(eliminated, e.Player)
+		}
+	}
+	if !reflect.DeepEqual(eliminated, []int{1}) {
+		t.Errorf("eliminated players %v, want [1]", eliminated)
+	}
+}
+
+func TestKillerNumbers(t *testing.T) {
+	g, err := NewKiller([]string{"A", "B", "C", "D"}, DefaultKillerOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[int]bool{}
+	for _, player := range g.State().Details.(KillerState).Players {
+		if player.Number < 1 || player.Number > 20 || seen[player.Number] {
+			t.Errorf("invalid or duplicate number %d", player.Number)
+		}
+		seen[player.Number] = true
+		if player.Lives != 3 {
+			t.Errorf("lives %d, want 3", player.Lives)
+		}
+	}
+	for _, options := range []KillerOptions{
+		{Lives: 3, Numbers: []int{1, 1}},
+		{Lives: 3, Numbers: []int{1, 21}},
+		{Lives: 0},
+	} {
+		if _, err := NewKiller([]string{"A", "B"}, options); err == nil {
+			t.Errorf("NewKiller(%+v) is valid", options)
+		}
+	}
+}
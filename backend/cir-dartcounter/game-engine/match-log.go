@@ -0,0 +1,314 @@
+package gameengine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	subscriptionhandler "github.com/One-Hundred-Eighty/Circle/pkg/subscription-handler"
+)
+
+// Action is an action of a match that is recorded in the match log.
+type Action string
+
+const (
+	// ActionCreate is the first entry of every log. It contains the players and the options of the match.
+	ActionCreate Action = "create"
+	// ActionThrow counts a dart (detected or entered manually).
+	ActionThrow Action = "throw"
+	// ActionEndVisit ends the running visit early (takeout).
+	ActionEndVisit Action = "end-visit"
+	// ActionUndo reverts the last dart (or the early end of a visit).
+	ActionUndo Action = "undo"
+	// ActionCorrect replaces a dart of the running or the previous visit.
+	ActionCorrect Action = "correct"
+	// ActionAbandon ends the match without a winner.
+	ActionAbandon Action = "abandon"
+)
+
+// LogEntry is an entry of the match log. The fields depend on the action.
+type LogEntry struct {
+	Seq      int           `json:"seq"` // 1 for the creation of the match, increasing without gaps
+	Time     time.Time     `json:"time"`
+	Action   Action        `json:"action"`
+	Players  []string      `json:"players,omitempty"`  // create
+	Options  *MatchOptions `json:"options,omitempty"`  // create
+	Dart     *Dart         `json:"dart,omitempty"`     // throw, correct
+	Previous bool          `json:"previous,omitempty"` // correct: the dart belongs to the previous visit
+	Index    int           `json:"index,omitempty"`    // correct: index of the dart inside the visit
+}
+
+// Record is an appended log entry together with the events it caused and the resulting state of the match.
+type Record struct {
+	Entry  LogEntry   `json:"entry"`
+	Events []Event    `json:"events"`
+	State  MatchState `json:"state"`
+}
+
+// ErrNotPersisted is returned if an action was applied and appended to the log, but persisting the log failed. The next
+// persisted action persists the entry as well.
+var ErrNotPersisted = errors.New("match log not persisted")
+
+// PersistFunc persists the entries of a match log. It is called with all entries after every appended entry, in the order
+// of the entries. A returned error is reported by the action (ErrNotPersisted), the entries stay appended.
+type PersistFunc func(entries []LogEntry) error
+
+// MatchLog is the append-only log of all actions of a match. Every action is applied to the match before it is appended,
+// so replaying the entries rebuilds the identical match. Subscribers receive a record for every appended entry.
+// It is safe for concurrent use.
+type MatchLog struct {
+	mu                  sync.Mutex
+	entries             []LogEntry
+	match               *Match
+	persist             PersistFunc // nil if the log is not persisted
+	subscriptionHandler *subscriptionhandler.SubscriptionHandler[Record]
+}
+
+// NewMatchLog creates a match and starts its log with the create entry.
+func NewMatchLog(players []string, options MatchOptions) (*MatchLog, error) {
+	options.GameOptions = append(json.RawMessage(nil), options.GameOptions...)
+	entry := LogEntry{Seq: 1, Time: time.Now(), Action: ActionCreate, Players: append([]string(nil), players...), Options: &options}
+	match, err := NewMatch(players, options)
+	if err != nil {
+		return nil, fmt.Errorf("NewMatchLog() - error: %v", err)
+	}
+	return &MatchLog{
+		entries:             []LogEntry{entry},
+		match:               match,
+		subscriptionHandler: subscriptionhandler.NewSubscriptionHandler[Record](),
+	}, nil
+}
+
+// ReplayMatchLog rebuilds a match from the entries of its log.
+func ReplayMatchLog(entries []LogEntry) (*MatchLog, error) {
+	if len(entries) == 0 || entries[0].Action != ActionCreate || entries[0].Options == nil {
+		return nil, fmt.Errorf("ReplayMatchLog() - error: the log does not start with the creation of a match")
+	}
+	match, err := replayEntries(entries)
+	if err != nil {
+		return nil, fmt.Errorf("ReplayMatchLog() - error: %v", err)
+	}
+	return &MatchLog{
+		entries:             append([]LogEntry(nil), entries...),
+		match:               match,
+		subscriptionHandler: subscriptionhandler.NewSubscriptionHandler[Record](),
+	}, nil
+}
+
+// Throw counts a dart in the running leg.
+func (l *MatchLog) Throw(d Dart) (Record, error) {
+	return l.append(LogEntry{Action: ActionThrow, Dart: &d})
+}
+
+// ThrowVisit counts the darts of the running visit (e.g. a visit entered manually). The visit is rejected if it has more
+// darts than the current player has left, less darts end the visit early. Darts after an early end of the visit (e.g.
+// bust or checkout) are dropped, the records contain the counted darts only. Nothing is counted if a dart is rejected.
+// If persisting the log fails, the records are returned with ErrNotPersisted.
+func (l *MatchLog) ThrowVisit(darts []Dart) ([]Record, error) {
+	if len(darts) == 0 || len(darts) > DartsPerVisit {
+		return nil, fmt.Errorf("ThrowVisit() - error: a visit has 1 to %d darts, got %d", DartsPerVisit, len(darts))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// --> the visit is applied to a replayed copy of the match, a rejected dart leaves the match untouched
+	match, err := replayEntries(l.entries)
+	if err != nil {
+		return nil, fmt.Errorf("ThrowVisit() - error: %v", err)
+	}
+	if state := match.State(); !state.Finished && len(darts) > DartsPerVisit-len(state.Game.Visit) {
+		// --> the darts would be counted in the next visit
+		return nil, fmt.Errorf("ThrowVisit() - error: %d darts left in the visit, got %d", DartsPerVisit-len(state.Game.Visit), len(darts))
+	}
+	var records []Record
+	ended := false
+	for i := 0; i < len(darts) && !ended; i++ {
+		d := darts[i]
+		entry := LogEntry{Action: ActionThrow, Dart: &d}
+		events, err := applyEntry(match, entry)
+		if err != nil {
+			return nil, fmt.Errorf("ThrowVisit() - error: dart %d: %w", i+1, err)
+		}
+		records = append(records, Record{Entry: entry, Events: events, State: match.State()})
+		ended = visitEnded(events)
+	}
+	if !ended {
+		entry := LogEntry{Action: ActionEndVisit}
+		events, err := applyEntry(match, entry)
+		if err != nil {
+			return nil, fmt.Errorf("ThrowVisit() - error: %w", err)
+		}
+		records = append(records, Record{Entry: entry, Events: events, State: match.State()})
+	}
+
+	l.match = match
+	for i := range records {
+		records[i] = l.commit(records[i])
+	}
+	if err := l.persistEntries(); err != nil {
+		return records, fmt.Errorf("ThrowVisit() - error: %w", err)
+	}
+	return records, nil
+}
+
+// EndVisit ends the running visit early. Nothing is logged if the current player has not thrown yet.
+func (l *MatchLog) EndVisit() (Record, error) {
+	return l.append(LogEntry{Action: ActionEndVisit})
+}
+
+// Undo reverts the last dart (or the early end of a visit).
+func (l *MatchLog) Undo() (Record, error) {
+	return l.append(LogEntry{Action: ActionUndo})
+}
+
+// Correct replaces a dart of the running (or the previous) visit.
+func (l *MatchLog) Correct(previous bool, index int, d Dart) (Record, error) {
+	return l.append(LogEntry{Action: ActionCorrect, Dart: &d, Previous: previous, Index: index})
+}
+
+// Abandon ends the match without a winner.
+func (l *MatchLog) Abandon() (Record, error) {
+	return l.append(LogEntry{Action: ActionAbandon})
+}
+
+// State returns a snapshot of the match.
+func (l *MatchLog) State() MatchState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.match.State()
+}
+
+// AllowedInputs returns the darts that count for the current player of the running leg.
+func (l *MatchLog) AllowedInputs() []Dart {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.match.AllowedInputs()
+}
+
+// Entries returns a copy of the log.
+func (l *MatchLog) Entries() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]LogEntry(nil), l.entries...)
+}
+
+// SetPersist sets the function that persists the log after every appended entry. It is called synchronously, so an
+// action returns after its entry was persisted. The entries that are already in the log are not persisted.
+func (l *MatchLog) SetPersist(persist PersistFunc) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.persist = persist
+}
+
+// Subscribe returns a channel that receives a record for every appended entry.
+func (l *MatchLog) Subscribe() <-chan Record {
+	return l.subscriptionHandler.Subscribe()
+}
+
+// Unsubscribe unsubscribes from the records and closes the channel.
+func (l *MatchLog) Unsubscribe(recordCh <-chan Record) {
+	l.subscriptionHandler.Unsubscribe(recordCh)
+}
+
+// Close unsubscribes all subscribers.
+func (l *MatchLog) Close() {
+	l.subscriptionHandler.UnsubscribeAll()
+}
+
+// append applies the entry to the match and appends it to the log if it changed the match. If persisting the log fails,
+// the record is returned together with ErrNotPersisted.
+func (l *MatchLog) append(entry LogEntry) (Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events, err := applyEntry(l.match, entry)
+	if err != nil {
+		return Record{}, err
+	}
+	if len(events) == 0 {
+		return Record{State: l.match.State()}, nil
+	}
+	record := l.commit(Record{Entry: entry, Events: events, State: l.match.State()})
+	if err := l.persistEntries(); err != nil {
+		return record, fmt.Errorf("append() - error: %w", err)
+	}
+	return record, nil
+}
+
+// commit appends the entry of an applied record to the log and publishes the record. The caller must hold the lock.
+func (l *MatchLog) commit(record Record) Record {
+	record.Entry.Seq = len(l.entries) + 1
+	record.Entry.Time = time.Now()
+	l.entries = append(l.entries, record.Entry)
+
+	l.subscriptionHandler.Publish(record)
+	return record
+}
+
+// persistEntries persists a copy of the log. The caller must hold the lock.
+func (l *MatchLog) persistEntries() error {
+	if l.persist == nil {
+		return nil
+	}
+	if err := l.persist(append([]LogEntry(nil), l.entries...)); err != nil {
+		return fmt.Errorf("%w: %v", ErrNotPersisted, err)
+	}
+	return nil
+}
+
+// replayEntries creates the match of the create entry and applies all following entries.
+func replayEntries(entries []LogEntry) (*Match, error) {
+	match, err := NewMatch(entries[0].Players, *entries[0].Options)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range entries {
+		if entry.Seq != i+1 {
+			return nil, fmt.Errorf("entry %d has the sequence number %d", i+1, entry.Seq)
+		}
+		if i == 0 {
+			continue
+		}
+		if _, err := applyEntry(match, entry); err != nil {
+			return nil, fmt.Errorf("entry %d (%s): %v", entry.Seq, entry.Action, err)
+		}
+	}
+	return match, nil
+}
+
+// visitEnded reports whether the events finished the running visit.
+func visitEnded(events []Event) bool {
+	for _, e := range events {
+		if e.Type == EventVisitEnded {
+			return true
+		}
+	}
+	return false
+}
+
+// applyEntry applies a logged action to the match.
+func applyEntry(m *Match, entry LogEntry) ([]Event, error) {
+	switch entry.Action {
+	case ActionThrow:
+		if entry.Dart == nil {
+			return nil, fmt.Errorf("throw without dart")
+		}
+		return m.Throw(*entry.Dart)
+	case ActionEndVisit:
+		return m.EndVisit()
+	case ActionUndo:
+		return m.Undo()
+	case ActionCorrect:
+		if entry.Dart == nil {
+			return nil, fmt.Errorf("correction without dart")
+		}
+		return m.Correct(entry.Previous, entry.Index, *entry.Dart)
+	case ActionAbandon:
+		return m.Abandon()
+	default:
+		return nil, fmt.Errorf("unknown action %q", entry.Action)
+	}
+}
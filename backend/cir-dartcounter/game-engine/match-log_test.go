@@ -0,0 +1,281 @@
+package gameengine
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// action applies an action to a match log.
+type action func(l *MatchLog) error
+
+func throw(label string) action {
+	return func(l *MatchLog) error {
+		d, err := ParseDart(label)
+		if err != nil {
+			return err
+		}
+		_, err = l.Throw(d)
+		return err
+	}
+}
+
+func throwVisit(labels ...string) action {
+	return func(l *MatchLog) error {
+		var darts []Dart
+		for _, label := range labels {
+			d, err := ParseDart(label)
+			if err != nil {
+				return err
+			}
+			darts = append(darts, d)
+		}
+		_, err := l.ThrowVisit(darts)
+		return err
+	}
+}
+
+func endVisit(l *MatchLog) error {
+	_, err := l.EndVisit()
+	return err
+}
+
+func undo(l *MatchLog) error {
+	_, err := l.Undo()
+	return err
+}
+
+func correct(previous bool, index int, label string) action {
+	return func(l *MatchLog) error {
+		d, err := ParseDart(label)
+		if err != nil {
+			return err
+		}
+		_, err = l.Correct(previous, index, d)
+		return err
+	}
+}
+
+func abandon(l *MatchLog) error {
+	_, err := l.Abandon()
+	return err
+}
+
+func newTestMatchLog(t *testing.T) *MatchLog {
+	t.Helper()
+	l, err := NewMatchLog([]string{"A", "B"}, MatchOptions{Mode: ModeX01, GameOptions: json.RawMessage(`{ "startScore": 101 }`), Legs: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+// Replaying the log has to rebuild the identical match, also after a json round trip of the entries.
+func TestReplayMatchLog(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []action
+		entries int
+	}{
+		{"create", nil, 1},
+		{"throw", []action{throw("T20"), throw("S1")}, 3},
+		{"end visit", []action{throw("T20"), endVisit, throw("S5")}, 4},
+		{"end visit without darts is not logged", []action{throw("S20"), throw("S20"), throw("S20"), endVisit}, 4},
+		{"undo to the start", []action{throw("T20"), throw("S1"), undo, undo}, 5},
+		{"correct the running visit", []action{throw("T20"), correct(false, 0, "T19")}, 3},
+		{"correct the previous visit", []action{throw("T20"), throw("S1"), throw("S1"), throw("S5"), correct(true, 2, "D19")}, 6},
+		{"leg won", []action{throw("T20"), throw("S1"), throw("D20"), throw("S5")}, 5},
+		{"visit", []action{throwVisit("T20", "S1"), throwVisit("S5", "S5", "S5")}, 7},
+		{"visit with a bust", []action{throwVisit("T20", "T20", "S1")}, 3},
+		{"abandon", []action{throw("T20"), abandon}, 3},
+	}
+	for _, tt := range tests {
+		l := newTestMatchLog(t)
+		for i, act := range tt.actions {
+			if err := act(l); err != nil {
+				t.Fatalf("%s: action %d: %v", tt.name, i+1, err)
+			}
+		}
+		entries := l.Entries()
+		if len(entries) != tt.entries {
+			t.Errorf("%s: %d entries, want %d", tt.name, len(entries), tt.entries)
+		}
+
+		replayed, err := ReplayMatchLog(entries)
+		if err != nil {
+			t.Fatalf("%s: ReplayMatchLog(): %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(replayed.State(), l.State()) {
+			t.Errorf("%s: replayed state %+v, want %+v", tt.name, replayed.State(), l.State())
+		}
+
+		data, err := json.Marshal(entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded []LogEntry
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		replayed, err = ReplayMatchLog(decoded)
+		if err != nil {
+			t.Fatalf("%s: ReplayMatchLog() of the json entries: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(replayed.State(), l.State()) {
+			t.Errorf("%s: state replayed from json %+v, want %+v", tt.name, replayed.State(), l.State())
+		}
+	}
+}
+
+func TestMatchLogUndoToTheStart(t *testing.T) {
+	l := newTestMatchLog(t)
+	start := l.State()
+	for _, act := range []action{throw("T20"), throw("S1"), undo, undo} {
+		if err := act(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(l.State(), start) {
+		t.Errorf("state %+v, want %+v", l.State(), start)
+	}
+	if err := undo(l); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo() of a new match: %v, want %v", err, ErrNothingToUndo)
+	}
+	if entries := l.Entries(); len(entries) != 5 {
+		t.Errorf("%d entries, a failed action must not be logged", len(entries))
+	}
+}
+
+func TestMatchLogThrowVisit(t *testing.T) {
+	tests := []struct {
+		name    string
+		thrown  []string // darts of the visit thrown before
+		darts   []string
+		counted int // counted darts, -1 if the visit is rejected
+		leg     int // running leg after the visit
+		score   int // remaining score of the first player
+	}{
+		{"three darts", nil, []string{"T20", "S1", "S1"}, 3, 1, 39},
+		{"ended early", nil, []string{"T20"}, 1, 1, 41},
+		{"bust drops the remaining darts", nil, []string{"T20", "T20", "S1"}, 2, 1, 101},
+		{"checkout drops the remaining darts", nil, []string{"T17", "BULL", "S1"}, 2, 2, 101},
+		{"rest of a started visit", []string{"S1"}, []string{"T20", "S1"}, 2, 1, 39},
+		{"more darts than left in the visit", []string{"S1"}, []string{"T20", "S1", "S1"}, -1, 1, 100},
+	}
+	for _, tt := range tests {
+		l := newTestMatchLog(t)
+		for _, label := range tt.thrown {
+			if err := throw(label)(l); err != nil {
+				t.Fatal(err)
+			}
+		}
+		darts := make([]Dart, len(tt.darts))
+		for i, label := range tt.darts {
+			darts[i], _ = ParseDart(label)
+		}
+		records, err := l.ThrowVisit(darts)
+		if tt.counted < 0 {
+			if err == nil {
+				t.Errorf("%s: ThrowVisit() succeeded", tt.name)
+			}
+			tt.counted = 0
+		} else if err != nil {
+			t.Fatalf("%s: ThrowVisit(): %v", tt.name, err)
+		}
+		counted := 0
+		for _, record := range records {
+			if record.Entry.Action == ActionThrow {
+				counted++
+			}
+		}
+		if counted != tt.counted {
+			t.Errorf("%s: %d darts counted, want %d", tt.name, counted, tt.counted)
+		}
+		state := l.State()
+		if state.Leg != tt.leg || state.Game.Details.(X01State).Players[0].Score != tt.score {
+			t.Errorf("%s: leg %d, score %d, want %d, %d", tt.name, state.Leg, state.Game.Details.(X01State).Players[0].Score, tt.leg, tt.score)
+		}
+	}
+}
+
+// a rejected dart must not leave the darts before it in the match
+func TestMatchLogThrowVisitRollback(t *testing.T) {
+	l := newTestMatchLog(t)
+	if err := throw("T20")(l); err != nil {
+		t.Fatal(err)
+	}
+	before, entries := l.State(), l.Entries()
+	if _, err := l.ThrowVisit([]Dart{{Segment: 1, Multiplier: 1}, {Segment: 21, Multiplier: 1}}); err == nil {
+		t.Fatal("ThrowVisit() with an invalid dart succeeded")
+	}
+	if !reflect.DeepEqual(l.State(), before) || len(l.Entries()) != len(entries) {
+		t.Errorf("state %+v (%d entries), want %+v (%d entries)", l.State(), len(l.Entries()), before, len(entries))
+	}
+	// --> the match goes on with the state before the rejected visit
+	if err := throw("S1")(l); err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := ReplayMatchLog(l.Entries())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(replayed.State(), l.State()) {
+		t.Errorf("replayed state %+v, want %+v", replayed.State(), l.State())
+	}
+}
+
+// a failed persist is reported, the action counts and the next persist contains its entry
+func TestMatchLogPersistError(t *testing.T) {
+	l := newTestMatchLog(t)
+	var persisted []LogEntry
+	fail := true
+	l.SetPersist(func(entries []LogEntry) error {
+		if fail {
+			return errors.New("disk full")
+		}
+		persisted = entries
+		return nil
+	})
+
+	record, err := l.Throw(Dart{Segment: 20, Multiplier: 3})
+	if !errors.Is(err, ErrNotPersisted) {
+		t.Fatalf("Throw() error %v, want ErrNotPersisted", err)
+	}
+	if record.Entry.Seq != 2 || len(l.Entries()) != 2 {
+		t.Errorf("record seq %d, %d entries after the failed persist", record.Entry.Seq, len(l.Entries()))
+	}
+	if _, err := l.ThrowVisit([]Dart{{Segment: 1, Multiplier: 1}}); !errors.Is(err, ErrNotPersisted) {
+		t.Errorf("ThrowVisit() error %v, want ErrNotPersisted", err)
+	}
+
+	fail = false
+	if _, err := l.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(persisted, l.Entries()) {
+		t.Errorf("persisted %d entries, want %d", len(persisted), len(l.Entries()))
+	}
+}
+
+func TestReplayMatchLogErrors(t *testing.T) {
+	l := newTestMatchLog(t)
+	if err := throw("T20")(l); err != nil {
+		t.Fatal(err)
+	}
+	valid := l.Entries()
+	gap := append([]LogEntry(nil), valid...)
+	gap[1].Seq = 3
+	invalid := append([]LogEntry(nil), valid...)
+	invalid[1].Dart = &Dart{Segment: 21, Multiplier: 1}
+	for name, entries := range map[string][]LogEntry{
+		"empty":          nil,
+		"without create": valid[1:],
+		"gap":            gap,
+		"invalid dart":   invalid,
+	} {
+		if _, err := ReplayMatchLog(entries); err == nil {
+			t.Errorf("%s: ReplayMatchLog() succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,511 @@
+package gameengine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrNoSuchDart = errors.New("no such dart")
+
+// MatchOptions configures a match. The legs of a match are played in any registered game mode.
+type MatchOptions struct {
+	Mode         Mode            `json:"mode"`
+	GameOptions  json.RawMessage `json:"gameOptions,omitempty"` // options of the game mode, defaults of the mode if empty
+	Legs         int             `json:"legs"`                  // first to N legs wins a set (the match if played without sets)
+	Sets         int             `json:"sets"`                  // best of N sets, 0 plays the match without sets
+	TwoClearLegs bool            `json:"twoClearLegs"`          // a set is won by a lead of two legs
+	MaxLegs      int             `json:"maxLegs"`               // tie-break: with two clear legs the first player to reach N legs wins anyway (0: no limit)
+}
+
+// DefaultMatchOptions returns the options of a single leg of 501.
+func DefaultMatchOptions() MatchOptions {
+	return MatchOptions{Mode: ModeX01, Legs: 1}
+}
+
+// Validate checks the options.
+func (o MatchOptions) Validate() error {
+	if o.Legs < 1 {
+		return fmt.Errorf("invalid number of legs %d", o.Legs)
+	}
+	if o.Sets < 0 {
+		return fmt.Errorf("invalid number of sets %d", o.Sets)
+	}
+	if o.Sets%2 == 0 && o.Sets > 0 {
+		// --> best of an even number of sets can end in a draw
+		return fmt.Errorf("number of sets %d must be odd (best of N sets)", o.Sets)
+	}
+	if o.MaxLegs != 0 && (!o.TwoClearLegs || o.MaxLegs <= o.Legs) {
+		return fmt.Errorf("max legs %d requires two clear legs and more than %d legs", o.MaxLegs, o.Legs)
+	}
+	return nil
+}
+
+// setsToWin returns the number of sets a player needs to win the match.
+func (o MatchOptions) setsToWin() int {
+	if o.Sets == 0 {
+		return 1
+	}
+	return o.Sets/2 + 1
+}
+
+// LegSummary summarizes a leg of a match.
+type LegSummary struct {
+	Set     int   `json:"set"`
+	Leg     int   `json:"leg"` // number of the leg inside the set
+	Starter int   `json:"starter"`
+	Winner  int   `json:"winner"` // -1 while the leg is running or if nobody won
+	Darts   []int `json:"darts"`  // darts thrown per player
+	Visits  []int `json:"visits"` // visits per player
+	Points  []int `json:"points"` // points scored per player
+}
+
+// SetSummary summarizes a set of a match.
+type SetSummary struct {
+	Set     int   `json:"set"`
+	Starter int   `json:"starter"`
+	Winner  int   `json:"winner"` // -1 while the set is running
+	Legs    []int `json:"legs"`   // legs won per player
+}
+
+// MatchPlayerSummary summarizes the match of a player.
+type MatchPlayerSummary struct {
+	Name    string  `json:"name"`
+	Sets    int     `json:"sets"` // won sets
+	Legs    int     `json:"legs"` // won legs (all sets)
+	Darts   int     `json:"darts"`
+	Points  int     `json:"points"`
+	Average float64 `json:"average"` // three-dart average over all legs
+}
+
+// Visit is a finished visit of a match.
+type Visit struct {
+	Player int    `json:"player"`
+	Set    int    `json:"set"`
+	Leg    int    `json:"leg"`
+	Darts  []Dart `json:"darts"`
+}
+
+// MatchState is a snapshot of a match.
+type MatchState struct {
+	Options   MatchOptions         `json:"options"`
+	Players   []MatchPlayerSummary `json:"players"`
+	Set       int                  `json:"set"`    // number of the running set
+	Leg       int                  `json:"leg"`    // number of the running leg inside the set
+	Winner    int                  `json:"winner"` // -1 while the match is running or if nobody won
+	Finished  bool                 `json:"finished"`
+	Abandoned bool                 `json:"abandoned"`
+	Sets      []SetSummary         `json:"sets"`
+	Legs      []LegSummary         `json:"legs"`
+	Game      State                `json:"game"`                    // state of the running (or the last) leg
+	Previous  *Visit               `json:"previousVisit,omitempty"` // the last finished visit (can be corrected)
+}
+
+// Match plays legs of a game mode until a player won the match; a leg nobody won ends the match without a winner. The player
+// who throws first alternates from leg to leg (and from set to set). Implementations are not safe for concurrent use.
+type Match struct {
+	players   []string
+	options   MatchOptions
+	leg       Rules
+	inputs    []input
+	sets      []SetSummary
+	legs      []LegSummary
+	visit     int    // number of the running visit
+	prev      *Visit // the last finished visit
+	winner    int
+	over      bool
+	abandoned bool
+}
+
+// NewMatch starts a match for the hand-overed players. The first leg is started right away.
+func NewMatch(players []string, options MatchOptions) (*Match, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("NewMatch() - error: %v", err)
+	}
+	if err := validatePlayers(players); err != nil {
+		return nil, fmt.Errorf("NewMatch() - error: %v", err)
+	}
+	// --> compact the options, so the options of a replayed (json encoded) match are identical
+	var gameOptions bytes.Buffer
+	if len(options.GameOptions) > 0 {
+		if err := json.Compact(&gameOptions, options.GameOptions); err != nil {
+			return nil, fmt.Errorf("NewMatch() - error: invalid game options: %v", err)
+		}
+	}
+	options.GameOptions = append(json.RawMessage(nil), gameOptions.Bytes()...)
+
+	m := &Match{
+		players: append([]string(nil), players...),
+		options: options,
+		winner:  -1,
+	}
+	if _, err := m.startSet(); err != nil {
+		return nil, fmt.Errorf("NewMatch() - error: %v", err)
+	}
+	return m, nil
+}
+
+// Mode returns the game mode of the legs.
+func (m *Match) Mode() Mode {
+	return m.options.Mode
+}
+
+// Throw counts a dart in the running leg. A won leg starts the next leg (or set) unless the match is over.
+func (m *Match) Throw(d Dart) ([]Event, error) {
+	if m.over {
+		return nil, fmt.Errorf("Throw() - error: %w", ErrGameOver)
+	}
+	events, err := m.leg.Throw(d)
+	if err != nil {
+		return nil, err
+	}
+	m.record(input{dart: d}, events)
+	return m.apply(events)
+}
+
+// EndVisit ends the running visit of the running leg early.
+func (m *Match) EndVisit() ([]Event, error) {
+	if m.over {
+		return nil, fmt.Errorf("EndVisit() - error: %w", ErrGameOver)
+	}
+	events, err := m.leg.EndVisit()
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	m.record(input{endVisit: true}, events)
+	return m.apply(events)
+}
+
+// Abandon ends the match without a winner.
+func (m *Match) Abandon() ([]Event, error) {
+	if m.over {
+		return nil, fmt.Errorf("Abandon() - error: %w", ErrGameOver)
+	}
+	m.over = true
+	m.abandoned = true
+	return []Event{{Type: EventMatchAbandoned, Player: m.leg.State().CurrentPlayer}}, nil
+}
+
+// Undo reverts the last dart (or the early end of a visit). A leg (set or match) won by the dart is reopened.
+func (m *Match) Undo() ([]Event, error) {
+	if m.abandoned {
+		return nil, fmt.Errorf("Undo() - error: %w", ErrGameOver)
+	}
+	if len(m.inputs) == 0 {
+		return nil, fmt.Errorf("Undo() - error: %w", ErrNothingToUndo)
+	}
+	fresh, err := NewMatch(m.players, m.options)
+	if err != nil {
+		return nil, fmt.Errorf("Undo() - error: %v", err)
+	}
+	last := m.inputs[len(m.inputs)-1]
+	if err := replay(fresh, m.inputs[:len(m.inputs)-1]); err != nil {
+		return nil, fmt.Errorf("Undo() - error: %v", err)
+	}
+	*m = *fresh
+
+	event := Event{Type: EventThrowUndone, Player: m.leg.State().CurrentPlayer}
+	if !last.endVisit {
+		dart := last.dart
+		event.Dart = &dart
+	}
+	return []Event{event}, nil
+}
+
+// Correct replaces a dart of the running visit (or of the previous visit) and recomputes everything that follows, e.g. busts,
+// won legs and the statistics. A dart of the previous visit that was not thrown (the visit ended early) can be added unless the
+// visit ended with a bust or a checkout before it; if the corrected visit ends earlier than before its remaining darts are
+// dropped. The correction event contains the darts of the visit that were counted.
+func (m *Match) Correct(previous bool, index int, d Dart) ([]Event, error) {
+	if m.abandoned {
+		return nil, fmt.Errorf("Correct() - error: %w", ErrGameOver)
+	}
+	if err := d.Validate(); err != nil {
+		return nil, fmt.Errorf("Correct() - error: %v", err)
+	}
+	target := m.visit
+	if previous {
+		target--
+	}
+	first, last := -1, -1
+	for i, in := range m.inputs {
+		if in.visit == target {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 || index < 0 || index >= DartsPerVisit {
+		return nil, fmt.Errorf("Correct() - error: dart %d: %w", index, ErrNoSuchDart)
+	}
+
+	group := append([]input(nil), m.inputs[first:last+1]...)
+	var darts []int // positions of the darts inside the group
+	for i, in := range group {
+		if !in.endVisit {
+			darts = append(darts, i)
+		}
+	}
+	old := Miss
+	switch {
+	case index < len(darts):
+		old = group[darts[index]].dart
+		group[darts[index]].dart = d
+	case previous:
+		// --> the dart was not counted: fill up the visit before its early end
+		var added []input
+		for len(darts)+len(added) < index {
+			added = append(added, input{dart: Miss, visit: target, player: group[0].player})
+		}
+		added = append(added, input{dart: d, visit: target, player: group[0].player})
+		at := len(group)
+		if group[at-1].endVisit {
+			at--
+		}
+		group = append(group[:at], append(added, group[at:]...)...)
+	default:
+		return nil, fmt.Errorf("Correct() - error: dart %d: %w", index, ErrNoSuchDart)
+	}
+
+	inputs := append(append(append([]input(nil), m.inputs[:first]...), group...), m.inputs[last+1:]...)
+	fresh, err := NewMatch(m.players, m.options)
+	if err != nil {
+		return nil, fmt.Errorf("Correct() - error: %v", err)
+	}
+	if err := fresh.replayVisits(inputs, m.visit); err != nil {
+		return nil, fmt.Errorf("Correct() - error: %v", err)
+	}
+	// --> the replay drops the darts after a bust or a checkout, the event reports the darts that were counted
+	var visit []Dart
+	for _, in := range fresh.inputs {
+		if in.visit == target && !in.endVisit {
+			visit = append(visit, in.dart)
+		}
+	}
+	if index >= len(visit) {
+		return nil, fmt.Errorf("Correct() - error: dart %d: the visit ended before the dart: %w", index, ErrNoSuchDart)
+	}
+	*m = *fresh
+
+	return []Event{{Type: EventCorrection, Player: group[0].player, Dart: &d, Previous: &old, Visit: visit}}, nil
+}
+
+// State returns a snapshot of the match.
+func (m *Match) State() MatchState {
+	state := MatchState{
+		Options:   m.options,
+		Winner:    m.winner,
+		Finished:  m.over,
+		Abandoned: m.abandoned,
+		Game:      m.leg.State(),
+	}
+	if m.prev != nil {
+		prev := *m.prev
+		prev.Darts = append([]Dart(nil), m.prev.Darts...)
+		state.Previous = &prev
+	}
+	state.Options.GameOptions = append(json.RawMessage(nil), m.options.GameOptions...)
+
+	for _, name := range m.players {
+		state.Players = append(state.Players, MatchPlayerSummary{Name: name})
+	}
+	for _, s := range m.sets {
+		s.Legs = append([]int(nil), s.Legs...)
+		state.Sets = append(state.Sets, s)
+		if s.Winner >= 0 {
+			state.Players[s.Winner].Sets++
+		}
+	}
+	for _, l := range m.legs {
+		l.Darts = append([]int(nil), l.Darts...)
+		l.Visits = append([]int(nil), l.Visits...)
+		l.Points = append([]int(nil), l.Points...)
+		state.Legs = append(state.Legs, l)
+		if l.Winner >= 0 {
+			state.Players[l.Winner].Legs++
+		}
+		for i := range state.Players {
+			state.Players[i].Darts += l.Darts[i]
+			state.Players[i].Points += l.Points[i]
+		}
+	}
+	for i := range state.Players {
+		state.Players[i].Average = average(state.Players[i].Points, state.Players[i].Darts)
+	}
+
+	leg := m.legs[len(m.legs)-1]
+	state.Set, state.Leg = leg.Set, leg.Leg
+	return state
+}
+
+// Winner returns the index of the player who won the match.
+func (m *Match) Winner() (int, bool) {
+	return m.winner, m.winner >= 0
+}
+
+// AllowedInputs returns the darts that count for the current player of the running leg.
+func (m *Match) AllowedInputs() []Dart {
+	if m.over {
+		return nil
+	}
+	return m.leg.AllowedInputs()
+}
+
+// record adds an input to the running visit.
+func (m *Match) record(in input, events []Event) {
+	in.visit = m.visit
+	in.player = events[0].Player
+	m.inputs = append(m.inputs, in)
+}
+
+// replayVisits applies the recorded inputs visit by visit. Inputs of a visit that already ended (bust or checkout) are
+// dropped; a visit before the running visit that did not end is ended early. Inputs after the end of the match are dropped.
+func (m *Match) replayVisits(inputs []input, running int) error {
+	for start := 0; start < len(inputs) && !m.over; {
+		end := start
+		for end < len(inputs) && inputs[end].visit == inputs[start].visit {
+			end++
+		}
+		visit := m.visit
+		for _, in := range inputs[start:end] {
+			if m.over || m.visit != visit {
+				break
+			}
+			var err error
+			if in.endVisit {
+				_, err = m.EndVisit()
+			} else {
+				_, err = m.Throw(in.dart)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if inputs[start].visit < running && m.visit == visit && !m.over {
+			if _, err := m.EndVisit(); err != nil {
+				return err
+			}
+		}
+		start = end
+	}
+	return nil
+}
+
+// apply adds the events of the running leg to its summary and moves on to the next leg once the leg is over.
+func (m *Match) apply(events []Event) ([]Event, error) {
+	leg := &m.legs[len(m.legs)-1]
+	for _, e := range events {
+		if e.Type == EventVisitEnded {
+			leg.Darts[e.Player] += len(e.Visit)
+			leg.Visits[e.Player]++
+			leg.Points[e.Player] += e.Points
+			m.prev = &Visit{Player: e.Player, Set: leg.Set, Leg: leg.Leg, Darts: e.Visit}
+			m.visit++
+		}
+	}
+	if !m.leg.State().Finished {
+		return events, nil
+	}
+
+	winner, ok := m.leg.Winner()
+	if !ok {
+		// nobody won (e.g. a solo player lost a training leg) --> the match ends without a winner, a repeated leg could
+		// be lost forever
+		m.over = true
+		return append(events, Event{Type: EventMatchEnded, Player: m.leg.State().CurrentPlayer}), nil
+	}
+	leg.Winner = winner
+
+	set := &m.sets[len(m.sets)-1]
+	set.Legs[winner]++
+	if !m.setWon(set, winner) {
+		event, err := m.startLeg()
+		if err != nil {
+			return events, err
+		}
+		return append(events, event), nil
+	}
+
+	set.Winner = winner
+	if m.options.Sets > 0 {
+		events = append(events, Event{Type: EventSetWon, Player: winner, Set: set.Set})
+	}
+	won := 0
+	for _, s := range m.sets {
+		if s.Winner == winner {
+			won++
+		}
+	}
+	if won >= m.options.setsToWin() {
+		m.winner = winner
+		m.over = true
+		return append(events, Event{Type: EventMatchWon, Player: winner}), nil
+	}
+	event, err := m.startSet()
+	if err != nil {
+		return events, err
+	}
+	return append(events, event), nil
+}
+
+// setWon reports whether the player won the set with the last leg.
+func (m *Match) setWon(set *SetSummary, player int) bool {
+	legs := set.Legs[player]
+	if legs < m.options.Legs {
+		return false
+	}
+	if !m.options.TwoClearLegs || (m.options.MaxLegs > 0 && legs >= m.options.MaxLegs) {
+		return true
+	}
+	for i, other := range set.Legs {
+		if i != player && legs-other < 2 {
+			return false
+		}
+	}
+	return true
+}
+
+// startSet starts a new set with its first leg. The first player of the set alternates from set to set.
+func (m *Match) startSet() (Event, error) {
+	number := len(m.sets) + 1
+	m.sets = append(m.sets, SetSummary{
+		Set:     number,
+		Starter: (number - 1) % len(m.players),
+		Winner:  -1,
+		Legs:    make([]int, len(m.players)),
+	})
+	return m.startLeg()
+}
+
+// startLeg starts a new leg in the running set. The first player of the leg alternates from leg to leg.
+func (m *Match) startLeg() (Event, error) {
+	set := m.sets[len(m.sets)-1]
+	number := 1
+	for _, l := range m.legs {
+		if l.Set == set.Set {
+			number++
+		}
+	}
+	starter := (set.Starter + number - 1) % len(m.players)
+
+	leg, err := NewGame(m.options.Mode, m.players, m.options.GameOptions)
+	if err != nil {
+		return Event{}, err
+	}
+	if err := leg.SetStarter(starter); err != nil {
+		return Event{}, err
+	}
+	m.leg = leg
+	m.legs = append(m.legs, LegSummary{
+		Set:     set.Set,
+		Leg:     number,
+		Starter: starter,
+		Winner:  -1,
+		Darts:   make([]int, len(m.players)),
+		Visits:  make([]int, len(m.players)),
+		Points:  make([]int, len(m.players)),
+	})
+	return Event{Type: EventLegStarted, Player: starter, Set: set.Set, Leg: number}, nil
+}
@@ -0,0 +1,150 @@
+package gameengine
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	options := MatchOptions{Mode: ModeX01, GameOptions: json.RawMessage(`{"startScore":40,"out":"single"}`), Legs: 2}
+	m, err := NewMatch([]string{"A", "B"}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		darts   string
+		events  string
+		leg     int
+		current int
+	}{
+		// --> the starter alternates from leg to leg
+		{"D20", "throw-registered,visit-ended,leg-won,leg-started", 2, 1},
+		{"S1 S1 S1", "throw-registered,throw-registered,throw-registered,visit-ended,player-changed", 2, 0},
+		{"D20", "throw-registered,visit-ended,leg-won,match-won", 2, 0},
+	}
+	for _, tt := range tests {
+		events := play(t, m, tt.darts)
+		state := m.State()
+		if eventTypes(events) != tt.events || state.Leg != tt.leg || state.Game.CurrentPlayer != tt.current {
+			t.Fatalf("%s: events %s, leg %d, current player %d, want %s, %d, %d", tt.darts, eventTypes(events), state.Leg,
+				state.Game.CurrentPlayer, tt.events, tt.leg, tt.current)
+		}
+	}
+	state := m.State()
+	if w, ok := m.Winner(); !ok || w != 0 || !state.Finished || state.Players[0].Legs != 2 {
+		t.Errorf("winner %d (%v), finished %v, legs %d", w, ok, state.Finished, state.Players[0].Legs)
+	}
+	if _, err := m.Throw(Miss); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Throw() after the match: %v", err)
+	}
+
+	// --> undo reopens the match and the leg
+	if _, err := m.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	state = m.State()
+	if _, ok := m.Winner(); ok || state.Finished || state.Leg != 2 || state.Players[0].Legs != 1 {
+		t.Errorf("after Undo(): finished %v, leg %d, legs %d", state.Finished, state.Leg, state.Players[0].Legs)
+	}
+}
+
+func TestMatchOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options MatchOptions
+		wantErr bool
+	}{
+		{name: "single leg", options: MatchOptions{Legs: 1}},
+		{name: "best of 3 sets", options: MatchOptions{Legs: 3, Sets: 3}},
+		{name: "two clear legs with tie-break", options: MatchOptions{Legs: 3, TwoClearLegs: true, MaxLegs: 5}},
+		{name: "no legs", options: MatchOptions{Legs: 0}, wantErr: true},
+		{name: "negative sets", options: MatchOptions{Legs: 1, Sets: -1}, wantErr: true},
+		{name: "even sets", options: MatchOptions{Legs: 1, Sets: 2}, wantErr: true},
+		{name: "tie-break without two clear legs", options: MatchOptions{Legs: 3, MaxLegs: 5}, wantErr: true},
+		{name: "tie-break below the legs", options: MatchOptions{Legs: 3, TwoClearLegs: true, MaxLegs: 3}, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+// a leg nobody won must not be repeated forever
+func TestMatchWithoutWinner(t *testing.T) {
+	m, err := NewMatch([]string{"A"}, MatchOptions{Mode: ModeBobs27, Legs: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 27 - 2 - 4 - 6 - 8 - 10 < 0
+	events := play(t, m, "MISS MISS MISS MISS MISS MISS MISS MISS MISS MISS MISS MISS MISS MISS MISS")
+	if last := events[len(events)-1]; last.Type != EventMatchEnded {
+		t.Fatalf("events %s, want match-ended", eventTypes(events))
+	}
+	state := m.State()
+	if _, ok := m.Winner(); ok || !state.Finished || state.Abandoned || len(state.Legs) != 1 || state.Legs[0].Winner != -1 {
+		t.Errorf("finished %v, abandoned %v, legs %+v", state.Finished, state.Abandoned, state.Legs)
+	}
+	if _, err := m.Throw(Miss); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Throw() after the match: %v", err)
+	}
+	if _, err := m.Undo(); err != nil || m.State().Finished {
+		t.Errorf("Undo() of the last dart: %v, finished %v", err, m.State().Finished)
+	}
+}
+
+func TestMatchCorrect(t *testing.T) {
+	options := MatchOptions{Mode: ModeX01, GameOptions: json.RawMessage(`{"startScore":101}`), Legs: 1}
+	tests := []struct {
+		name     string
+		darts    string
+		previous bool
+		index    int
+		dart     string
+		visit    string // darts of the corrected visit reported by the event, empty if the correction fails
+		scores   [2]int
+	}{
+		{"running visit", "T20 S1 S1 | S5", false, 0, "S20", "S20", [2]int{39, 81}},
+		{"previous visit", "T20 S1 S1 | S5", true, 2, "S19", "T20 S1 S19", [2]int{21, 96}},
+		{"previous visit busts earlier", "T20 S1 S1 | S5", true, 1, "T19", "T20 T19", [2]int{101, 96}},
+		{"add a dart to a visit ended early", "T20 | S5", true, 1, "S20", "T20 S20", [2]int{21, 96}},
+		{"add a dart after a bust", "T20 T20 | S5", true, 2, "D20", "", [2]int{101, 96}},
+		{"add a dart after a checkout", "T20 S1 S1 | S5 | S1 D19", true, 2, "S1", "", [2]int{0, 96}},
+	}
+	for _, tt := range tests {
+		m, err := NewMatch([]string{"A", "B"}, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		play(t, m, tt.darts)
+		before := m.State()
+		d, _ := ParseDart(tt.dart)
+		events, err := m.Correct(tt.previous, tt.index, d)
+		switch {
+		case tt.visit == "" && !errors.Is(err, ErrNoSuchDart):
+			t.Errorf("%s: Correct(): %v, want %v", tt.name, err, ErrNoSuchDart)
+			continue
+		case tt.visit == "" && !reflect.DeepEqual(m.State(), before):
+			t.Errorf("%s: a rejected correction changed the match", tt.name)
+			continue
+		case tt.visit != "" && err != nil:
+			t.Errorf("%s: Correct(): %v", tt.name, err)
+			continue
+		case tt.visit != "":
+			var visit []string
+			for _, dart := range events[0].Visit {
+				visit = append(visit, dart.String())
+			}
+			if strings.Join(visit, " ") != tt.visit {
+				t.Errorf("%s: corrected visit %v, want %s", tt.name, visit, tt.visit)
+			}
+		}
+		players := m.State().Game.Details.(X01State).Players
+		if scores := [2]int{players[0].Score, players[1].Score}; scores != tt.scores {
+			t.Errorf("%s: scores %v, want %v", tt.name, scores, tt.scores)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package gameengine
+
+import (
+	"fmt"
+)
+
+// ShanghaiOptions configures a game of shanghai.
+type ShanghaiOptions struct {
+	Rounds int `json:"rounds"` // number of rounds (1-20), round n is played on the number n
+}
+
+// DefaultShanghaiOptions returns the options of the classic game with seven rounds.
+func DefaultShanghaiOptions() ShanghaiOptions {
+	return ShanghaiOptions{Rounds: 7}
+}
+
+// ShanghaiPlayerState is the state of a player of shanghai.
+type ShanghaiPlayerState struct {
+	Name        string `json:"name"`
+	Score       int    `json:"score"`
+	Round       int    `json:"round"` // round (and number) the player plays next
+	DartsThrown int    `json:"dartsThrown"`
+}
+
+// ShanghaiState contains the details of a shanghai snapshot.
+type ShanghaiState struct {
+	Options ShanghaiOptions       `json:"options"`
+	Players []ShanghaiPlayerState `json:"players"`
+}
+
+// Shanghai is played over a number of rounds; in round n only the number n scores. A visit with a single, a double and a treble
+// of the round number (a "shanghai") wins immediately. Otherwise the highest score after the last round wins; a tie is won
+// by the player who threw first.
+type Shanghai struct {
+	turn
+	names   []string
+	options ShanghaiOptions
+	players []ShanghaiPlayerState
+}
+
+// NewShanghai starts a game of shanghai for the hand-overed players.
+func NewShanghai(players []string, options ShanghaiOptions) (*Shanghai, error) {
+	if options.Rounds < 1 || options.Rounds > 20 {
+		return nil, fmt.Errorf("NewShanghai() - error: invalid number of rounds %d", options.Rounds)
+	}
+	if err := validatePlayers(players); err != nil {
+		return nil, fmt.Errorf("NewShanghai() - error: %v", err)
+	}
+	g := &Shanghai{
+		turn:    newTurn(len(players)),
+		names:   append([]string(nil), players...),
+		options: options,
+	}
+	for _, name := range players {
+		g.players = append(g.players, ShanghaiPlayerState{Name: name, Round: 1})
+	}
+	return g, nil
+}
+
+// Mode returns the game mode.
+func (g *Shanghai) Mode() Mode {
+	return ModeShanghai
+}
+
+// Throw counts a dart of the current player.
+func (g *Shanghai) Throw(d Dart) ([]Event, error) {
+	if err := g.checkThrow(d); err != nil {
+		return nil, err
+	}
+
+	p := &g.players[g.current]
+	g.startThrow(d)
+	p.DartsThrown++
+
+	points := 0
+	if !d.IsMiss() && d.Segment == p.Round {
+		points = d.Value()
+	}
+	p.Score += points
+	g.visitPoints += points
+
+	events := []Event{g.throwEvent(d, points, 0)}
+	if g.isShanghai(p.Round) {
+		return append(events, g.win(g.current)...), nil
+	}
+	if len(g.visit) == DartsPerVisit {
+		events = append(events, g.completeVisit()...)
+	}
+	return events, nil
+}
+
+// EndVisit ends the running visit early. The missing darts count as misses.
+func (g *Shanghai) EndVisit() ([]Event, error) {
+	misses, ok, err := g.checkEndVisit()
+	if !ok {
+		return nil, err
+	}
+	g.players[g.current].DartsThrown += misses
+	return g.completeVisit(), nil
+}
+
+// Undo reverts the last dart (or the early end of a visit).
+func (g *Shanghai) Undo() ([]Event, error) {
+	fresh, err := NewShanghai(g.names, g.options)
+	if err != nil {
+		return nil, err
+	}
+	event, err := g.undo(fresh)
+	if err != nil {
+		return nil, err
+	}
+	*g = *fresh
+	return []Event{event}, nil
+}
+
+// State returns a snapshot of the game.
+func (g *Shanghai) State() State {
+	return g.state(ModeShanghai, ShanghaiState{
+		Options: g.options,
+		Players: append([]ShanghaiPlayerState(nil), g.players...),
+	})
+}
+
+// AllowedInputs returns the darts on the round number of the current player.
+func (g *Shanghai) AllowedInputs() []Dart {
+	if g.over {
+		return nil
+	}
+	return segmentDarts(g.players[g.current].Round)
+}
+
+// isShanghai reports whether the running visit contains a single, a double and a treble of the number.
+func (g *Shanghai) isShanghai(number int) bool {
+	var multipliers [4]bool
+	for _, d := range g.visit {
+		if d.Segment == number {
+			multipliers[d.Multiplier] = true
+		}
+	}
+	return multipliers[1] && multipliers[2] && multipliers[3]
+}
+
+// completeVisit moves the current player to the next round. The game ends after the last round of the last player.
+func (g *Shanghai) completeVisit() []Event {
+	g.players[g.current].Round++
+	if g.current == g.lastPlayer() && g.players[g.current].Round > g.options.Rounds {
+		return g.win(g.highest(func(i int) int { return g.players[i].Score }))
+	}
+	return g.endVisit()
+}
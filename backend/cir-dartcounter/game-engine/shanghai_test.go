@@ -0,0 +1,43 @@
+package gameengine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShanghai(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ShanghaiOptions
+		darts   string
+		scores  []int
+		winner  int // -1 if the game is running
+	}{
+		{"only the round number scores", ShanghaiOptions{Rounds: 2}, "S1 D1 S2 | S1 S1 S1", []int{3, 3}, -1},
+		{"second round", ShanghaiOptions{Rounds: 2}, "S1 D1 S2 | S1 S1 S1 | S2 T2 MISS", []int{11, 3}, -1},
+		{"highest score after the last round", ShanghaiOptions{Rounds: 2}, "S1 D1 S2 | S1 S1 S1 | S2 T2 MISS | S2 S2 S2", []int{11, 9}, 0},
+		{"a tie is won by the first player", ShanghaiOptions{Rounds: 1}, "S1 | S1 |", []int{1, 1}, 0},
+		{"shanghai wins immediately", DefaultShanghaiOptions(), "T1 S1 D1", []int{6, 0}, 0},
+	}
+	for _, tt := range tests {
+		g, err := NewShanghai([]string{"A", "B"}, tt.options)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		play(t, g, tt.darts)
+		var scores []int
+		for _, player := range g.State().Details.(ShanghaiState).Players {
+			scores = append(scores, player.Score)
+		}
+		if !reflect.DeepEqual(scores, tt.scores) {
+			t.Errorf("%s: scores %v, want %v", tt.name, scores, tt.scores)
+		}
+		winner, won := g.Winner()
+		if !won {
+			winner = -1
+		}
+		if winner != tt.winner {
+			t.Errorf("%s: winner %d, want %d", tt.name, winner, tt.winner)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package gameengine
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// input is a recorded input of a game: a dart or the early end of a visit.
+type input struct {
+	dart     Dart
+	endVisit bool
+	visit    int // number of the visit inside the match (recorded by a match only)
+	player   int // player of the visit (recorded by a match only)
+}
+
+// turn keeps the order of the players, the darts of the running visit and the recorded inputs. It is embedded by all game modes.
+type turn struct {
+	players     int
+	starter     int
+	current     int
+	winner      int
+	over        bool
+	visit       []Dart
+	visitPoints int
+	inputs      []input
+	eliminated  []bool // players out of the game (e.g. killed) are skipped
+}
+
+func newTurn(players int) turn {
+	return turn{players: players, winner: -1, eliminated: make([]bool, players)}
+}
+
+// SetStarter hands the first visit to the player. It fails once the leg is running.
+func (t *turn) SetStarter(player int) error {
+	if player < 0 || player >= t.players {
+		return fmt.Errorf("SetStarter() - error: invalid player %d", player)
+	}
+	if len(t.inputs) > 0 {
+		return fmt.Errorf("SetStarter() - error: leg already running")
+	}
+	t.starter = player
+	t.current = player
+	return nil
+}
+
+// checkThrow validates a dart and checks that the game is still running.
+func (t *turn) checkThrow(d Dart) error {
+	if err := d.Validate(); err != nil {
+		return fmt.Errorf("Throw() - error: %v", err)
+	}
+	if t.over {
+		return fmt.Errorf("Throw() - error: %w", ErrGameOver)
+	}
+	return nil
+}
+
+// startThrow records the dart and adds it to the running visit.
+func (t *turn) startThrow(d Dart) {
+	t.inputs = append(t.inputs, input{dart: d})
+	t.visit = append(t.visit, d)
+}
+
+// checkEndVisit checks whether the visit can be ended early. It returns false if the current player has not thrown yet.
+// Otherwise the input is recorded and the visit is filled up with misses; the number of added misses is returned.
+func (t *turn) checkEndVisit() (int, bool, error) {
+	if t.over {
+		return 0, false, fmt.Errorf("EndVisit() - error: %w", ErrGameOver)
+	}
+	if len(t.visit) == 0 {
+		return 0, false, nil
+	}
+	t.inputs = append(t.inputs, input{endVisit: true})
+	misses := 0
+	for len(t.visit) < DartsPerVisit {
+		t.visit = append(t.visit, Miss)
+		misses++
+	}
+	return misses, true, nil
+}
+
+// throwEvent returns the event of a counted dart.
+func (t *turn) throwEvent(d Dart, points, marks int) Event {
+	dart := d
+	return Event{Type: EventThrowRegistered, Player: t.current, Dart: &dart, Points: points, Marks: marks}
+}
+
+// endVisit finishes the visit of the current player and hands over to the next player who is still in the game.
+func (t *turn) endVisit() []Event {
+	events := []Event{
+		{Type: EventVisitEnded, Player: t.current, Points: t.visitPoints, Visit: t.visitCopy()},
+	}
+	t.resetVisit()
+	for i := 0; i < t.players; i++ {
+		t.current = (t.current + 1) % t.players
+		if !t.eliminated[t.current] {
+			break
+		}
+	}
+	events = append(events, Event{Type: EventPlayerChanged, Player: t.current})
+	return events
+}
+
+// endVisitIfComplete ends the visit after the last dart.
+func (t *turn) endVisitIfComplete() []Event {
+	if len(t.visit) < DartsPerVisit {
+		return nil
+	}
+	return t.endVisit()
+}
+
+// win finishes the game with the hand-overed winner. The running visit ends.
+func (t *turn) win(winner int) []Event {
+	events := []Event{
+		{Type: EventVisitEnded, Player: t.current, Points: t.visitPoints, Visit: t.visitCopy()},
+		{Type: EventLegWon, Player: winner},
+	}
+	t.resetVisit()
+	t.winner = winner
+	t.over = true
+	return events
+}
+
+// finish ends the game without a winner (e.g. a solo training game that was lost).
+func (t *turn) finish() []Event {
+	events := []Event{
+		{Type: EventVisitEnded, Player: t.current, Points: t.visitPoints, Visit: t.visitCopy()},
+	}
+	t.resetVisit()
+	t.over = true
+	return events
+}
+
+func (t *turn) resetVisit() {
+	t.visit = nil
+	t.visitPoints = 0
+}
+
+func (t *turn) visitCopy() []Dart {
+	return append([]Dart{}, t.visit...)
+}
+
+// Winner returns the index of the player who won the leg.
+func (t *turn) Winner() (int, bool) {
+	return t.winner, t.winner >= 0
+}
+
+// lastPlayer returns the player who throws the last visit of a round.
+func (t *turn) lastPlayer() int {
+	return (t.starter + t.players - 1) % t.players
+}
+
+// highest returns the player with the highest score; a tie is won by the player who threw first.
+func (t *turn) highest(score func(player int) int) int {
+	best := t.starter
+	for i := 1; i < t.players; i++ {
+		if player := (t.starter + i) % t.players; score(player) > score(best) {
+			best = player
+		}
+	}
+	return best
+}
+
+// remaining returns the players who are not eliminated.
+func (t *turn) remaining() []int {
+	var players []int
+	for i, eliminated := range t.eliminated {
+		if !eliminated {
+			players = append(players, i)
+		}
+	}
+	return players
+}
+
+// state returns the common part of a game snapshot.
+func (t *turn) state(mode Mode, details any) State {
+	return State{
+		Mode:          mode,
+		CurrentPlayer: t.current,
+		Visit:         t.visitCopy(),
+		VisitPoints:   t.visitPoints,
+		Winner:        t.winner,
+		Finished:      t.over,
+		Details:       details,
+	}
+}
+
+// undo replays all inputs except the last one on the fresh game and returns the event of the undone input.
+func (t *turn) undo(fresh Rules) (Event, error) {
+	if len(t.inputs) == 0 {
+		return Event{}, fmt.Errorf("Undo() - error: %w", ErrNothingToUndo)
+	}
+	last := t.inputs[len(t.inputs)-1]
+	if err := fresh.SetStarter(t.starter); err != nil {
+		return Event{}, fmt.Errorf("Undo() - error: %v", err)
+	}
+	if err := replay(fresh, t.inputs[:len(t.inputs)-1]); err != nil {
+		return Event{}, fmt.Errorf("Undo() - error: %v", err)
+	}
+	event := Event{Type: EventThrowUndone, Player: fresh.State().CurrentPlayer}
+	if !last.endVisit {
+		dart := last.dart
+		event.Dart = &dart
+	}
+	return event, nil
+}
+
+// thrower accepts darts and the early end of a visit (a leg or a match).
+type thrower interface {
+	Throw(d Dart) ([]Event, error)
+	EndVisit() ([]Event, error)
+}
+
+// replay applies the recorded inputs to a leg or a match.
+func replay(g thrower, inputs []input) error {
+	for _, in := range inputs {
+		var err error
+		if in.endVisit {
+			_, err = g.EndVisit()
+		} else {
+			_, err = g.Throw(in.dart)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package gameengine
+
+import (
+	"fmt"
+
+	"github.com/One-Hundred-Eighty/Circle/backend/cir-dartcounter/checkout"
+)
+
+// Rule is the in- or out-rule of an X01 game.
+type Rule string
+
+const (
+	// RuleSingle allows every dart to open or finish.
+	RuleSingle Rule = "single"
+	// RuleDouble requires a double (including the bull) to open or finish.
+	RuleDouble Rule = "double"
+	// RuleMaster requires a double or a treble (including the bull) to open or finish.
+	RuleMaster Rule = "master"
+)
+
+// allows reports whether the dart satisfies the rule.
+func (r Rule) allows(d Dart) bool {
+	switch r {
+	case RuleDouble:
+		return d.IsDouble()
+	case RuleMaster:
+		return d.IsDouble() || d.IsTreble()
+	default:
+		return !d.IsMiss()
+	}
+}
+
+func (r Rule) validate() error {
+	switch r {
+	case RuleSingle, RuleDouble, RuleMaster:
+		return nil
+	default:
+		return fmt.Errorf("invalid rule %q", r)
+	}
+}
+
+// X01Options configures an X01 game, e.g. 501 single-in double-out.
+type X01Options struct {
+	StartScore int  `json:"startScore"` // 301, 501, 701 or any custom score
+	In         Rule `json:"in"`
+	Out        Rule `json:"out"`
+	// FavouriteDoubles are the preferred finishing doubles of the checkout suggestions (segments, 25 for the bull).
+	FavouriteDoubles []int `json:"favouriteDoubles,omitempty"`
+}
+
+// DefaultX01Options returns the options of the standard game: 501 single-in double-out.
+func DefaultX01Options() X01Options {
+	return X01Options{
+		StartScore: 501,
+		In:         RuleSingle,
+		Out:        RuleDouble,
+	}
+}
+
+// Validate checks the options. Missing rules default to single-in and double-out.
+func (o *X01Options) Validate() error {
+	if o.In == "" {
+		o.In = RuleSingle
+	}
+	if o.Out == "" {
+		o.Out = RuleDouble
+	}
+	if o.StartScore < 2 {
+		return fmt.Errorf("invalid start score %d", o.StartScore)
+	}
+	if err := o.In.validate(); err != nil {
+		return fmt.Errorf("in-rule: %v", err)
+	}
+	if err := o.Out.validate(); err != nil {
+		return fmt.Errorf("out-rule: %v", err)
+	}
+	checkoutOptions := o.checkoutOptions()
+	return checkoutOptions.Validate()
+}
+
+// checkoutOptions returns the options of the checkout suggestions.
+func (o X01Options) checkoutOptions() checkout.Options {
+	return checkout.Options{
+		Out:              checkout.Rule(o.Out),
+		FavouriteDoubles: append([]int(nil), o.FavouriteDoubles...),
+	}
+}
+
+// X01PlayerState is the state of a player of an X01 game.
+type X01PlayerState struct {
+	Name        string  `json:"name"`
+	Score       int     `json:"score"`  // remaining score
+	Opened      bool    `json:"opened"` // the in-rule was satisfied
+	DartsThrown int     `json:"dartsThrown"`
+	Points      int     `json:"points"`             // counted points (busted visits excluded)
+	Average     float64 `json:"average"`            // three-dart average
+	Checkout    []Dart  `json:"checkout,omitempty"` // recommended finish with the darts left in the (next) visit
+}
+
+// X01State contains the details of an X01 game snapshot.
+type X01State struct {
+	Options X01Options       `json:"options"`
+	Players []X01PlayerState `json:"players"`
+}
+
+// X01 counts a leg of 301, 501, 701 or a custom start score. The players throw in the order they were handed over.
+type X01 struct {
+	turn
+	names   []string
+	options X01Options
+	players []X01PlayerState
+
+	// state at the start of the running visit (restored on a bust)
+	visitStartScore  int
+	visitStartOpened bool
+}
+
+// NewX01 starts an X01 game for the hand-overed players.
+func NewX01(players []string, options X01Options) (*X01, error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("NewX01() - error: %v", err)
+	}
+	if err := validatePlayers(players); err != nil {
+		return nil, fmt.Errorf("NewX01() - error: %v", err)
+	}
+	g := &X01{
+		turn:    newTurn(len(players)),
+		names:   append([]string(nil), players...),
+		options: options,
+	}
+	for _, name := range players {
+		g.players = append(g.players, X01PlayerState{
+			Name:   name,
+			Score:  options.StartScore,
+			Opened: options.In == RuleSingle,
+		})
+	}
+	return g, nil
+}
+
+// Mode returns the game mode.
+func (g *X01) Mode() Mode {
+	return ModeX01
+}
+
+// Throw counts a dart of the current player. The visit ends after the third dart, a bust or a checkout;
+// afterwards the next player is up.
+func (g *X01) Throw(d Dart) ([]Event, error) {
+	if err := g.checkThrow(d); err != nil {
+		return nil, err
+	}
+
+	p := &g.players[g.current]
+	if len(g.visit) == 0 {
+		g.visitStartScore = p.Score
+		g.visitStartOpened = p.Opened
+	}
+	g.startThrow(d)
+	p.DartsThrown++
+
+	if !p.Opened && g.options.In.allows(d) {
+		p.Opened = true
+	}
+	points := 0
+	if p.Opened {
+		points = d.Value()
+	}
+	remaining := p.Score - points
+
+	events := []Event{g.throwEvent(d, points, 0)}
+	switch {
+	case g.isBust(remaining, d):
+		// --> revert the whole visit
+		p.Points -= g.visitPoints
+		p.Score = g.visitStartScore
+		p.Opened = g.visitStartOpened
+		g.visitPoints = 0
+		events = append(events, Event{Type: EventBust, Player: g.current, Visit: g.visitCopy()})
+		events = append(events, g.endVisit()...)
+	case remaining == 0:
+		p.Score = 0
+		p.Points += points
+		g.visitPoints += points
+		events = append(events, g.win(g.current)...)
+	default:
+		p.Score = remaining
+		p.Points += points
+		g.visitPoints += points
+		events = append(events, g.endVisitIfComplete()...)
+	}
+	p.Average = average(p.Points, p.DartsThrown)
+	return events, nil
+}
+
+// EndVisit ends the running visit early (e.g. the darts were taken out of the board). The missing darts count as misses.
+// Nothing happens if the current player has not thrown yet.
+func (g *X01) EndVisit() ([]Event, error) {
+	misses, ok, err := g.checkEndVisit()
+	if !ok {
+		return nil, err
+	}
+	p := &g.players[g.current]
+	p.DartsThrown += misses
+	p.Average = average(p.Points, p.DartsThrown)
+	return g.endVisit(), nil
+}
+
+// Undo reverts the last dart (or the early end of a visit).
+func (g *X01) Undo() ([]Event, error) {
+	fresh, err := NewX01(g.names, g.options)
+	if err != nil {
+		return nil, err
+	}
+	event, err := g.undo(fresh)
+	if err != nil {
+		return nil, err
+	}
+	*g = *fresh
+	return []Event{event}, nil
+}
+
+// State returns a snapshot of the game including the checkout suggestions.
+func (g *X01) State() State {
+	options := g.options
+	options.FavouriteDoubles = append([]int(nil), g.options.FavouriteDoubles...)
+	players := append([]X01PlayerState(nil), g.players...)
+	for i := range players {
+		players[i].Checkout = g.checkout(i)
+	}
+	return g.state(ModeX01, X01State{
+		Options: options,
+		Players: players,
+	})
+}
+
+// AllowedInputs returns the darts that count for the current player: all darts or the darts of the in-rule if the player has not opened yet.
+func (g *X01) AllowedInputs() []Dart {
+	if g.over {
+		return nil
+	}
+	if g.players[g.current].Opened {
+		return allDarts()
+	}
+	var darts []Dart
+	for _, d := range allDarts() {
+		if g.options.In.allows(d) {
+			darts = append(darts, d)
+		}
+	}
+	return darts
+}
+
+// isBust reports whether a dart that leaves the hand-overed remaining score busts the visit.
+func (g *X01) isBust(remaining int, d Dart) bool {
+	switch {
+	case remaining < 0:
+		return true
+	case remaining == 0:
+		return !g.options.Out.allows(d)
+	case remaining == 1:
+		// --> no double or treble is left to finish
+		return g.options.Out != RuleSingle
+	default:
+		return false
+	}
+}
+
+// checkout returns the recommended finish of a player with the darts left in the running visit (three darts for the other
+// players) or nil if the player cannot finish.
+func (g *X01) checkout(player int) []Dart {
+	p := g.players[player]
+	if g.over || !p.Opened {
+		return nil
+	}
+	darts := DartsPerVisit
+	if player == g.current {
+		darts -= len(g.visit)
+	}
+	suggestion, ok := checkout.Suggest(p.Score, darts, g.options.checkoutOptions())
+	if !ok {
+		return nil
+	}
+	var result []Dart
+	for _, d := range suggestion {
+		result = append(result, Dart{Segment: d.Segment, Multiplier: d.Multiplier})
+	}
+	return result
+}
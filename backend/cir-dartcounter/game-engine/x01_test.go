@@ -0,0 +1,114 @@
+package gameengine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestX01(t *testing.T) {
+	tests := []struct {
+		name    string
+		options X01Options
+		darts   string
+		score   int  // remaining score of the first player
+		opened  bool // the first player is opened
+		won     bool
+		events  string // events of the last visit, checked if not empty
+	}{
+		{"visit", X01Options{StartScore: 501}, "T20 T20 T20", 321, true, false, ""},
+		{"bust", X01Options{StartScore: 40}, "S20 S19", 40, true, false,
+			"throw-registered,throw-registered,bust,visit-ended,player-changed"},
+		{"bust leaving one", X01Options{StartScore: 20}, "S19", 20, true, false, ""},
+		{"double out without double", X01Options{StartScore: 40}, "S20 S20", 40, true, false, ""},
+		{"double out", X01Options{StartScore: 40}, "S10 D15", 0, true, true, ""},
+		{"bull finishes double out", X01Options{StartScore: 50}, "BULL", 0, true, true, ""},
+		{"master out treble", X01Options{StartScore: 60, Out: RuleMaster}, "T20", 0, true, true, ""},
+		{"master out single", X01Options{StartScore: 20, Out: RuleMaster}, "S20", 20, true, false, ""},
+		{"single out", X01Options{StartScore: 20, Out: RuleSingle}, "S19 S1", 0, true, true, ""},
+		{"double in", X01Options{StartScore: 301, In: RuleDouble}, "T20 D10 S5", 276, true, false, ""},
+		{"double in not opened", X01Options{StartScore: 301, In: RuleDouble}, "T20 T20 S5", 301, false, false, ""},
+		{"bust reverts the opening", X01Options{StartScore: 30, In: RuleDouble}, "D10 T20", 30, false, false, ""},
+		{"master in", X01Options{StartScore: 301, In: RuleMaster}, "S20 T5 S20", 266, true, false, ""},
+		{"takeout", X01Options{StartScore: 501}, "T20 |", 441, true, false,
+			"throw-registered,visit-ended,player-changed"},
+	}
+	for _, tt := range tests {
+		g, err := NewX01([]string{"A"}, tt.options)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		events := play(t, g, tt.darts)
+		player := g.State().Details.(X01State).Players[0]
+		if player.Score != tt.score || player.Opened != tt.opened {
+			t.Errorf("%s: score %d (opened %v), want %d (opened %v)", tt.name, player.Score, player.Opened, tt.score, tt.opened)
+		}
+		if _, won := g.Winner(); won != tt.won {
+			t.Errorf("%s: won = %v, want %v", tt.name, won, tt.won)
+		}
+		if tt.events != "" && eventTypes(events) != tt.events {
+			t.Errorf("%s: events %s, want %s", tt.name, eventTypes(events), tt.events)
+		}
+	}
+}
+
+func TestX01Statistics(t *testing.T) {
+	g, _ := NewX01([]string{"A", "B"}, X01Options{StartScore: 501})
+	play(t, g, "T20 T20 T20 | S1 |")
+	state := g.State()
+	a := state.Details.(X01State).Players[0]
+	b := state.Details.(X01State).Players[1]
+	if a.Score != 321 || a.DartsThrown != 3 || a.Average != 180 {
+		t.Errorf("first player: %+v", a)
+	}
+	// --> a takeout counts all three darts of the visit
+	if b.Score != 500 || b.DartsThrown != 3 || b.Average != 1 {
+		t.Errorf("second player: %+v", b)
+	}
+	if state.CurrentPlayer != 0 {
+		t.Errorf("current player %d, want 0", state.CurrentPlayer)
+	}
+	if events := play(t, g, "|"); len(events) != 0 {
+		t.Errorf("takeout without darts: %s", eventTypes(events))
+	}
+}
+
+func TestX01GameOver(t *testing.T) {
+	g, _ := NewX01([]string{"A", "B"}, X01Options{StartScore: 40})
+	events := play(t, g, "S10 D15")
+	if w, ok := g.Winner(); !ok || w != 0 {
+		t.Fatalf("winner %d (%v), events %s", w, ok, eventTypes(events))
+	}
+	if !g.State().Finished {
+		t.Error("the game is not finished")
+	}
+	if _, err := g.Throw(Miss); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Throw() after the checkout: %v, want %v", err, ErrGameOver)
+	}
+}
+
+func TestX01Checkout(t *testing.T) {
+	g, _ := NewX01([]string{"A"}, X01Options{StartScore: 501})
+	play(t, g, "T20 T20 T20 | T20 T20 T20 | T20 T19")
+	player := g.State().Details.(X01State).Players[0]
+	if player.Score != 24 {
+		t.Fatalf("score %d, want 24", player.Score)
+	}
+	if len(player.Checkout) != 1 || player.Checkout[0] != (Dart{Segment: 12, Multiplier: 2}) {
+		t.Errorf("checkout %v, want [D12]", player.Checkout)
+	}
+}
+
+func TestX01Validate(t *testing.T) {
+	for _, options := range []X01Options{
+		{StartScore: 501, In: "triple"},
+		{StartScore: 501, Out: "foo"},
+		{StartScore: 1},
+	} {
+		if _, err := NewX01([]string{"A"}, options); err == nil {
+			t.Errorf("NewX01(%+v) is valid", options)
+		}
+	}
+	if _, err := NewX01(nil, DefaultX01Options()); err == nil {
+		t.Error("NewX01() without players is valid")
+	}
+}
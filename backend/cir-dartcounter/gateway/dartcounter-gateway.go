@@ -2,24 +2,124 @@ package gateway
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
-	"time"
+	"sort"
+	"strconv"
+	"sync"
 
+	gameengine "github.com/One-Hundred-Eighty/Circle/backend/cir-dartcounter/game-engine"
+	dartcounterevents "github.com/One-Hundred-Eighty/Circle/pkg/dartcounter-events"
 	dartmasterlogger "github.com/One-Hundred-Eighty/Circle/pkg/dartmaster-logger"
+	detectionpipeline "github.com/One-Hundred-Eighty/Circle/pkg/detection-pipeline"
 	"github.com/One-Hundred-Eighty/Circle/pkg/sse"
+	"github.com/gorilla/mux"
 )
 
+// DetectionPipeline provides the detected throws and takeouts.
+type DetectionPipeline interface {
+	Subscribe() <-chan detectionpipeline.Event
+	Unsubscribe(eventCh <-chan detectionpipeline.Event)
+}
+
+// MatchStore persists the logs of the matches and the sse-event ids.
+type MatchStore interface {
+	Save(matchID int, entries []gameengine.LogEntry) error
+	Load() (map[int][]gameengine.LogEntry, error)
+	LastMatchID() (int, error)
+	MoveAside(matchID int) error
+	Archive(matchID int) error
+	SaveLastEventID(id uint64) error
+	LoadLastEventID() (uint64, error)
+}
+
+// eventIDBlock is the number of sse-event ids that are reserved at once. Only the end of a block is persisted, an id
+// is never sent twice (also not after a restart).
+const eventIDBlock = 1000
+
 type dartcounterGateway struct {
-	logger    *dartmasterlogger.DartmasterLogger
-	sseServer *sse.SseServer
+	logger            *dartmasterlogger.DartmasterLogger
+	sseServer         *sse.SseServer
+	detectionPipeline DetectionPipeline
+	matchStore        MatchStore
+	mu                sync.Mutex
+	matches           map[int]*gameengine.MatchLog // logs of all started matches by id
+	matchID           int                          // id of the running match (counts the detections), 0 if no match was started
+	lastMatchID       int                          // id of the last started match
+	sendMu            sync.Mutex
+	eventID           uint64 // id of the last sse-event
+	reservedEventID   uint64 // highest persisted sse-event id that may be sent
+	pendingMu         sync.Mutex
+	pending           []detectionpipeline.Event // detections behind a throw that needs a confirmation
+	pendingMatchID    int                       // id of the match of the pending detections
+}
+
+type correctionRequest struct {
+	Previous bool            `json:"previous"` // correct the previous visit instead of the running one
+	Index    int             `json:"index"`    // index of the dart inside the visit (0-2)
+	Dart     gameengine.Dart `json:"dart"`
 }
 
-func NewDartcounterGateway(logger *dartmasterlogger.DartmasterLogger) *dartcounterGateway {
+type matchRequest struct {
+	Players []string                `json:"players"`
+	Options gameengine.MatchOptions `json:"options"`
+}
+
+type throwRequest struct {
+	Dart  *gameengine.Dart  `json:"dart,omitempty"`  // a single dart
+	Darts []gameengine.Dart `json:"darts,omitempty"` // a whole visit, less than three darts end the visit early
+}
+
+type confirmRequest struct {
+	Dart *gameengine.Dart `json:"dart,omitempty"` // the counted dart, the detected dart if empty
+}
+
+// pendingDetection is a detection of the running match that is not counted yet. The first one needs a confirmation,
+// the others wait behind it.
+type pendingDetection struct {
+	Type              detectionpipeline.EventType `json:"type"`
+	Dart              *gameengine.Dart            `json:"dart,omitempty"` // the detected dart of a throw
+	NeedsConfirmation bool                        `json:"needsConfirmation"`
+	Reason            string                      `json:"reason,omitempty"`
+	Confidence        float64                     `json:"confidence,omitempty"`
+}
+
+// matchResponse is a started match. Only the running match counts the detections.
+type matchResponse struct {
+	ID      int                   `json:"id"`
+	Running bool                  `json:"running"`
+	State   gameengine.MatchState `json:"state"`
+	Dropped []gameengine.Dart     `json:"dropped,omitempty"` // submitted darts after the visit ended early, e.g. bust or checkout (not counted)
+	Warning string                `json:"warning,omitempty"` // set if the action counts, but was not persisted (it would be lost after a restart)
+}
+
+// matchSummary is the list entry of a started match.
+type matchSummary struct {
+	ID        int             `json:"id"`
+	Running   bool            `json:"running"`
+	Mode      gameengine.Mode `json:"mode"`
+	Players   []string        `json:"players"`
+	Finished  bool            `json:"finished"`
+	Abandoned bool            `json:"abandoned"`
+	Winner    int             `json:"winner"`
+}
+
+// NewDartcounterGateway returns a new dartcounter gateway. The detection pipeline is optional (nil if no cameras are available).
+// The match store is optional as well (nil if the matches are not persisted); all persisted matches are restored and the
+// last unfinished match continues as the running match.
+func NewDartcounterGateway(logger *dartmasterlogger.DartmasterLogger, detectionPipeline DetectionPipeline, matchStore MatchStore) *dartcounterGateway {
 	dartcounterGateway := &dartcounterGateway{
-		logger:    logger,
-		sseServer: sse.NewSseServer("[dartcounter-sse] "),
+		logger:            logger,
+		sseServer:         sse.NewSseServer("[dartcounter-sse] "),
+		detectionPipeline: detectionPipeline,
+		matchStore:        matchStore,
+		matches:           make(map[int]*gameengine.MatchLog),
 	}
-	dartcounterGateway.startSharingDataViaSse()
+	dartcounterGateway.restoreEventID()
+	dartcounterGateway.restoreMatches()
+	dartcounterGateway.startForwardingDetections()
 	return dartcounterGateway
 }
 
@@ -37,33 +137,640 @@ func (g *dartcounterGateway) SSE() http.HandlerFunc {
 	}
 }
 
-// TODO
-// StartSharingDataViaSse starts sharing data from the binary-admin via the sse-server.
-func (g *dartcounterGateway) startSharingDataViaSse() {
+// GameModes returns all game modes that can be started.
+func (g *dartcounterGateway) GameModes() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
 
-	type Auto struct {
-		Marke   string `json:"marke"`
-		Baujahr int    `json:"baujahr"`
+		g.writeJSON(w, http.StatusOK, gameengine.Modes())
 	}
+}
 
-	go func() {
-		for {
-			// create car
-			auto := Auto{
-				Marke:   "Volkswagen",
-				Baujahr: 2023,
+// CreateMatch starts a new match (a single leg with the default options), which becomes the running match that counts the
+// detections. The other matches are kept and can still be played via their id.
+//
+// body: {"players": ["Anna", "Ben"], "options": {"mode": "x01", "gameOptions": {"startScore": 501}, "legs": 3}}
+func (g *dartcounterGateway) CreateMatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		req := matchRequest{Options: gameengine.DefaultMatchOptions()}
+		if err := decodeJSON(r, &req); err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, fmt.Errorf("invalid match request: %v", err))
+			return
+		}
+		matchLog, err := gameengine.NewMatchLog(req.Players, req.Options)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		id := g.addMatch(matchLog)
+		g.logger.Printf("match %d created (%s, legs: %d, sets: %d, players: %v)", id, req.Options.Mode, req.Options.Legs, req.Options.Sets, req.Players)
+		g.writeJSON(w, http.StatusCreated, matchResponse{ID: id, Running: true, State: matchLog.State()})
+	}
+}
+
+// Matches returns all started matches.
+func (g *dartcounterGateway) Matches() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		g.mu.Lock()
+		runningID := g.matchID
+		matchLogs := make(map[int]*gameengine.MatchLog, len(g.matches))
+		ids := make([]int, 0, len(g.matches))
+		for id, matchLog := range g.matches {
+			matchLogs[id] = matchLog
+			ids = append(ids, id)
+		}
+		g.mu.Unlock()
+		sort.Ints(ids)
+
+		summaries := make([]matchSummary, 0, len(ids))
+		for _, id := range ids {
+			state := matchLogs[id].State()
+			summary := matchSummary{
+				ID:        id,
+				Running:   id == runningID,
+				Mode:      state.Options.Mode,
+				Finished:  state.Finished,
+				Abandoned: state.Abandoned,
+				Winner:    state.Winner,
+			}
+			for _, p := range state.Players {
+				summary.Players = append(summary.Players, p.Name)
 			}
+			summaries = append(summaries, summary)
+		}
+		g.writeJSON(w, http.StatusOK, summaries)
+	}
+}
 
-			// convert struct to json
-			data, err := json.Marshal(auto)
-			if err != nil {
-				g.logger.PrintlnErr("JSON Marshal Error:", err)
-				continue
+// GetMatch returns the state of a match.
+func (g *dartcounterGateway) GetMatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		id, matchLog, running, err := g.matchOfRequest(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		g.writeJSON(w, http.StatusOK, matchResponse{ID: id, Running: running, State: matchLog.State()})
+	}
+}
+
+// SubmitThrows counts a single dart or the darts of a whole visit in a match. A visit with more darts than the current
+// player has left is rejected. Darts of a visit after its early end (e.g. bust or checkout) are not counted, they are
+// returned as "dropped".
+//
+// body: {"dart": {"segment": 20, "multiplier": 3}} or {"darts": [{"segment": 20, "multiplier": 3}, {"segment": 0, "multiplier": 0}]}
+func (g *dartcounterGateway) SubmitThrows() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		id, matchLog, running, err := g.matchOfRequest(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		var req throwRequest
+		if err := decodeJSON(r, &req); err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, fmt.Errorf("invalid throw request: %v", err))
+			return
+		}
+		if (req.Dart == nil) == (req.Darts == nil) {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, fmt.Errorf("invalid throw request: either dart or darts is required"))
+			return
+		}
+
+		var dropped []gameengine.Dart
+		if req.Dart != nil {
+			_, err = matchLog.Throw(*req.Dart)
+		} else {
+			var records []gameengine.Record
+			records, err = matchLog.ThrowVisit(req.Darts)
+			counted := 0
+			for _, record := range records {
+				if record.Entry.Action == gameengine.ActionThrow {
+					counted++
+				}
 			}
+			if (err == nil || errors.Is(err, gameengine.ErrNotPersisted)) && counted < len(req.Darts) {
+				dropped = req.Darts[counted:]
+				g.logger.Printf("%d darts after the early end of the visit dropped", len(dropped))
+			}
+		}
+		if errors.Is(err, gameengine.ErrGameOver) {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusConflict, err)
+			return
+		}
+		if err != nil && !errors.Is(err, gameengine.ErrNotPersisted) {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, err)
+			return
+		}
+		g.writeJSON(w, http.StatusOK, matchResponse{ID: id, Running: running, State: matchLog.State(), Dropped: dropped, Warning: g.persistWarning(err)})
+	}
+}
+
+// UndoMatchThrow reverts the last dart (or the early end of a visit) of a match.
+func (g *dartcounterGateway) UndoMatchThrow() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
 
-			// send event via sse
-			g.sseServer.SendEvent("1", "new-auto", data)
-			time.Sleep(5 * time.Second)
+		id, matchLog, running, err := g.matchOfRequest(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
 		}
+		record, err := matchLog.Undo()
+		if err != nil && !errors.Is(err, gameengine.ErrNotPersisted) {
+			// nothing to undo or the match was abandoned
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusConflict, err)
+			return
+		}
+		g.writeJSON(w, http.StatusOK, matchResponse{ID: id, Running: running, State: record.State, Warning: g.persistWarning(err)})
+	}
+}
+
+// CorrectMatchThrow replaces a dart of the running or the previous visit of a match. All following state is recomputed.
+// A dart after the bust or the checkout of a visit cannot be added (404).
+//
+// body: {"previous": true, "index": 2, "dart": {"segment": 20, "multiplier": 2}}
+func (g *dartcounterGateway) CorrectMatchThrow() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		id, matchLog, running, err := g.matchOfRequest(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		var req correctionRequest
+		if err := decodeJSON(r, &req); err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, fmt.Errorf("invalid correction request: %v", err))
+			return
+		}
+		record, err := matchLog.Correct(req.Previous, req.Index, req.Dart)
+		if errors.Is(err, gameengine.ErrNoSuchDart) {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		if errors.Is(err, gameengine.ErrGameOver) {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusConflict, err)
+			return
+		}
+		if err != nil && !errors.Is(err, gameengine.ErrNotPersisted) {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		visit := "running"
+		if req.Previous {
+			visit = "previous"
+		}
+		g.logger.Printf("dart %d of the %s visit of match %d corrected to %s", req.Index, visit, id, req.Dart)
+		g.writeJSON(w, http.StatusOK, matchResponse{ID: id, Running: running, State: record.State, Warning: g.persistWarning(err)})
+	}
+}
+
+// AllowedInputs returns the darts that count for the current player of the running leg of a match.
+func (g *dartcounterGateway) AllowedInputs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		_, matchLog, _, err := g.matchOfRequest(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		darts := matchLog.AllowedInputs()
+		if darts == nil {
+			darts = []gameengine.Dart{}
+		}
+		g.writeJSON(w, http.StatusOK, darts)
+	}
+}
+
+// MatchLog returns all logged actions of a match (e.g. for audits).
+func (g *dartcounterGateway) MatchLog() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		_, matchLog, _, err := g.matchOfRequest(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		g.writeJSON(w, http.StatusOK, matchLog.Entries())
+	}
+}
+
+// AbandonMatch ends a match without a winner.
+func (g *dartcounterGateway) AbandonMatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		id, matchLog, running, err := g.matchOfRequest(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		record, err := matchLog.Abandon()
+		if err != nil && !errors.Is(err, gameengine.ErrNotPersisted) {
+			// the match is already over
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusConflict, err)
+			return
+		}
+		g.logger.Printf("match %d abandoned", id)
+		g.writeJSON(w, http.StatusOK, matchResponse{ID: id, Running: running, State: record.State, Warning: g.persistWarning(err)})
+	}
+}
+
+// PendingThrows returns the detections of the running match that wait for the confirmation of a throw.
+func (g *dartcounterGateway) PendingThrows() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		id, _, running, err := g.matchOfRequest(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		pending := []pendingDetection{}
+		g.pendingMu.Lock()
+		if running && g.pendingMatchID == id {
+			for _, event := range g.pending {
+				pending = append(pending, newPendingDetection(event))
+			}
+		}
+		g.pendingMu.Unlock()
+		g.writeJSON(w, http.StatusOK, pending)
+	}
+}
+
+// ConfirmThrow counts the pending throw of the running match (optionally as a corrected dart) and the detections that
+// waited behind it.
+//
+// body (optional): {"dart": {"segment": 20, "multiplier": 1}}
+func (g *dartcounterGateway) ConfirmThrow() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		id, matchLog, running, err := g.matchOfRequest(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+		var req confirmRequest
+		if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, fmt.Errorf("invalid confirm request: %v", err))
+			return
+		}
+
+		g.pendingMu.Lock()
+		defer g.pendingMu.Unlock()
+		if !running || g.pendingMatchID != id || len(g.pending) == 0 {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, fmt.Errorf("no throw pending in match %d", id))
+			return
+		}
+		dart := gameengine.DartFromScore(g.pending[0].Throw.Score)
+		if req.Dart != nil {
+			dart = *req.Dart
+		}
+		_, err = matchLog.Throw(dart)
+		if errors.Is(err, gameengine.ErrGameOver) {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusConflict, err)
+			return
+		} else if err != nil && !errors.Is(err, gameengine.ErrNotPersisted) {
+			// --> the throw stays pending (e.g. an invalid corrected dart)
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusBadRequest, err)
+			return
+		}
+		g.logger.Printf("pending throw confirmed as %s", dart)
+		g.pending = g.pending[1:]
+		g.countPending(id, matchLog)
+		g.writeJSON(w, http.StatusOK, matchResponse{ID: id, Running: running, State: matchLog.State(), Warning: g.persistWarning(err)})
+	}
+}
+
+// RejectThrow drops the pending throw of the running match without counting it and counts the detections that waited
+// behind it.
+func (g *dartcounterGateway) RejectThrow() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.logger.LogHttpRequest(r)
+
+		id, matchLog, running, err := g.matchOfRequest(r)
+		if err != nil {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, err)
+			return
+		}
+
+		g.pendingMu.Lock()
+		defer g.pendingMu.Unlock()
+		if !running || g.pendingMatchID != id || len(g.pending) == 0 {
+			g.logger.LogAndWriteHttpRequestError(w, http.StatusNotFound, fmt.Errorf("no throw pending in match %d", id))
+			return
+		}
+		g.logger.Printf("pending throw %s rejected", g.pending[0].Throw.Score.Label)
+		g.sendEvent(dartcounterevents.ThrowRejected, pendingThrowEvent(dartcounterevents.ThrowRejected, id, g.pending[0]))
+		g.pending = g.pending[1:]
+		g.countPending(id, matchLog)
+		g.writeJSON(w, http.StatusOK, matchResponse{ID: id, Running: running, State: matchLog.State()})
+	}
+}
+
+// persistWarning returns the warning of the response to an action that counts, but could not be persisted. The request
+// does not fail: repeating the action would count it twice. Empty if the action was persisted.
+func (g *dartcounterGateway) persistWarning(err error) string {
+	if !errors.Is(err, gameengine.ErrNotPersisted) {
+		return ""
+	}
+	g.logger.PrintlnErr(err)
+	return err.Error()
+}
+
+// matchOfRequest returns the id and the log of the match of the request path and whether it is the running match.
+func (g *dartcounterGateway) matchOfRequest(r *http.Request) (int, *gameengine.MatchLog, bool, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["matchID"])
+	if err != nil {
+		return 0, nil, false, fmt.Errorf("invalid match-id: %v", err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	matchLog, ok := g.matches[id]
+	if !ok {
+		return 0, nil, false, fmt.Errorf("unknown match-id: %d", id)
+	}
+	return id, matchLog, id == g.matchID, nil
+}
+
+// startForwardingDetections forwards the throws and takeouts of the detection pipeline via the sse-server
+// and counts them in the running match. A takeout ends the visit of the current player.
+func (g *dartcounterGateway) startForwardingDetections() {
+	if g.detectionPipeline == nil {
+		return
+	}
+	eventCh := g.detectionPipeline.Subscribe()
+
+	go func() {
+		for event := range eventCh {
+			g.mu.Lock()
+			matchID := g.matchID
+			g.mu.Unlock()
+			g.sendEvent(detectionEvent(matchID, event))
+			g.countDetection(event)
+		}
+		g.logger.Println("detection events closed")
 	}()
 }
+
+// countDetection logs a detection event in the running match. Bounce-outs count as missed darts. A throw (or bounce-out)
+// that needs a confirmation is held as pending (and shared via the sse-server) until it is confirmed or rejected; the
+// detections after it wait behind it, so the darts are counted in the order they were thrown.
+func (g *dartcounterGateway) countDetection(event detectionpipeline.Event) {
+	g.mu.Lock()
+	matchID := g.matchID
+	matchLog, ok := g.matches[matchID]
+	g.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	g.pendingMu.Lock()
+	defer g.pendingMu.Unlock()
+	if g.pendingMatchID != matchID {
+		// --> the pending detections belong to a replaced match
+		g.pending, g.pendingMatchID = nil, matchID
+	}
+	g.pending = append(g.pending, event)
+	if len(g.pending) == 1 {
+		g.countPending(matchID, matchLog)
+	}
+}
+
+// countPending logs the pending detections until a throw needs a confirmation. The caller holds the pending lock.
+func (g *dartcounterGateway) countPending(matchID int, matchLog *gameengine.MatchLog) {
+	for len(g.pending) > 0 {
+		event := g.pending[0]
+		if needsConfirmation(event) {
+			g.logger.Printf("throw %s waits for a confirmation: %s", event.Throw.Score.Label, event.Throw.Reason)
+			g.sendEvent(dartcounterevents.ThrowPending, pendingThrowEvent(dartcounterevents.ThrowPending, matchID, event))
+			return
+		}
+		g.pending = g.pending[1:]
+
+		var err error
+		switch event.Type {
+		case detectionpipeline.Throw:
+			if event.Throw == nil {
+				continue
+			}
+			_, err = matchLog.Throw(gameengine.DartFromScore(event.Throw.Score))
+		case detectionpipeline.BounceOut:
+			_, err = matchLog.Throw(gameengine.Miss)
+		case detectionpipeline.Takeout:
+			_, err = matchLog.EndVisit()
+		}
+		if err != nil && !errors.Is(err, gameengine.ErrGameOver) {
+			g.logger.PrintlnErr(err)
+		}
+	}
+}
+
+// needsConfirmation reports whether the detection is a throw (or a bounce-out not seen by every calibrated camera) that
+// is only counted after a confirmation.
+func needsConfirmation(event detectionpipeline.Event) bool {
+	if event.Type != detectionpipeline.Throw && event.Type != detectionpipeline.BounceOut {
+		return false
+	}
+	return event.Throw != nil && event.Throw.NeedsConfirmation
+}
+
+// newPendingDetection returns the detection as it is shared while it waits to be counted.
+func newPendingDetection(event detectionpipeline.Event) pendingDetection {
+	detection := pendingDetection{Type: event.Type, NeedsConfirmation: needsConfirmation(event)}
+	if event.Throw != nil {
+		dart := gameengine.DartFromScore(event.Throw.Score)
+		detection.Dart, detection.Reason, detection.Confidence = &dart, event.Throw.Reason, event.Throw.Confidence
+	}
+	return detection
+}
+
+// addMatch adds a match, makes it the running match and shares its state via the sse-server.
+func (g *dartcounterGateway) addMatch(matchLog *gameengine.MatchLog) int {
+	// --> subscribe before the match is shared, so no record of it is lost
+	recordCh := matchLog.Subscribe()
+	g.mu.Lock()
+	g.lastMatchID++
+	id := g.lastMatchID
+	g.mu.Unlock()
+	g.persistMatch(id, matchLog)
+
+	state := matchLog.State()
+	g.sendEvent(dartcounterevents.MatchCreated, matchCreatedEvent(id, state))
+	g.sendState(id, state)
+
+	g.mu.Lock()
+	g.matches[id] = matchLog
+	g.matchID = id
+	g.mu.Unlock()
+	go g.forwardRecords(id, recordCh)
+	return id
+}
+
+// persistMatch stores the log of a new match and every later change (if a match store is available).
+func (g *dartcounterGateway) persistMatch(id int, matchLog *gameengine.MatchLog) {
+	if g.matchStore == nil {
+		return
+	}
+	if err := g.matchStore.Save(id, matchLog.Entries()); err != nil {
+		// --> the next action persists the whole log again
+		g.logger.PrintlnErr(err)
+	}
+	g.persistChanges(id, matchLog)
+}
+
+// persistChanges stores the log of the match after every later change (if a match store is available).
+func (g *dartcounterGateway) persistChanges(id int, matchLog *gameengine.MatchLog) {
+	if g.matchStore == nil {
+		return
+	}
+	matchLog.SetPersist(func(entries []gameengine.LogEntry) error {
+		return g.matchStore.Save(id, entries)
+	})
+}
+
+// restoreEventID continues the sse-event ids after the last id that may have been sent before the restart, so clients that
+// reconnect with the id of their last event (Last-Event-ID) never see an id twice.
+func (g *dartcounterGateway) restoreEventID() {
+	if g.matchStore == nil {
+		return
+	}
+	id, err := g.matchStore.LoadLastEventID()
+	if err != nil {
+		g.logger.PrintlnErr(err)
+		return
+	}
+	g.eventID, g.reservedEventID = id, id
+}
+
+// restoreMatches replays the logs of the unfinished persisted matches. The last one becomes the running match again, so
+// it continues exactly where it stopped. Logs of finished matches are archived, so they are not replayed on every boot;
+// logs that cannot be replayed are moved aside.
+func (g *dartcounterGateway) restoreMatches() {
+	if g.matchStore == nil {
+		return
+	}
+	// --> the ids of matches that are archived or could not be restored are not reused
+	lastMatchID, err := g.matchStore.LastMatchID()
+	if err != nil {
+		g.logger.PrintlnErr(err)
+	}
+	stored, err := g.matchStore.Load()
+	if err != nil {
+		// --> files that cannot be read are skipped, the other matches are restored
+		g.logger.PrintlnErr(err)
+	}
+	ids := make([]int, 0, len(stored))
+	for id := range stored {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	running := 0
+	for _, id := range ids {
+		lastMatchID = max(lastMatchID, id)
+		matchLog, err := gameengine.ReplayMatchLog(stored[id])
+		if err != nil {
+			g.logger.PrintfErr("match %d could not be restored: %v", id, err)
+			if err := g.matchStore.MoveAside(id); err != nil {
+				g.logger.PrintlnErr(err)
+			}
+			continue
+		}
+		if state := matchLog.State(); state.Finished || state.Abandoned {
+			if err := g.matchStore.Archive(id); err != nil {
+				g.logger.PrintlnErr(err)
+			}
+			continue
+		}
+		running = id
+		recordCh := matchLog.Subscribe()
+		g.mu.Lock()
+		g.matches[id] = matchLog
+		g.mu.Unlock()
+		g.persistChanges(id, matchLog)
+		go g.forwardRecords(id, recordCh)
+	}
+	g.lastMatchID = lastMatchID
+
+	if running > 0 {
+		g.mu.Lock()
+		g.matchID = running
+		g.mu.Unlock()
+		g.logger.Printf("%d matches restored, match %d continues", len(g.matches), running)
+	}
+}
+
+// forwardRecords shares the game events of every logged action of a match followed by the resulting state via the
+// sse-server. The records of all matches are shared, not only the ones of the running match.
+func (g *dartcounterGateway) forwardRecords(matchID int, recordCh <-chan gameengine.Record) {
+	for record := range recordCh {
+		for _, e := range record.Events {
+			g.sendEvent(gameEvent(matchID, e, record.State))
+		}
+		g.sendState(matchID, record.State)
+	}
+}
+
+// sendState shares the state of the match via the sse-server.
+func (g *dartcounterGateway) sendState(matchID int, state gameengine.MatchState) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		g.logger.PrintlnErr("JSON Marshal Error:", err)
+		return
+	}
+	g.sendEvent(dartcounterevents.MatchState, dartcounterevents.MatchStateEvent{Header: dartcounterevents.NewHeader(dartcounterevents.MatchState, matchID), State: data})
+}
+
+// sendEvent shares the payload as json via the sse-server. The event ids increase with every event.
+func (g *dartcounterGateway) sendEvent(eventType dartcounterevents.Type, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		g.logger.PrintlnErr("JSON Marshal Error:", err)
+		return
+	}
+	g.sendMu.Lock()
+	defer g.sendMu.Unlock()
+	g.eventID++
+	if g.matchStore != nil && g.eventID > g.reservedEventID {
+		// --> reserve the next block of ids before an id of it is sent
+		if err := g.matchStore.SaveLastEventID(g.eventID + eventIDBlock - 1); err != nil {
+			g.logger.PrintlnErr(err)
+		} else {
+			g.reservedEventID = g.eventID + eventIDBlock - 1
+		}
+	}
+	g.sseServer.SendEvent(strconv.FormatUint(g.eventID, 10), string(eventType), data)
+}
+
+// decodeJSON decodes the json body of the request. Unknown fields are rejected.
+func decodeJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// writeJSON writes the hand-overed value as json response.
+func (g *dartcounterGateway) writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		g.logger.LogAndWriteHttpRequestError(w, http.StatusInternalServerError, fmt.Errorf("JSON Marshal Error: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
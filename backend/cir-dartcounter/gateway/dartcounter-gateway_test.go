@@ -0,0 +1,592 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gameengine "github.com/One-Hundred-Eighty/Circle/backend/cir-dartcounter/game-engine"
+	dartfusion "github.com/One-Hundred-Eighty/Circle/pkg/dart-fusion"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+	dartcounterevents "github.com/One-Hundred-Eighty/Circle/pkg/dartcounter-events"
+	dartmasterlogger "github.com/One-Hundred-Eighty/Circle/pkg/dartmaster-logger"
+	detectionpipeline "github.com/One-Hundred-Eighty/Circle/pkg/detection-pipeline"
+	"github.com/gorilla/mux"
+)
+
+func newTestGateway(t *testing.T) *dartcounterGateway {
+	t.Helper()
+	return NewDartcounterGateway(dartmasterlogger.NewDartmasterLogger("[dartcounter-test] "), nil, nil)
+}
+
+func newTestMatch(t *testing.T, g *dartcounterGateway) (int, *gameengine.MatchLog) {
+	t.Helper()
+	matchLog, err := gameengine.NewMatchLog([]string{"Anna", "Ben"}, gameengine.DefaultMatchOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g.addMatch(matchLog), matchLog
+}
+
+// throwEvent returns the detection of a dart in the center of the area of the label (e.g. "T20").
+func throwEvent(t *testing.T, label string, needsConfirmation bool) detectionpipeline.Event {
+	t.Helper()
+	board := dartboard.Standard()
+	x, y, err := board.Target(label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &dartfusion.Result{Score: board.Score(x, y), Confidence: 0.9, NeedsConfirmation: needsConfirmation}
+	if needsConfirmation {
+		result.Confidence, result.Reason = 0.3, "cameras disagree"
+	}
+	return detectionpipeline.Event{Type: detectionpipeline.Throw, Throw: result}
+}
+
+// call calls a handler of a match and returns the status code and the body of the response.
+func call(handler http.HandlerFunc, matchID int, body string) (int, string) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r = mux.SetURLVars(r, map[string]string{"matchID": strconv.Itoa(matchID)})
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w.Code, w.Body.String()
+}
+
+func x01Scores(t *testing.T, state gameengine.MatchState) []int {
+	t.Helper()
+	details, ok := state.Game.Details.(gameengine.X01State)
+	if !ok {
+		t.Fatalf("unexpected game details %T", state.Game.Details)
+	}
+	var scores []int
+	for _, p := range details.Players {
+		scores = append(scores, p.Score)
+	}
+	return scores
+}
+
+func TestPendingThrowIsCountedAfterConfirmation(t *testing.T) {
+	g := newTestGateway(t)
+	id, matchLog := newTestMatch(t, g)
+
+	g.countDetection(throwEvent(t, "T20", false))
+	g.countDetection(throwEvent(t, "S5", true))
+	g.countDetection(throwEvent(t, "T1", false))
+	g.countDetection(detectionpipeline.Event{Type: detectionpipeline.Takeout})
+
+	// only the first dart is counted, the others wait behind the flagged throw
+	if darts := matchLog.State().Game.Visit; len(darts) != 1 {
+		t.Fatalf("%d darts counted before the confirmation, want 1", len(darts))
+	}
+	code, body := call(g.PendingThrows(), id, "")
+	var pending []pendingDetection
+	if err := json.Unmarshal([]byte(body), &pending); code != http.StatusOK || err != nil {
+		t.Fatalf("pending: %d %s", code, body)
+	}
+	if len(pending) != 3 || !pending[0].NeedsConfirmation || pending[0].Dart.String() != "S5" || pending[0].Reason == "" {
+		t.Fatalf("unexpected pending detections %+v", pending)
+	}
+
+	// the operator corrects the flagged dart to S1 --> the waiting dart and the takeout are counted as well
+	if code, body := call(g.ConfirmThrow(), id, `{"dart": {"segment": 1, "multiplier": 1}}`); code != http.StatusOK {
+		t.Fatalf("confirm: %d %s", code, body)
+	}
+	state := matchLog.State()
+	if scores := x01Scores(t, state); scores[0] != 501-60-1-3 {
+		t.Errorf("score %d, want %d", scores[0], 501-60-1-3)
+	}
+	if state.Game.CurrentPlayer != 1 || state.Previous == nil || len(state.Previous.Darts) != 3 {
+		t.Errorf("the takeout did not end the visit: %+v", state.Game)
+	}
+	if code, _ := call(g.ConfirmThrow(), id, ""); code != http.StatusNotFound {
+		t.Errorf("confirm without pending throw: %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestPendingThrowConfirmedAsDetected(t *testing.T) {
+	g := newTestGateway(t)
+	id, matchLog := newTestMatch(t, g)
+
+	g.countDetection(throwEvent(t, "D16", true))
+	if code, body := call(g.ConfirmThrow(), id, ""); code != http.StatusOK {
+		t.Fatalf("confirm: %d %s", code, body)
+	}
+	if darts := matchLog.State().Game.Visit; len(darts) != 1 || darts[0].String() != "D16" {
+		t.Errorf("counted darts %v, want [D16]", darts)
+	}
+}
+
+func TestPendingThrowRejected(t *testing.T) {
+	g := newTestGateway(t)
+	id, matchLog := newTestMatch(t, g)
+
+	g.countDetection(throwEvent(t, "T20", true))
+	g.countDetection(throwEvent(t, "S19", false))
+	if code, body := call(g.RejectThrow(), id, ""); code != http.StatusOK {
+		t.Fatalf("reject: %d %s", code, body)
+	}
+	if darts := matchLog.State().Game.Visit; len(darts) != 1 || darts[0].String() != "S19" {
+		t.Errorf("counted darts %v, want [S19]", darts)
+	}
+	if code, _ := call(g.RejectThrow(), id, ""); code != http.StatusNotFound {
+		t.Errorf("reject without pending throw: %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestPendingBounceOut(t *testing.T) {
+	g := newTestGateway(t)
+	id, matchLog := newTestMatch(t, g)
+
+	seen := detectionpipeline.Event{Type: detectionpipeline.BounceOut, Throw: &dartfusion.Result{Score: dartboard.Miss, Confidence: 1}}
+	unseen := detectionpipeline.Event{Type: detectionpipeline.BounceOut, Throw: &dartfusion.Result{
+		Score: dartboard.Miss, Confidence: 0.5, NeedsConfirmation: true, Reason: "bounce-out seen by 1 of 2 calibrated cameras",
+	}}
+	g.countDetection(seen)
+	g.countDetection(unseen)
+	g.countDetection(throwEvent(t, "S20", false))
+
+	// --> the bounce-out seen by every camera is counted, the other one waits for a confirmation
+	if darts := matchLog.State().Game.Visit; len(darts) != 1 || darts[0] != gameengine.Miss {
+		t.Fatalf("counted darts %v before the confirmation, want [MISS]", darts)
+	}
+	code, body := call(g.PendingThrows(), id, "")
+	var pending []pendingDetection
+	if err := json.Unmarshal([]byte(body), &pending); code != http.StatusOK || err != nil {
+		t.Fatalf("pending: %d %s", code, body)
+	}
+	if len(pending) != 2 || pending[0].Type != detectionpipeline.BounceOut || !pending[0].NeedsConfirmation || pending[0].Reason == "" {
+		t.Fatalf("unexpected pending detections %+v", pending)
+	}
+
+	if code, body := call(g.ConfirmThrow(), id, ""); code != http.StatusOK {
+		t.Fatalf("confirm: %d %s", code, body)
+	}
+	state := matchLog.State()
+	if state.Previous == nil || len(state.Previous.Darts) != 3 || state.Previous.Darts[1] != gameengine.Miss || state.Previous.Darts[2].String() != "S20" {
+		t.Fatalf("counted visit %+v, want [MISS MISS S20]", state.Previous)
+	}
+
+	// a rejected bounce-out (e.g. a hand passing the board) is not counted
+	g.countDetection(unseen)
+	if code, body := call(g.RejectThrow(), id, ""); code != http.StatusOK {
+		t.Fatalf("reject: %d %s", code, body)
+	}
+	if darts := matchLog.State().Game.Visit; len(darts) != 0 {
+		t.Errorf("counted darts %v after the rejection, want none", darts)
+	}
+}
+
+func TestPendingThrowsOfReplacedMatch(t *testing.T) {
+	g := newTestGateway(t)
+	first, _ := newTestMatch(t, g)
+	g.countDetection(throwEvent(t, "T20", true))
+	second, matchLog := newTestMatch(t, g)
+
+	if code, _ := call(g.ConfirmThrow(), first, ""); code != http.StatusNotFound {
+		t.Errorf("confirm in the replaced match: %d, want %d", code, http.StatusNotFound)
+	}
+	g.countDetection(throwEvent(t, "S20", false))
+	if darts := matchLog.State().Game.Visit; len(darts) != 1 || darts[0].String() != "S20" {
+		t.Errorf("counted darts %v in match %d, want [S20]", darts, second)
+	}
+}
+
+func TestConfirmInvalidDartKeepsThrowPending(t *testing.T) {
+	g := newTestGateway(t)
+	id, matchLog := newTestMatch(t, g)
+
+	g.countDetection(throwEvent(t, "T20", true))
+	if code, _ := call(g.ConfirmThrow(), id, `{"dart": {"segment": 21, "multiplier": 1}}`); code != http.StatusBadRequest {
+		t.Errorf("confirm an invalid dart: %d, want %d", code, http.StatusBadRequest)
+	}
+	if code, _ := call(g.ConfirmThrow(), id, `{"foo": 1}`); code != http.StatusBadRequest {
+		t.Errorf("confirm with unknown field: %d, want %d", code, http.StatusBadRequest)
+	}
+	if code, body := call(g.ConfirmThrow(), id, ""); code != http.StatusOK {
+		t.Fatalf("confirm: %d %s", code, body)
+	}
+	if darts := matchLog.State().Game.Visit; len(darts) != 1 || darts[0].String() != "T20" {
+		t.Errorf("counted darts %v, want [T20]", darts)
+	}
+}
+
+func TestSubmitVisitReportsDroppedDarts(t *testing.T) {
+	g := newTestGateway(t)
+	options := gameengine.MatchOptions{Mode: gameengine.ModeX01, GameOptions: json.RawMessage(`{"startScore":40}`), Legs: 1}
+	matchLog, err := gameengine.NewMatchLog([]string{"Anna", "Ben"}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := g.addMatch(matchLog)
+
+	// 40 - 20 = 20 --> the T20 busts, the S1 is dropped
+	code, body := call(g.SubmitThrows(), id, `{"darts":[{"segment":20,"multiplier":1},{"segment":20,"multiplier":3},{"segment":1,"multiplier":1}]}`)
+	var res matchResponse
+	if err := json.Unmarshal([]byte(body), &res); code != http.StatusOK || err != nil {
+		t.Fatalf("bust visit: %d %s", code, body)
+	}
+	if len(res.Dropped) != 1 || res.Dropped[0].String() != "S1" {
+		t.Errorf("dropped darts %v, want [S1]", res.Dropped)
+	}
+	state := matchLog.State()
+	if scores := x01Scores(t, state); scores[0] != 40 || state.Game.CurrentPlayer != 1 {
+		t.Errorf("scores %v, current player %d after the bust", scores, state.Game.CurrentPlayer)
+	}
+
+	// --> Ben has thrown a dart, three more darts don't fit into the visit
+	if code, body := call(g.SubmitThrows(), id, `{"dart":{"segment":1,"multiplier":1}}`); code != http.StatusOK {
+		t.Fatalf("throw: %d %s", code, body)
+	}
+	if code, _ := call(g.SubmitThrows(), id, `{"darts":[{"segment":1,"multiplier":1},{"segment":1,"multiplier":1},{"segment":1,"multiplier":1}]}`); code != http.StatusBadRequest {
+		t.Errorf("visit with more darts than left: %d, want %d", code, http.StatusBadRequest)
+	}
+	if darts := matchLog.State().Game.Visit; len(darts) != 1 {
+		t.Errorf("counted darts %v after the rejected visit, want [S1]", darts)
+	}
+}
+
+// sseClient connects to the sse-stream of the gateway and returns the headers of the received events. It returns once the
+// client receives the events of the gateway.
+func sseClient(t *testing.T, g *dartcounterGateway) <-chan dartcounterevents.Header {
+	t.Helper()
+	server := httptest.NewServer(g.SSE())
+	t.Cleanup(server.Close)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventCh := make(chan dartcounterevents.Header, 100)
+	go func() {
+		// --> the response header is written with the first event
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return
+		}
+		defer res.Body.Close()
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				var header dartcounterevents.Header
+				if json.Unmarshal([]byte(data), &header) == nil {
+					eventCh <- header
+				}
+			}
+		}
+	}()
+
+	// --> the client is subscribed once it receives an event
+	timeout := time.After(5 * time.Second)
+	for {
+		g.sendEvent(dartcounterevents.PlayerChanged, dartcounterevents.PlayerEvent{Header: dartcounterevents.NewHeader(dartcounterevents.PlayerChanged, -1)})
+		select {
+		case <-eventCh:
+			for len(eventCh) > 0 {
+				<-eventCh
+			}
+			return eventCh
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("sse client not connected")
+		}
+	}
+}
+
+// waitForEvent waits for an event of a match, the events before it are skipped.
+func waitForEvent(t *testing.T, eventCh <-chan dartcounterevents.Header, eventType dartcounterevents.Type, matchID int) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case header := <-eventCh:
+			if header.Type == eventType && header.MatchID == matchID {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no %s event of match %d", eventType, matchID)
+		}
+	}
+}
+
+func TestCreateMatchValidatesTheRequest(t *testing.T) {
+	g := newTestGateway(t)
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"players":["Anna","Ben"]}`, http.StatusCreated},
+		{`{"players":["Anna"],"options":{"mode":"cricket","legs":2}}`, http.StatusCreated},
+		{`{"players":["Anna"],"options":{"mode":"x01","gameOptions":{"startScore":301,"in":"double"}}}`, http.StatusCreated},
+		{`{"players":["Anna"],"mode":"x01"}`, http.StatusBadRequest},
+		{`{"players":["Anna"],"options":{"mode":"x01","gameOptions":{"foo":1}}}`, http.StatusBadRequest},
+		{`{"players":[]}`, http.StatusBadRequest},
+		{`{"players":["Anna"],"options":{"mode":"foo"}}`, http.StatusBadRequest},
+		{`{"players":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if code, body := call(g.CreateMatch(), 0, tt.body); code != tt.status {
+			t.Errorf("%s: status %d (%s), want %d", tt.body, code, body, tt.status)
+		}
+	}
+}
+
+// the actions of every match are shared, not only the ones of the running match
+func TestEventsOfEveryMatch(t *testing.T) {
+	g := newTestGateway(t)
+	eventCh := sseClient(t, g)
+
+	first, firstLog := newTestMatch(t, g)
+	waitForEvent(t, eventCh, dartcounterevents.MatchCreated, first)
+	second, _ := newTestMatch(t, g)
+	waitForEvent(t, eventCh, dartcounterevents.MatchCreated, second)
+
+	// --> the first match is not the running match anymore
+	if _, err := firstLog.Throw(gameengine.Dart{Segment: 20, Multiplier: 3}); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvent(t, eventCh, dartcounterevents.ThrowRegistered, first)
+	waitForEvent(t, eventCh, dartcounterevents.MatchState, first)
+
+	if code, body := call(g.SubmitThrows(), second, `{"dart":{"segment":19,"multiplier":3}}`); code != http.StatusOK {
+		t.Fatalf("throw: %d %s", code, body)
+	}
+	waitForEvent(t, eventCh, dartcounterevents.ThrowRegistered, second)
+}
+
+// a record appended right after the match was added must not be lost
+func TestEventsOfANewMatch(t *testing.T) {
+	g := newTestGateway(t)
+	eventCh := sseClient(t, g)
+
+	id, matchLog := newTestMatch(t, g)
+	if _, err := matchLog.Throw(gameengine.Dart{Segment: 20, Multiplier: 3}); err != nil {
+		t.Fatal(err)
+	}
+	waitForEvent(t, eventCh, dartcounterevents.MatchCreated, id)
+	waitForEvent(t, eventCh, dartcounterevents.ThrowRegistered, id)
+}
+
+// memoryStore is a match store in memory.
+type memoryStore struct {
+	mu          sync.Mutex
+	matches     map[int][]gameengine.LogEntry
+	archived    map[int][]gameengine.LogEntry
+	movedAside  map[int][]gameengine.LogEntry
+	lastEventID uint64
+	saves       int   // number of saved match logs
+	saveErr     error // returned by Save instead of saving
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		matches:    make(map[int][]gameengine.LogEntry),
+		archived:   make(map[int][]gameengine.LogEntry),
+		movedAside: make(map[int][]gameengine.LogEntry),
+	}
+}
+
+func (s *memoryStore) Save(matchID int, entries []gameengine.LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.saves++
+	s.matches[matchID] = entries
+	return nil
+}
+
+func (s *memoryStore) Load() (map[int][]gameengine.LogEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	matches := make(map[int][]gameengine.LogEntry, len(s.matches))
+	for id, entries := range s.matches {
+		matches[id] = entries
+	}
+	return matches, nil
+}
+
+func (s *memoryStore) LastMatchID() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lastID := 0
+	for _, matches := range []map[int][]gameengine.LogEntry{s.matches, s.archived, s.movedAside} {
+		for id := range matches {
+			lastID = max(lastID, id)
+		}
+	}
+	return lastID, nil
+}
+
+func (s *memoryStore) MoveAside(matchID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.movedAside[matchID] = s.matches[matchID]
+	delete(s.matches, matchID)
+	return nil
+}
+
+func (s *memoryStore) Archive(matchID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.archived[matchID] = s.matches[matchID]
+	delete(s.matches, matchID)
+	return nil
+}
+
+func (s *memoryStore) SaveLastEventID(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastEventID = id
+	return nil
+}
+
+func (s *memoryStore) LoadLastEventID() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastEventID, nil
+}
+
+// a restart restores the matches without rewriting them, only later changes are saved
+func TestRestoreDoesNotRewriteMatches(t *testing.T) {
+	store := newMemoryStore()
+	logger := dartmasterlogger.NewDartmasterLogger("[dartcounter-test] ")
+	g := NewDartcounterGateway(logger, nil, store)
+	id, _ := newTestMatch(t, g)
+	if code, body := call(g.SubmitThrows(), id, `{"dart":{"segment":20,"multiplier":3}}`); code != http.StatusOK {
+		t.Fatalf("throw: %d %s", code, body)
+	}
+
+	store.saves = 0
+	restarted := NewDartcounterGateway(logger, nil, store)
+	if store.saves != 0 {
+		t.Errorf("%d match logs saved by the restart, want 0", store.saves)
+	}
+	if code, body := call(restarted.SubmitThrows(), id, `{"dart":{"segment":20,"multiplier":1}}`); code != http.StatusOK {
+		t.Fatalf("throw after the restart: %d %s", code, body)
+	}
+	if store.saves != 1 || len(store.matches[id]) != 3 {
+		t.Errorf("%d saves with %d entries after a throw, want 1 save with 3 entries", store.saves, len(store.matches[id]))
+	}
+}
+
+// a restart restores the unfinished matches only: finished matches are archived, logs that cannot be replayed are moved
+// aside, and the ids of both are not reused
+func TestRestoreUnfinishedMatches(t *testing.T) {
+	store := newMemoryStore()
+	logger := dartmasterlogger.NewDartmasterLogger("[dartcounter-test] ")
+	g := NewDartcounterGateway(logger, nil, store)
+	finished, _ := newTestMatch(t, g)
+	if code, body := call(g.AbandonMatch(), finished, ""); code != http.StatusOK {
+		t.Fatalf("abandon: %d %s", code, body)
+	}
+	unfinished, _ := newTestMatch(t, g)
+	store.matches[3] = []gameengine.LogEntry{{Action: gameengine.ActionThrow}}
+	store.movedAside[7] = nil
+
+	restarted := NewDartcounterGateway(logger, nil, store)
+	if _, ok := restarted.matches[unfinished]; !ok || len(restarted.matches) != 1 || restarted.matchID != unfinished {
+		t.Errorf("restored matches %v (running %d), want match %d", reflect.ValueOf(restarted.matches).MapKeys(), restarted.matchID, unfinished)
+	}
+	if _, ok := store.archived[finished]; !ok {
+		t.Errorf("finished match %d not archived", finished)
+	}
+	if _, ok := store.movedAside[3]; !ok {
+		t.Error("match 3 that cannot be replayed not moved aside")
+	}
+	if id, _ := newTestMatch(t, restarted); id != 8 {
+		t.Errorf("new match %d after the restart, want 8", id)
+	}
+
+	// --> the archived match is not replayed on the next boot
+	restarted = NewDartcounterGateway(logger, nil, store)
+	if _, ok := restarted.matches[finished]; ok {
+		t.Errorf("archived match %d restored", finished)
+	}
+}
+
+// a throw that cannot be persisted is counted, the response warns about it
+func TestThrowNotPersisted(t *testing.T) {
+	store := newMemoryStore()
+	g := NewDartcounterGateway(dartmasterlogger.NewDartmasterLogger("[dartcounter-test] "), nil, store)
+	id, matchLog := newTestMatch(t, g)
+
+	store.saveErr = errors.New("disk full")
+	code, body := call(g.SubmitThrows(), id, `{"dart":{"segment":20,"multiplier":3}}`)
+	var res matchResponse
+	if err := json.Unmarshal([]byte(body), &res); code != http.StatusOK || err != nil || res.Warning == "" {
+		t.Errorf("throw: %d %s, want %d with a warning", code, body, http.StatusOK)
+	}
+	if scores := x01Scores(t, matchLog.State()); scores[0] != 441 {
+		t.Errorf("scores %v after the not persisted throw, want 441 for Anna", scores)
+	}
+
+	// --> the next persisted action persists the throw as well, the response has no warning
+	store.saveErr = nil
+	code, body = call(g.SubmitThrows(), id, `{"dart":{"segment":20,"multiplier":1}}`)
+	res = matchResponse{}
+	if err := json.Unmarshal([]byte(body), &res); code != http.StatusOK || err != nil || res.Warning != "" {
+		t.Errorf("throw: %d %s, want %d without a warning", code, body, http.StatusOK)
+	}
+	if len(store.matches[id]) != 3 {
+		t.Errorf("%d persisted entries, want 3", len(store.matches[id]))
+	}
+}
+
+// the event ids keep increasing after a restart, so a client that reconnects with its last event id never sees an id twice
+func TestEventIDsAfterRestart(t *testing.T) {
+	store := newMemoryStore()
+	logger := dartmasterlogger.NewDartmasterLogger("[dartcounter-test] ")
+
+	g := NewDartcounterGateway(logger, nil, store)
+	for i := 0; i < eventIDBlock+10; i++ {
+		g.sendEvent(dartcounterevents.PlayerChanged, dartcounterevents.PlayerEvent{})
+	}
+	last := g.eventID
+	if store.lastEventID < last {
+		t.Fatalf("persisted event id %d is lower than the sent id %d", store.lastEventID, last)
+	}
+
+	restarted := NewDartcounterGateway(logger, nil, store)
+	restarted.sendEvent(dartcounterevents.PlayerChanged, dartcounterevents.PlayerEvent{})
+	if restarted.eventID <= last {
+		t.Errorf("event id %d after the restart, want more than %d", restarted.eventID, last)
+	}
+}
+
+func TestDetectionEvent(t *testing.T) {
+	tests := []struct {
+		event     detectionpipeline.Event
+		eventType dartcounterevents.Type
+		dart      string
+	}{
+		{throwEvent(t, "T20", false), dartcounterevents.ThrowDetected, "T20"},
+		{throwEvent(t, "D5", true), dartcounterevents.ThrowDetected, "D5"},
+		{detectionpipeline.Event{Type: detectionpipeline.Takeout}, dartcounterevents.TakeoutDetected, ""},
+		{detectionpipeline.Event{Type: detectionpipeline.BounceOut}, dartcounterevents.BounceOutDetected, ""},
+	}
+	for _, tt := range tests {
+		eventType, payload := detectionEvent(1, tt.event)
+		if eventType != tt.eventType || payload.Type != tt.eventType || payload.MatchID != 1 {
+			t.Errorf("%s: event type %s (header %s, match %d)", tt.event.Type, eventType, payload.Type, payload.MatchID)
+		}
+		if (payload.Throw == nil) != (tt.dart == "") {
+			t.Errorf("%s: throw %+v", tt.event.Type, payload.Throw)
+			continue
+		}
+		if payload.Throw != nil && (payload.Throw.Dart.Label != tt.dart || payload.Throw.NeedsConfirmation != tt.event.Throw.NeedsConfirmation) {
+			t.Errorf("%s: throw %+v, want %s", tt.event.Type, payload.Throw, tt.dart)
+		}
+	}
+}
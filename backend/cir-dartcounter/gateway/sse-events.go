@@ -0,0 +1,117 @@
+package gateway
+
+import (
+	gameengine "github.com/One-Hundred-Eighty/Circle/backend/cir-dartcounter/game-engine"
+	dartcounterevents "github.com/One-Hundred-Eighty/Circle/pkg/dartcounter-events"
+	detectionpipeline "github.com/One-Hundred-Eighty/Circle/pkg/detection-pipeline"
+)
+
+// matchCreatedEvent returns the sse-event that announces a new running match.
+func matchCreatedEvent(matchID int, state gameengine.MatchState) dartcounterevents.MatchCreatedEvent {
+	event := dartcounterevents.MatchCreatedEvent{
+		Header:      dartcounterevents.NewHeader(dartcounterevents.MatchCreated, matchID),
+		Players:     []string{},
+		Mode:        string(state.Options.Mode),
+		GameOptions: state.Options.GameOptions,
+		Legs:        state.Options.Legs,
+		Sets:        state.Options.Sets,
+	}
+	for _, p := range state.Players {
+		event.Players = append(event.Players, p.Name)
+	}
+	return event
+}
+
+// gameEvent converts a game event into its sse-event. The state is the match state after the event.
+func gameEvent(matchID int, e gameengine.Event, state gameengine.MatchState) (dartcounterevents.Type, any) {
+	eventType := dartcounterevents.Type(e.Type)
+	header := dartcounterevents.NewHeader(eventType, matchID)
+
+	switch e.Type {
+	case gameengine.EventThrowRegistered:
+		event := dartcounterevents.ThrowRegisteredEvent{Header: header, Player: e.Player, Points: e.Points, Marks: e.Marks}
+		if e.Dart != nil {
+			event.Dart = sseDart(*e.Dart)
+		}
+		return eventType, event
+	case gameengine.EventVisitEnded:
+		return eventType, dartcounterevents.VisitEndedEvent{Header: header, Player: e.Player, Darts: sseDarts(e.Visit), Points: e.Points}
+	case gameengine.EventBust:
+		return eventType, dartcounterevents.BustEvent{Header: header, Player: e.Player, Darts: sseDarts(e.Visit)}
+	case gameengine.EventLegStarted:
+		return eventType, dartcounterevents.LegEvent{Header: header, Player: e.Player, Set: e.Set, Leg: e.Leg}
+	case gameengine.EventLegWon:
+		event := dartcounterevents.LegEvent{Header: header, Player: e.Player}
+		// the won leg is the last finished leg of the match
+		for i := len(state.Legs) - 1; i >= 0; i-- {
+			if state.Legs[i].Winner >= 0 {
+				event.Set, event.Leg = state.Legs[i].Set, state.Legs[i].Leg
+				break
+			}
+		}
+		return eventType, event
+	case gameengine.EventSetWon:
+		return eventType, dartcounterevents.LegEvent{Header: header, Player: e.Player, Set: e.Set}
+	case gameengine.EventCorrection:
+		event := dartcounterevents.CorrectionEvent{Header: header, Player: e.Player, Darts: sseDarts(e.Visit)}
+		if e.Dart != nil {
+			event.Dart = sseDart(*e.Dart)
+		}
+		if e.Previous != nil {
+			event.Previous = sseDart(*e.Previous)
+		}
+		return eventType, event
+	default:
+		// player-changed, match-won, match-ended, match-abandoned, player-eliminated and throw-undone
+		return eventType, dartcounterevents.PlayerEvent{Header: header, Player: e.Player}
+	}
+}
+
+// pendingThrowEvent returns the sse-event of a throw that needs a confirmation (or was rejected).
+func pendingThrowEvent(eventType dartcounterevents.Type, matchID int, event detectionpipeline.Event) dartcounterevents.PendingThrowEvent {
+	return dartcounterevents.PendingThrowEvent{
+		Header:     dartcounterevents.NewHeader(eventType, matchID),
+		Dart:       sseDart(gameengine.DartFromScore(event.Throw.Score)),
+		Reason:     event.Throw.Reason,
+		Confidence: event.Throw.Confidence,
+	}
+}
+
+// detectionEvent returns the sse-event type and the payload of an event of the detection pipeline.
+func detectionEvent(matchID int, event detectionpipeline.Event) (dartcounterevents.Type, dartcounterevents.DetectionEvent) {
+	eventType := dartcounterevents.ThrowDetected
+	switch event.Type {
+	case detectionpipeline.Takeout:
+		eventType = dartcounterevents.TakeoutDetected
+	case detectionpipeline.BounceOut:
+		eventType = dartcounterevents.BounceOutDetected
+	}
+	payload := dartcounterevents.DetectionEvent{
+		Header:    dartcounterevents.NewHeader(eventType, matchID),
+		Timestamp: event.Timestamp,
+		Manual:    event.Manual,
+	}
+	if event.Throw != nil {
+		payload.Throw = &dartcounterevents.DetectedThrow{
+			Dart:              sseDart(gameengine.DartFromScore(event.Throw.Score)),
+			X:                 event.Throw.Position.X,
+			Y:                 event.Throw.Position.Y,
+			Confidence:        event.Throw.Confidence,
+			NeedsConfirmation: event.Throw.NeedsConfirmation,
+			Reason:            event.Throw.Reason,
+		}
+	}
+	return eventType, payload
+}
+
+func sseDart(d gameengine.Dart) dartcounterevents.Dart {
+	return dartcounterevents.Dart{Segment: d.Segment, Multiplier: d.Multiplier, Label: d.String()}
+}
+
+func sseDarts(darts []gameengine.Dart) []dartcounterevents.Dart {
+	result := make([]dartcounterevents.Dart, 0, len(darts))
+	for _, d := range darts {
+		result = append(result, sseDart(d))
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+package matchstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	gameengine "github.com/One-Hundred-Eighty/Circle/backend/cir-dartcounter/game-engine"
+	atomicfile "github.com/One-Hundred-Eighty/Circle/pkg/atomic-file"
+)
+
+// DefaultStoreDir is the directory where the matches are persisted by default.
+const DefaultStoreDir = "/var/lib/dartmaster/matches"
+
+var matchFile = regexp.MustCompile(`^match-([0-9]+)\.json$`)
+
+// usedMatchFile matches the files of all matches that used a match-id, also the ones moved aside.
+var usedMatchFile = regexp.MustCompile(`^match-([0-9]+)\.json(\.bad)?$`)
+
+// archiveDir is the subdirectory of the finished matches. They are kept (e.g. for audits), but not loaded.
+const archiveDir = "archive"
+
+// eventIDFile contains the highest sse-event id that may have been sent, so the ids keep increasing after a restart.
+const eventIDFile = "event-id"
+
+// badFileSuffix is appended to match files that can't be loaded.
+const badFileSuffix = ".bad"
+
+// storedMatch is the content of a match file.
+type storedMatch struct {
+	ID      int                   `json:"id"`
+	Entries []gameengine.LogEntry `json:"entries"`
+}
+
+// Store persists the logs of the matches as json files (one file per match). The files are replaced atomically, so a
+// crash (e.g. a power cut) leaves the last completely written log behind.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewStore returns a match store that persists the matches inside the hand-overed directory.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("NewStore() - error: creating directory %s: %v", dir, err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Save persists the log of a match.
+func (s *Store) Save(matchID int, entries []gameengine.LogEntry) error {
+	data, err := json.Marshal(storedMatch{ID: matchID, Entries: entries})
+	if err != nil {
+		return fmt.Errorf("Save() - error: encoding match %d: %v", matchID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := atomicfile.Write(s.path(matchID), data); err != nil {
+		return fmt.Errorf("Save() - error: writing match %d: %v", matchID, err)
+	}
+	return nil
+}
+
+// Load returns the logs of all persisted matches (not the archived ones) by match-id. Files that can't be read are moved aside (suffix
+// ".bad"), so they don't block the next boot; the problems are returned as error next to the readable matches.
+func (s *Store) Load() (map[int][]gameengine.LogEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("Load() - error: %v", err)
+	}
+	matches := make(map[int][]gameengine.LogEntry)
+	var errs []error
+	for _, file := range files {
+		match := matchFile.FindStringSubmatch(file.Name())
+		if match == nil {
+			continue
+		}
+		matchID, entries, err := s.loadMatch(file.Name(), match[1])
+		if err != nil {
+			path := filepath.Join(s.dir, file.Name())
+			if renameErr := os.Rename(path, path+badFileSuffix); renameErr != nil {
+				err = fmt.Errorf("%v (moving aside: %v)", err, renameErr)
+			}
+			errs = append(errs, fmt.Errorf("Load() - error: skipped %s: %v", file.Name(), err))
+			continue
+		}
+		matches[matchID] = entries
+	}
+	return matches, errors.Join(errs...)
+}
+
+// loadMatch reads a single match file.
+func (s *Store) loadMatch(name, id string) (int, []gameengine.LogEntry, error) {
+	matchID, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid match id: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		return 0, nil, err
+	}
+	var stored storedMatch
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return 0, nil, fmt.Errorf("decoding: %v", err)
+	}
+	if stored.ID != matchID {
+		return 0, nil, fmt.Errorf("contains match %d", stored.ID)
+	}
+	return matchID, stored.Entries, nil
+}
+
+// LastMatchID returns the highest match-id of all persisted matches, including the archived matches and the files that
+// were moved aside, so a new match never reuses the id of a stored one. 0 if no match was persisted yet.
+func (s *Store) LastMatchID() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lastID := 0
+	for _, dir := range []string{s.dir, filepath.Join(s.dir, archiveDir)} {
+		files, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("LastMatchID() - error: %v", err)
+		}
+		for _, file := range files {
+			match := usedMatchFile.FindStringSubmatch(file.Name())
+			if match == nil {
+				continue
+			}
+			if id, err := strconv.Atoi(match[1]); err == nil && id > lastID {
+				lastID = id
+			}
+		}
+	}
+	return lastID, nil
+}
+
+// MoveAside moves the log of a match aside (suffix ".bad"), e.g. if it can't be replayed. It is not loaded anymore.
+func (s *Store) MoveAside(matchID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Rename(s.path(matchID), s.path(matchID)+badFileSuffix); err != nil {
+		return fmt.Errorf("MoveAside() - error: match %d: %v", matchID, err)
+	}
+	return nil
+}
+
+// Archive moves the log of a finished match into the archive, so it is kept but not loaded anymore.
+func (s *Store) Archive(matchID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dir := filepath.Join(s.dir, archiveDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("Archive() - error: creating directory %s: %v", dir, err)
+	}
+	if err := os.Rename(s.path(matchID), filepath.Join(dir, filepath.Base(s.path(matchID)))); err != nil {
+		return fmt.Errorf("Archive() - error: match %d: %v", matchID, err)
+	}
+	return nil
+}
+
+// SaveLastEventID persists the highest sse-event id that may have been sent.
+func (s *Store) SaveLastEventID(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := atomicfile.Write(filepath.Join(s.dir, eventIDFile), []byte(strconv.FormatUint(id, 10))); err != nil {
+		return fmt.Errorf("SaveLastEventID() - error: %v", err)
+	}
+	return nil
+}
+
+// LoadLastEventID returns the persisted sse-event id, 0 if no id was persisted yet.
+func (s *Store) LoadLastEventID() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(filepath.Join(s.dir, eventIDFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("LoadLastEventID() - error: %v", err)
+	}
+	id, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("LoadLastEventID() - error: invalid event id: %v", err)
+	}
+	return id, nil
+}
+
+func (s *Store) path(matchID int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("match-%d.json", matchID))
+}
@@ -0,0 +1,157 @@
+package matchstore
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	gameengine "github.com/One-Hundred-Eighty/Circle/backend/cir-dartcounter/game-engine"
+)
+
+func newTestEntries(t *testing.T) []gameengine.LogEntry {
+	t.Helper()
+	matchLog, err := gameengine.NewMatchLog([]string{"Anna", "Ben"}, gameengine.DefaultMatchOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := matchLog.Throw(gameengine.Dart{Segment: 20, Multiplier: 3}); err != nil {
+		t.Fatal(err)
+	}
+	return matchLog.Entries()
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := newTestEntries(t)
+	for _, id := range []int{1, 2} {
+		if err := store.Save(id, entries); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("%d matches loaded, want 2", len(matches))
+	}
+	loaded, err := gameengine.ReplayMatchLog(matches[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := gameengine.ReplayMatchLog(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.State(), saved.State()) {
+		t.Errorf("loaded state %+v, want %+v", loaded.State(), saved.State())
+	}
+}
+
+// a file that cannot be loaded is moved aside, the other matches are loaded
+func TestLoadSkipsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(1, newTestEntries(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{name: "truncated", file: "match-2.json", data: `{"id":2,"entries":[`},
+		{name: "wrong id", file: "match-3.json", data: `{"id":4,"entries":[]}`},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	matches, err := store.Load()
+	if err == nil {
+		t.Error("Load() reported no error for the bad files")
+	}
+	if len(matches) != 1 || matches[1] == nil {
+		t.Errorf("loaded matches %v, want match 1", reflect.ValueOf(matches).MapKeys())
+	}
+	for _, tt := range tests {
+		if _, err := os.Stat(filepath.Join(dir, tt.file+badFileSuffix)); err != nil {
+			t.Errorf("%s: file not moved aside: %v", tt.name, err)
+		}
+	}
+
+	// --> the next boot loads without errors
+	if _, err := store.Load(); err != nil {
+		t.Errorf("second Load() error: %v", err)
+	}
+}
+
+// archived matches and files moved aside are not loaded, but their ids count for the last match-id
+func TestArchiveAndMoveAside(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := store.LastMatchID(); err != nil || id != 0 {
+		t.Errorf("LastMatchID() = %d, %v before the first save, want 0", id, err)
+	}
+	entries := newTestEntries(t)
+	for _, id := range []int{1, 2, 3} {
+		if err := store.Save(id, entries); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Archive(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.MoveAside(3); err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[1] == nil {
+		t.Errorf("loaded matches %v, want match 1", reflect.ValueOf(matches).MapKeys())
+	}
+	if id, err := store.LastMatchID(); err != nil || id != 3 {
+		t.Errorf("LastMatchID() = %d, %v, want 3", id, err)
+	}
+	if err := store.Archive(1); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := store.LastMatchID(); err != nil || id != 3 {
+		t.Errorf("LastMatchID() = %d, %v after archiving every match, want 3", id, err)
+	}
+	if err := store.MoveAside(4); err == nil {
+		t.Error("MoveAside() of an unknown match succeeded")
+	}
+}
+
+func TestLastEventID(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := store.LoadLastEventID(); err != nil || id != 0 {
+		t.Errorf("LoadLastEventID() = %d, %v before the first save, want 0", id, err)
+	}
+	if err := store.SaveLastEventID(1999); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := store.LoadLastEventID(); err != nil || id != 1999 {
+		t.Errorf("LoadLastEventID() = %d, %v, want 1999", id, err)
+	}
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cameraadmin "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/device"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+const usage = `cir-camera - camera diagnostics for the Dartmaster
+
+usage:
+  cir-camera list
+  cir-camera info        -device /dev/video0
+  cir-camera formats     -device /dev/video0
+  cir-camera controls    -device /dev/video0
+  cir-camera set-control -device /dev/video0 -name brightness -value 128
+  cir-camera snapshot    -device /dev/video0 [-width 1920 -height 1080] -out snapshot.jpg
+  cir-camera record      -device /dev/video0 [-width 1920 -height 1080] -duration 10s -out record.mjpeg
+  cir-camera measure     [-device /dev/video0] [-width 1920 -height 1080] -duration 10s
+
+measure runs on all discovered cameras if no device is given.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+
+	var err error
+	command, args := os.Args[1], os.Args[2:]
+	switch command {
+	case "list":
+		err = list()
+	case "info":
+		err = info(args)
+	case "formats":
+		err = formats(args)
+	case "controls":
+		err = controls(args)
+	case "set-control":
+		err = setControl(args)
+	case "snapshot":
+		err = snapshot(args)
+	case "record":
+		err = record(args)
+	case "measure":
+		err = measure(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Printf("unknown command: %s\n\n%s", command, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// list prints all discovered capture devices.
+func list() error {
+	descriptions, err := device.Discover()
+	if err != nil {
+		return err
+	}
+	if len(descriptions) == 0 {
+		fmt.Println("no cameras found")
+		return nil
+	}
+	for i, d := range descriptions {
+		fmt.Printf("camera %d: %s\t%s (%s, %s)\n", i+1, d.Path, d.Capability.Card, d.Capability.Driver, d.Capability.BusInfo)
+	}
+	return nil
+}
+
+// info prints the capabilities and the active format of a device.
+func info(args []string) error {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	devicePath := fs.String("device", "", "device path, e.g. /dev/video0")
+	fs.Parse(args)
+
+	dev, err := openDevice(*devicePath)
+	if err != nil {
+		return err
+	}
+	defer dev.Close()
+
+	fmt.Println(dev.Info())
+	return nil
+}
+
+// formats prints all pixel formats, frame sizes and frame rates of a device.
+func formats(args []string) error {
+	fs := flag.NewFlagSet("formats", flag.ExitOnError)
+	devicePath := fs.String("device", "", "device path, e.g. /dev/video0")
+	fs.Parse(args)
+
+	dev, err := openDevice(*devicePath)
+	if err != nil {
+		return err
+	}
+	defer dev.Close()
+
+	formats, err := dev.Formats()
+	if err != nil {
+		return err
+	}
+	for _, f := range formats {
+		var flags []string
+		if f.Description.Flags&v4l2.FormatFlagCompressed != 0 {
+			flags = append(flags, "compressed")
+		}
+		if f.Description.Flags&v4l2.FormatFlagEmulated != 0 {
+			flags = append(flags, "emulated")
+		}
+		fmt.Printf("%s - %s %v\n", f.Description.PixelFormat, f.Description.Description, flags)
+
+		for _, s := range f.FrameSizes {
+			if s.Size.Type != v4l2.FrameSizeTypeDiscrete {
+				fmt.Printf("\t%dx%d - %dx%d (step %dx%d)\n", s.Size.MinWidth, s.Size.MinHeight, s.Size.MaxWidth, s.Size.MaxHeight, s.Size.StepWidth, s.Size.StepHeight)
+				continue
+			}
+			var rates []string
+			for _, interval := range s.Intervals {
+				if interval.Min.Numerator != 0 {
+					rates = append(rates, fmt.Sprintf("%.1f", float64(interval.Min.Denominator)/float64(interval.Min.Numerator)))
+				}
+			}
+			fmt.Printf("\t%dx%d @ %s fps\n", s.Size.MaxWidth, s.Size.MaxHeight, strings.Join(rates, ", "))
+		}
+	}
+	return nil
+}
+
+// controls prints all controls of a device with their ranges and current values.
+func controls(args []string) error {
+	fs := flag.NewFlagSet("controls", flag.ExitOnError)
+	devicePath := fs.String("device", "", "device path, e.g. /dev/video0")
+	fs.Parse(args)
+
+	dev, err := openDevice(*devicePath)
+	if err != nil {
+		return err
+	}
+	defer dev.Close()
+
+	controls, err := dev.Controls()
+	if err != nil {
+		return err
+	}
+	for _, c := range controls {
+		value := "-"
+		if c.IsReadable() {
+			value = fmt.Sprintf("%d", c.Value)
+		}
+		var flags string
+		if c.Flags&v4l2.CtrlFlagInactive != 0 {
+			flags += " (inactive)"
+		}
+		if c.Flags&v4l2.CtrlFlagReadOnly != 0 {
+			flags += " (read-only)"
+		}
+		fmt.Printf("%-40s value: %-6s range: [%d, %d] step: %d default: %d%s\n", c.Name, value, c.Minimum, c.Maximum, c.Step, c.Default, flags)
+		for _, item := range c.Menu {
+			fmt.Printf("\t%d: %s\n", item.Index, item.Name)
+		}
+	}
+	return nil
+}
+
+// setControl sets the value of a device control.
+func setControl(args []string) error {
+	fs := flag.NewFlagSet("set-control", flag.ExitOnError)
+	devicePath := fs.String("device", "", "device path, e.g. /dev/video0")
+	name := fs.String("name", "", "control name, e.g. brightness")
+	value := fs.Int("value", 0, "control value")
+	fs.Parse(args)
+
+	if *name == "" {
+		return fmt.Errorf("missing control name")
+	}
+
+	dev, err := openDevice(*devicePath)
+	if err != nil {
+		return err
+	}
+	defer dev.Close()
+
+	control, err := dev.Control(*name)
+	if err != nil {
+		return err
+	}
+	if err := dev.SetControl(control, int32(*value)); err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d --> %d\n", control.Name, control.Value, *value)
+	return nil
+}
+
+// snapshot captures a single frame and writes it to a file. The file type is chosen by the file extension (.jpg, .png or raw).
+func snapshot(args []string) error {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	devicePath := fs.String("device", "", "device path, e.g. /dev/video0")
+	width := fs.Int("width", 1920, "resolution-width in pixels")
+	height := fs.Int("height", 1080, "resolution-height in pixels")
+	out := fs.String("out", "snapshot.jpg", "output file (.jpg, .png or raw)")
+	fs.Parse(args)
+
+	if *devicePath == "" {
+		return fmt.Errorf("missing device path")
+	}
+	cameraAdmin := cameraadmin.NewCameraAdmin(cameraadmin.WithCameras(*devicePath))
+	if err := cameraAdmin.Start(*width, *height); err != nil {
+		return err
+	}
+	defer cameraAdmin.ShutDown()
+
+	f, err := cameraAdmin.Snapshot(1, 5*time.Second)
+	if err != nil {
+		return err
+	}
+
+	data := f.Data
+	isJPEG := f.PixFormat.PixelFormat == v4l2.PixelFmtMJPEG || f.PixFormat.PixelFormat == v4l2.PixelFmtJPEG
+	switch strings.ToLower(filepath.Ext(*out)) {
+	case ".jpg", ".jpeg":
+		if !isJPEG {
+			img, err := f.Image()
+			if err != nil {
+				return err
+			}
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+				return err
+			}
+			data = buf.Bytes()
+		}
+	case ".png":
+		img, err := f.Image()
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("snapshot written to %s (%s %dx%d)\n", *out, f.PixFormat.PixelFormat, f.PixFormat.Width, f.PixFormat.Height)
+	return nil
+}
+
+// record writes all frames of the given duration into a file. MJPEG frames are concatenated to a playable mjpeg stream.
+func record(args []string) error {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	devicePath := fs.String("device", "", "device path, e.g. /dev/video0")
+	width := fs.Int("width", 1920, "resolution-width in pixels")
+	height := fs.Int("height", 1080, "resolution-height in pixels")
+	duration := fs.Duration("duration", 10*time.Second, "recording duration")
+	out := fs.String("out", "record.mjpeg", "output file")
+	fs.Parse(args)
+
+	if *devicePath == "" {
+		return fmt.Errorf("missing device path")
+	}
+	cameraAdmin := cameraadmin.NewCameraAdmin(cameraadmin.WithCameras(*devicePath))
+	if err := cameraAdmin.Start(*width, *height); err != nil {
+		return err
+	}
+	defer cameraAdmin.ShutDown()
+
+	// --> the file is created after the device was opened, so a wrong device leaves no empty recording behind
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	frameCh := cameraAdmin.Subscribe(1, "record")
+	defer cameraAdmin.Unsubscribe(1, frameCh, "record")
+
+	var frames int
+	timeoutCh := time.After(*duration)
+	for {
+		select {
+		case f, ok := <-frameCh:
+			if !ok {
+				return fmt.Errorf("camera was shut down")
+			}
+			if f.IsEmpty() {
+				continue
+			}
+			if _, err := file.Write(f.Data); err != nil {
+				return err
+			}
+			frames++
+		case <-timeoutCh:
+			fmt.Printf("%d frames recorded to %s (%.1f fps)\n", frames, *out, float64(frames)/duration.Seconds())
+			return nil
+		}
+	}
+}
+
+// measure measures the actual frame rate, the capture latency and the dropped frames per camera.
+func measure(args []string) error {
+	fs := flag.NewFlagSet("measure", flag.ExitOnError)
+	devicePath := fs.String("device", "", "device path, e.g. /dev/video0 (default: all discovered cameras)")
+	width := fs.Int("width", 1920, "resolution-width in pixels")
+	height := fs.Int("height", 1080, "resolution-height in pixels")
+	duration := fs.Duration("duration", 10*time.Second, "measuring duration")
+	fs.Parse(args)
+
+	devicePaths := []string{*devicePath}
+	if *devicePath == "" {
+		descriptions, err := device.Discover()
+		if err != nil {
+			return err
+		}
+		if len(descriptions) == 0 {
+			return fmt.Errorf("no cameras found")
+		}
+		devicePaths = nil
+		for _, d := range descriptions {
+			devicePaths = append(devicePaths, d.Path)
+		}
+	}
+
+	cameraAdmin := cameraadmin.NewCameraAdmin(cameraadmin.WithCameras(devicePaths...))
+	if err := cameraAdmin.Start(*width, *height); err != nil {
+		return err
+	}
+	defer cameraAdmin.ShutDown()
+
+	// let the cameras settle (auto exposure, first frames)
+	time.Sleep(2 * time.Second)
+
+	// count the frames that actually reach a subscriber
+	var mu sync.Mutex
+	received := make([]int, len(devicePaths))
+	for _, cameraID := range cameraAdmin.CameraIDs() {
+		cameraAdmin.ResetStats(cameraID)
+		frameCh := cameraAdmin.Subscribe(cameraID, "measure")
+		go func(cameraID int, frameCh <-chan frame.Frame) {
+			for f := range frameCh {
+				if !f.IsEmpty() {
+					mu.Lock()
+					received[cameraID-1]++
+					mu.Unlock()
+				}
+			}
+		}(cameraID, frameCh)
+		defer cameraAdmin.Unsubscribe(cameraID, frameCh, "measure")
+	}
+
+	fmt.Printf("measuring %d camera(s) for %v...\n", len(devicePaths), *duration)
+	time.Sleep(*duration)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, cameraID := range cameraAdmin.CameraIDs() {
+		stats, err := cameraAdmin.Stats(cameraID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("camera %d (%s): %.1f fps, %d frames, %d dropped, %d errors, %d received, latency avg %v / max %v\n",
+			cameraID, devicePaths[cameraID-1], stats.FPS(), stats.Frames, stats.DroppedFrames, stats.ErrorFrames, received[cameraID-1],
+			stats.AvgLatency().Round(time.Microsecond), stats.MaxLatency.Round(time.Microsecond))
+	}
+	return nil
+}
+
+// openDevice opens a device for diagnostics without starting the stream.
+func openDevice(devicePath string) (*device.Device, error) {
+	if devicePath == "" {
+		return nil, fmt.Errorf("missing device path")
+	}
+	return device.Open(devicePath)
+}
@@ -6,20 +6,74 @@ import (
 	"os/signal"
 	"syscall"
 
+	circamera "github.com/One-Hundred-Eighty/Circle/backend/cir-camera"
+	cameragateway "github.com/One-Hundred-Eighty/Circle/backend/cir-camera/gateway"
 	cirdartcounter "github.com/One-Hundred-Eighty/Circle/backend/cir-dartcounter"
+	dartcountergateway "github.com/One-Hundred-Eighty/Circle/backend/cir-dartcounter/gateway"
+	matchstore "github.com/One-Hundred-Eighty/Circle/backend/cir-dartcounter/match-store"
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	cameraadmin "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
 	dartmasterlogger "github.com/One-Hundred-Eighty/Circle/pkg/dartmaster-logger"
+	detectionpipeline "github.com/One-Hundred-Eighty/Circle/pkg/detection-pipeline"
 )
 
 func main() {
 	// create loggers
 	mainLogger := dartmasterlogger.NewDartmasterLogger("[main] ")
 	dartcounterServerLogger := dartmasterlogger.NewDartmasterLogger("[dartcounter-server] ")
+	cameraServerLogger := dartmasterlogger.NewDartmasterLogger("[camera-server] ")
 
 	mainLogger.Println("boot servers...")
 	fmt.Println()
 
+	// start the cameras --> the camera server and the detection pipeline are only booted if the cameras are available
+	var dartcounterDetections dartcountergateway.DetectionPipeline
+	var cameraDetections cameragateway.DetectionPipeline
+	cameraAdmin := cameraadmin.NewCameraAdmin()
+	calibrationStore, err := calibration.NewStore(calibration.DefaultStoreDir)
+	var intrinsicsStore *calibration.IntrinsicsStore
+	if err == nil {
+		intrinsicsStore, err = calibration.NewIntrinsicsStore(calibration.DefaultIntrinsicsStoreDir)
+	}
+	if err != nil {
+		mainLogger.PrintfErr("error occurred loading calibrations: %v", err)
+	} else if err := cameraAdmin.Start(1920, 1080); err != nil {
+		mainLogger.PrintfErr("error occurred starting cameras: %v", err)
+	} else {
+		defer cameraAdmin.ShutDown()
+
+		// detect the throws of all calibrated cameras
+		detectionPipeline := detectionpipeline.NewPipeline(cameraAdmin, calibrationStore, dartboard.Standard())
+		if err := detectionPipeline.Start(); err != nil {
+			mainLogger.PrintfErr("error occurred starting detection pipeline: %v", err)
+		} else {
+			defer detectionPipeline.Stop()
+			dartcounterDetections = detectionPipeline
+			cameraDetections = detectionPipeline
+			cameraAdmin.SetOverlaySource(detectionPipeline.OverlayData)
+		}
+
+		cameraServer := circamera.NewServer(cameraServerLogger, cameraAdmin, calibrationStore, intrinsicsStore, cameraDetections, "8889")
+		go func() {
+			err := cameraServer.ListenAndServe()
+			if err != nil {
+				mainLogger.PrintfErr("error occurred starting server: %v", err)
+			}
+		}()
+	}
+
+	// persist the matches --> unfinished matches continue after a restart
+	var dartcounterMatches dartcountergateway.MatchStore
+	matchStore, err := matchstore.NewStore(matchstore.DefaultStoreDir)
+	if err != nil {
+		mainLogger.PrintfErr("error occurred opening the match store, matches are not persisted: %v", err)
+	} else {
+		dartcounterMatches = matchStore
+	}
+
 	// create servers
-	dartcounterServer := cirdartcounter.NewServer(dartcounterServerLogger, "8888")
+	dartcounterServer := cirdartcounter.NewServer(dartcounterServerLogger, dartcounterDetections, dartcounterMatches, "8888")
 
 	// run boot the servers
 	go func() {
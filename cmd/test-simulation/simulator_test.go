@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+// TestScenarios runs all built-in scenarios at a low resolution. The durations are scaled to the speed of the machine,
+// so the test also passes with the race detector (go test -race).
+func TestScenarios(t *testing.T) {
+	if testing.Short() {
+		t.Skip("end-to-end simulation skipped in short mode")
+	}
+	if err := run(640, 480, 15, false, scenarios); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScenariosAutoCalibrated(t *testing.T) {
+	if testing.Short() {
+		t.Skip("end-to-end simulation skipped in short mode")
+	}
+	if err := run(640, 480, 15, true, scenarios[:1]); err != nil {
+		t.Fatal(err)
+	}
+}
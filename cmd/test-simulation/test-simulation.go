@@ -0,0 +1,383 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	gameengine "github.com/One-Hundred-Eighty/Circle/backend/cir-dartcounter/game-engine"
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	cameraadmin "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	motiondetector "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/motion-detector"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+	detectionpipeline "github.com/One-Hundred-Eighty/Circle/pkg/detection-pipeline"
+	"github.com/One-Hundred-Eighty/Circle/pkg/simulator"
+)
+
+const usage = `test-simulation - end-to-end test of the throw detection with simulated cameras
+
+usage:
+  test-simulation [-width 1280 -height 960] [-fps 15] [-auto-calibrate] [-script T20,D16,bounce-out,takeout]
+
+A script is a comma separated list of steps: a score label (e.g. T20, D16, S5, BULL, 25) throws a dart into the
+center of the area and expects the same score, "bounce-out" expects a missed dart and "takeout" expects a takeout.
+Without a script, all built-in scenarios are run. The detected darts are counted in a match; at the end the match log is
+replayed and has to yield the identical match state.
+
+The durations of the simulation (timeouts, group window, visible bounce-outs) are scaled to the measured frame
+processing speed of the machine, so the scenarios also pass on slow machines or with the race detector.
+`
+
+const (
+	// eventTimeout is the maximum time to wait for the detection of a step (on a machine that processes all frames)
+	eventTimeout = 5 * time.Second
+	// groupWindow is the time to wait for the motion events of the other cameras (on a machine that processes all frames)
+	groupWindow = 300 * time.Millisecond
+	// stallTimeout is the maximum time to wait for frames of the cameras before they count as stalled
+	stallTimeout = 5 * time.Minute
+)
+
+type scenario struct {
+	name  string
+	steps []string
+}
+
+var scenarios = []scenario{
+	{name: "treble, double, bounce-out", steps: []string{"T20", "D16", "bounce-out", "takeout"}},
+	{name: "bulls and singles", steps: []string{"BULL", "25", "S5", "takeout"}},
+	{name: "all trebles of the top", steps: []string{"T1", "T18", "T5", "takeout"}},
+	{name: "doubles around the board", steps: []string{"D6", "D3", "D11", "takeout"}},
+}
+
+func main() {
+	width := flag.Int("width", 1280, "resolution-width of the simulated cameras in pixels")
+	height := flag.Int("height", 960, "resolution-height of the simulated cameras in pixels")
+	fps := flag.Int("fps", 15, "frame rate of the simulated cameras")
+	autoCalibrate := flag.Bool("auto-calibrate", false, "calibrate the cameras by the automatic board detection instead of the exact calibration")
+	script := flag.String("script", "", "comma separated steps of a single scenario")
+	flag.Usage = func() { fmt.Print(usage) }
+	flag.Parse()
+
+	runScenarios := scenarios
+	if *script != "" {
+		runScenarios = []scenario{{name: "script", steps: strings.Split(*script, ",")}}
+	}
+
+	if err := run(*width, *height, *fps, *autoCalibrate, runScenarios); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(width, height, fps int, autoCalibrate bool, scenarios []scenario) error {
+	board := dartboard.Standard()
+	sim := simulator.NewSimulator(board, simulator.DefaultViews(width, height), fps)
+
+	// virtual cameras --> the motion detection is tuned to the small simulated darts
+	options := []cameraadmin.Option{cameraadmin.WithFrameSources(sim.Sources()...)}
+	motionConfig := motiondetector.DefaultConfig()
+	motionConfig.Scale = 2
+	motionConfig.MotionRatio = 0.0002
+	motionConfig.ChangeRatio = 0.0001
+	for i := range sim.Views() {
+		options = append(options, cameraadmin.WithMotionConfig(i+1, motionConfig))
+	}
+	cameraAdmin := cameraadmin.NewCameraAdmin(options...)
+	if err := cameraAdmin.Start(width, height); err != nil {
+		return err
+	}
+	defer cameraAdmin.ShutDown()
+
+	// calibrate the cameras
+	storeDir, err := os.MkdirTemp("", "test-simulation-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(storeDir)
+	calibrationStore, err := calibration.NewStore(storeDir)
+	if err != nil {
+		return err
+	}
+	for i, view := range sim.Views() {
+		cameraID := i + 1
+		c, err := calibrate(cameraAdmin, cameraID, view, autoCalibrate)
+		if err != nil {
+			return fmt.Errorf("calibrating camera %d: %v", cameraID, err)
+		}
+		if err := calibrationStore.Save(c); err != nil {
+			return err
+		}
+		fmt.Printf("camera %d calibrated (reprojection error: %.2f mm)\n", cameraID, c.ReprojectionError)
+	}
+
+	t, err := measureTiming(cameraAdmin, sim, fps, motionConfig.SettleFrames)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("durations scaled by %.1f to the frame processing speed\n", t.scale)
+
+	bounceOutConfig := detectionpipeline.DefaultBounceOutConfig()
+	bounceOutConfig.MaxMotionDuration = t.duration(bounceOutConfig.MaxMotionDuration)
+	pipeline := detectionpipeline.NewPipeline(cameraAdmin, calibrationStore, board,
+		detectionpipeline.WithGroupWindow(t.duration(groupWindow)), detectionpipeline.WithBounceOutConfig(bounceOutConfig))
+	if err := pipeline.Start(); err != nil {
+		return err
+	}
+	defer pipeline.Stop()
+	eventCh := pipeline.Subscribe()
+
+	// the motion detectors of the pipeline learn the empty board
+	if err := learnEmptyBoard(cameraAdmin); err != nil {
+		return err
+	}
+
+	// count the detections in a match (like the dartcounter does)
+	matchLog, err := gameengine.NewMatchLog([]string{"Player 1", "Player 2"}, gameengine.DefaultMatchOptions())
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, s := range scenarios {
+		fmt.Printf("\nscenario: %s\n", s.name)
+		for _, step := range s.steps {
+			event, result, err := runStep(sim, eventCh, strings.TrimSpace(step), t)
+			if err != nil {
+				failed++
+				fmt.Printf("  FAIL %-12s %v\n", step, err)
+				continue
+			}
+			fmt.Printf("  ok   %-12s %s\n", step, result)
+			if err := countDetection(matchLog, event, strings.TrimSpace(step)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d steps failed", failed)
+	}
+	if err := checkReplay(matchLog); err != nil {
+		return err
+	}
+	fmt.Println("\nall scenarios passed")
+	return nil
+}
+
+// snapshotter returns the frames of a camera.
+type snapshotter interface {
+	Snapshot(cameraID int, timeout time.Duration) (frame.Frame, error)
+}
+
+// motionSource returns the frames and the motion events of the cameras.
+type motionSource interface {
+	snapshotter
+	CameraIDs() []int
+	SubscribeMotion(cameraID int, subscriberName string) (<-chan motiondetector.Event, error)
+	UnsubscribeMotion(cameraID int, eventCh <-chan motiondetector.Event, subscriberName string)
+}
+
+// timing scales the durations of the simulation. A machine that is too slow to process every frame (e.g. with the race
+// detector) processes less frames per second, so a motion settles later and has to be visible longer to be seen.
+type timing struct {
+	fps   int
+	scale float64 // 1 if every frame is processed
+}
+
+// duration returns the scaled duration of a machine that processes every frame.
+func (t timing) duration(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * t.scale)
+}
+
+// frames returns the scaled duration of the hand-overed amount of frames.
+func (t timing) frames(n float64) time.Duration {
+	return t.duration(time.Duration(n * float64(time.Second) / float64(t.fps)))
+}
+
+// measureTiming measures how long the motion detectors of all cameras need to settle after a dart landed and compares it
+// with the time of a machine that processes every frame.
+func measureTiming(source motionSource, sim *simulator.Simulator, fps int, settleFrames int) (timing, error) {
+	var motionChs []<-chan motiondetector.Event
+	for _, cameraID := range source.CameraIDs() {
+		motionCh, err := source.SubscribeMotion(cameraID, "test-simulation")
+		if err != nil {
+			return timing{}, err
+		}
+		defer source.UnsubscribeMotion(cameraID, motionCh, "test-simulation")
+		motionChs = append(motionChs, motionCh)
+	}
+	if err := learnEmptyBoard(source); err != nil {
+		return timing{}, err
+	}
+
+	start := time.Now()
+	sim.Throw(calibration.Point{X: -60, Y: -60})
+	if err := waitSettled(motionChs); err != nil {
+		return timing{}, err
+	}
+	elapsed := time.Since(start)
+	sim.Clear()
+	if err := waitSettled(motionChs); err != nil {
+		return timing{}, err
+	}
+
+	// the motion starts with the first frame of the dart and settles after the still frames
+	nominal := time.Duration(settleFrames+1) * time.Second / time.Duration(fps)
+	return timing{fps: fps, scale: max(1, float64(elapsed)/float64(nominal))}, nil
+}
+
+// learnEmptyBoard waits until every camera has handed over a frame of the empty board to its motion detector. The
+// frame after a snapshot is published after the frame of the snapshot was fed into the motion detector.
+func learnEmptyBoard(source motionSource) error {
+	for _, cameraID := range source.CameraIDs() {
+		for i := 0; i < 2; i++ {
+			if _, err := source.Snapshot(cameraID, stallTimeout); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// waitSettled waits until the motion of every camera has settled.
+func waitSettled(motionChs []<-chan motiondetector.Event) error {
+	for _, motionCh := range motionChs {
+		for settled := false; !settled; {
+			select {
+			case event, ok := <-motionCh:
+				if !ok {
+					return fmt.Errorf("motion events were closed")
+				}
+				settled = event.Type == motiondetector.MotionSettled
+			case <-time.After(stallTimeout):
+				return fmt.Errorf("no motion settled within %v", stallTimeout)
+			}
+		}
+	}
+	return nil
+}
+
+// calibrate returns the exact calibration of a view or the calibration of the automatic board detection.
+func calibrate(cameraAdmin snapshotter, cameraID int, view simulator.CameraView, autoCalibrate bool) (calibration.Calibration, error) {
+	if !autoCalibrate {
+		return view.Calibration(cameraID)
+	}
+	f, err := cameraAdmin.Snapshot(cameraID, eventTimeout)
+	if err != nil {
+		return calibration.Calibration{}, err
+	}
+	img, err := f.Image()
+	if err != nil {
+		return calibration.Calibration{}, err
+	}
+	c, detection, err := calibration.AutoCalibrate(cameraID, img, calibration.DefaultDetectionConfig())
+	if err != nil {
+		return calibration.Calibration{}, err
+	}
+	fmt.Printf("camera %d: board detected (confidence: %.2f)\n", cameraID, detection.Confidence)
+	return c, nil
+}
+
+// runStep executes a step in the simulator and checks the event of the detection pipeline.
+func runStep(sim *simulator.Simulator, eventCh <-chan detectionpipeline.Event, step string, t timing) (detectionpipeline.Event, string, error) {
+	var want detectionpipeline.EventType
+	switch strings.ToLower(step) {
+	case "bounce-out":
+		want = detectionpipeline.BounceOut
+		go sim.BounceOut(calibration.Point{X: 40, Y: 40}, t.frames(1.5))
+	case "takeout":
+		want = detectionpipeline.Takeout
+		go sim.Takeout(t.duration(500 * time.Millisecond))
+	default:
+		want = detectionpipeline.Throw
+		if _, err := sim.ThrowAt(step); err != nil {
+			return detectionpipeline.Event{}, "", err
+		}
+	}
+
+	select {
+	case event, ok := <-eventCh:
+		if !ok {
+			return event, "", fmt.Errorf("detection pipeline was stopped")
+		}
+		if event.Type != want {
+			return event, "", fmt.Errorf("expected %s event, got %s", want, event.Type)
+		}
+		if want != detectionpipeline.Throw {
+			return event, string(event.Type), nil
+		}
+		result := event.Throw
+		if result.Score.Label != strings.ToUpper(step) {
+			return event, "", fmt.Errorf("expected %s, got %s at (%.1f, %.1f)", strings.ToUpper(step), result.Score.Label, result.Position.X, result.Position.Y)
+		}
+		summary := fmt.Sprintf("%s at (%.1f, %.1f), %d cameras, confidence %.2f", result.Score.Label, result.Position.X, result.Position.Y, len(result.Observations), result.Confidence)
+		if result.NeedsConfirmation {
+			summary += ", needs confirmation: " + result.Reason
+		}
+		return event, summary, nil
+	case <-time.After(t.duration(eventTimeout)):
+		return detectionpipeline.Event{}, "", fmt.Errorf("no %s event within %v", want, t.duration(eventTimeout))
+	}
+}
+
+// countDetection logs a detection event in the match. Bounce-outs count as missed darts. Like in the dartcounter, a
+// throw that needs a confirmation is not counted as detected: the operator confirms it with the dart of the step.
+func countDetection(matchLog *gameengine.MatchLog, event detectionpipeline.Event, step string) error {
+	var err error
+	switch event.Type {
+	case detectionpipeline.Throw:
+		dart := gameengine.DartFromScore(event.Throw.Score)
+		if event.Throw.NeedsConfirmation {
+			if dart, err = gameengine.ParseDart(step); err != nil {
+				return err
+			}
+			fmt.Printf("       confirmed as %s\n", dart)
+		}
+		_, err = matchLog.Throw(dart)
+	case detectionpipeline.BounceOut:
+		_, err = matchLog.Throw(gameengine.Miss)
+	case detectionpipeline.Takeout:
+		_, err = matchLog.EndVisit()
+	}
+	return err
+}
+
+// checkReplay corrects and undoes a dart of the match, then replays its log (encoded like a stored log) and compares
+// the replayed state with the running one.
+func checkReplay(matchLog *gameengine.MatchLog) error {
+	if _, err := matchLog.Throw(gameengine.Dart{Segment: 1, Multiplier: 1}); err != nil {
+		return err
+	}
+	if _, err := matchLog.Correct(false, 0, gameengine.Dart{Segment: 20, Multiplier: 3}); err != nil {
+		return err
+	}
+	if _, err := matchLog.Throw(gameengine.Miss); err != nil {
+		return err
+	}
+	if _, err := matchLog.Undo(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(matchLog.Entries())
+	if err != nil {
+		return err
+	}
+	var entries []gameengine.LogEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	replayed, err := gameengine.ReplayMatchLog(entries)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(replayed.State(), matchLog.State()) {
+		return fmt.Errorf("the replayed match log yields a different state")
+	}
+	fmt.Printf("\nmatch log replayed: %d entries, identical state\n", len(entries))
+	return nil
+}
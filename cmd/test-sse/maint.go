@@ -1,18 +1,12 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 
+	dartcounterevents "github.com/One-Hundred-Eighty/Circle/pkg/dartcounter-events"
 	uricaller "github.com/One-Hundred-Eighty/Circle/pkg/uri-caller"
 )
 
-// Auto-Struktur
-type Auto struct {
-	Marke   string `json:"marke"`
-	Baujahr int    `json:"baujahr"`
-}
-
 func main() {
 	dartcounterUriCaller := uricaller.NewDartcounterUriCaller()
 
@@ -22,23 +16,33 @@ func main() {
 	}
 
 	for sseEvent := range eventStream {
-		// encode json
-		var auto Auto
-		err := json.Unmarshal([]byte(sseEvent.Data), &auto)
-		if err != nil {
-			panic(err)
-		}
-
 		fmt.Println("---------------------------")
 		fmt.Printf("id: %s\n", sseEvent.Id)
 		fmt.Printf("type: %s\n", sseEvent.Event)
 		fmt.Printf("data: %s", sseEvent.Data)
 
-		fmt.Println()
+		// decode json
+		payload, err := sseEvent.Decode()
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
 
-		fmt.Printf("id: %s\n", sseEvent.Id)
-		fmt.Printf("type: %s\n", sseEvent.Event)
-		fmt.Printf("data:\tMarke: %s\n", auto.Marke)
-		fmt.Printf("\tBaujahr: %d\n", auto.Baujahr)
+		switch event := payload.(type) {
+		case *dartcounterevents.MatchCreatedEvent:
+			fmt.Printf("match %d created: %s, players: %v\n", event.MatchID, event.Mode, event.Players)
+		case *dartcounterevents.ThrowRegisteredEvent:
+			fmt.Printf("player %d: %s (%d points)\n", event.Player, event.Dart.Label, event.Points)
+		case *dartcounterevents.VisitEndedEvent:
+			fmt.Printf("player %d: visit ended with %d points\n", event.Player, event.Points)
+		case *dartcounterevents.BustEvent:
+			fmt.Printf("player %d: bust\n", event.Player)
+		case *dartcounterevents.LegEvent:
+			fmt.Printf("%s: player %d (set %d, leg %d)\n", event.Type, event.Player, event.Set, event.Leg)
+		case *dartcounterevents.CorrectionEvent:
+			fmt.Printf("player %d: %s corrected to %s\n", event.Player, event.Previous.Label, event.Dart.Label)
+		case *dartcounterevents.PlayerEvent:
+			fmt.Printf("%s: player %d\n", event.Type, event.Player)
+		}
 	}
 }
@@ -0,0 +1,509 @@
+package calibration
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	pixelconverter "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/pixel-converter"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+var ErrBoardNotFound = errors.New("board not found")
+
+// ratio of the board radii that are used to find the rings
+const (
+	trebleToDoubleRatio = trebleRingOuterRadius / doubleRingOuterRadius
+	doubleRingMidRatio  = 166.0 / doubleRingOuterRadius // middle of the double ring (inner edge at 162 mm)
+)
+
+type DetectionConfig struct {
+	// WorkingWidth is the image width the detection runs on. Larger images are downscaled.
+	WorkingWidth int
+	// TopSegment is the segment at the top of the camera image (20 for an upright mounted board)
+	TopSegment int
+	// MinConfidence is the minimum confidence of an automatic calibration
+	MinConfidence float64
+}
+
+// DefaultDetectionConfig returns the configuration for an upright mounted standard board.
+func DefaultDetectionConfig() DetectionConfig {
+	return DetectionConfig{
+		WorkingWidth:  640,
+		TopSegment:    20,
+		MinConfidence: 0.5,
+	}
+}
+
+// DetectionConfigFor returns the default configuration for the hand-overed board, with the top segment of its rotation.
+func DetectionConfigFor(board *dartboard.Board) DetectionConfig {
+	config := DefaultDetectionConfig()
+	config.TopSegment = board.TopSegment()
+	return config
+}
+
+// BoardDetection is the result of the automatic board detection. All points are in image pixels of the original image.
+type BoardDetection struct {
+	Bull            Point            `json:"bull"`
+	DoubleRing      Ellipse          `json:"doubleRing"` // outer edge of the double ring
+	TrebleRing      Ellipse          `json:"trebleRing"` // outer edge of the treble ring
+	WireAngles      []float64        `json:"wireAngles"` // image angles of the segment wires (radians), starting with the wire clockwise of the top segment
+	Correspondences []Correspondence `json:"correspondences"`
+	Confidence      float64          `json:"confidence"`
+}
+
+type colorClass uint8
+
+const (
+	colorNone colorClass = iota
+	colorRed
+	colorGreen
+)
+
+// segment colors of the double and treble ring: the segments with an even index in the segment order are red
+func segmentColor(segmentIdx int) colorClass {
+	if segmentIdx%2 == 0 {
+		return colorRed
+	}
+	return colorGreen
+}
+
+// AutoCalibrate detects the board in a camera image and computes the calibration from the detected reference points.
+// The calibration is rejected if the detection confidence is below the configured minimum.
+// The options are handed over to Calibrate (e.g. the lens distortion of the camera).
+func AutoCalibrate(cameraID int, img image.Image, config DetectionConfig, opts ...Option) (Calibration, BoardDetection, error) {
+	detection, err := DetectBoard(img, config)
+	if err != nil {
+		return Calibration{}, detection, err
+	}
+
+	var lens Calibration
+	for _, opt := range opts {
+		opt(&lens)
+	}
+	correspondences := rejectOutliers(detection.Correspondences, lens.undistort)
+	if len(correspondences) < len(detection.Correspondences)/2 {
+		return Calibration{}, detection, fmt.Errorf("AutoCalibrate() - error: too many inconsistent reference points: %w", ErrBoardNotFound)
+	}
+
+	bounds := img.Bounds()
+	calibration, err := Calibrate(cameraID, bounds.Dx(), bounds.Dy(), correspondences, opts...)
+	if err != nil {
+		return Calibration{}, detection, err
+	}
+
+	// the reprojection error is part of the confidence
+	detection.Confidence *= clamp01(1 - calibration.ReprojectionError/MaxReprojectionError)
+	if detection.Confidence < config.MinConfidence {
+		return Calibration{}, detection, fmt.Errorf("AutoCalibrate() - error: confidence %.2f below %.2f: %w", detection.Confidence, config.MinConfidence, ErrBoardNotFound)
+	}
+	return calibration, detection, nil
+}
+
+// DetectBoard finds the bull, the ellipses of the treble and double ring and the segment wires of a dartboard in a camera image.
+func DetectBoard(img image.Image, config DetectionConfig) (BoardDetection, error) {
+	defaults := DefaultDetectionConfig()
+	if config.WorkingWidth == 0 {
+		config.WorkingWidth = defaults.WorkingWidth
+	}
+	if config.TopSegment == 0 {
+		config.TopSegment = defaults.TopSegment
+	}
+	topSegmentIdx := segmentIndex(config.TopSegment)
+	if topSegmentIdx < 0 {
+		return BoardDetection{}, fmt.Errorf("DetectBoard() - error: unknown top segment %d", config.TopSegment)
+	}
+
+	// downscale to the working size
+	bounds := img.Bounds()
+	factor := 1
+	if bounds.Dx() > config.WorkingWidth {
+		factor = bounds.Dx() / config.WorkingWidth
+	}
+	classes := classifyColors(pixelconverter.Downscale(img, factor))
+
+	bull, err := findBull(classes)
+	if err != nil {
+		return BoardDetection{}, err
+	}
+
+	doubleEdge, trebleEdge := findRingEdges(classes, bull)
+	doubleRing, doubleInliers, doubleResidual, err := fitEllipseRobust(bull, doubleEdge, 0.05)
+	if err != nil {
+		return BoardDetection{}, fmt.Errorf("DetectBoard() - error: double ring: %v: %w", err, ErrBoardNotFound)
+	}
+	trebleRing, trebleInliers, trebleResidual, err := fitEllipseRobust(bull, trebleEdge, 0.05)
+	if err != nil {
+		return BoardDetection{}, fmt.Errorf("DetectBoard() - error: treble ring: %v: %w", err, ErrBoardNotFound)
+	}
+
+	boundaries, err := findWires(classes, bull, doubleRing, topSegmentIdx)
+	if err != nil {
+		return BoardDetection{}, err
+	}
+
+	// scale back to the original image
+	toImage := func(p Point) Point {
+		return Point{
+			X: float64(bounds.Min.X) + (p.X+0.5)*float64(factor) - 0.5,
+			Y: float64(bounds.Min.Y) + (p.Y+0.5)*float64(factor) - 0.5,
+		}
+	}
+
+	detection := BoardDetection{
+		Bull:       toImage(bull),
+		WireAngles: boundaries,
+	}
+	detection.Correspondences = append(detection.Correspondences, Correspondence{Name: "BULL", Image: detection.Bull})
+	for i, angle := range boundaries {
+		segment := segmentOrder[(topSegmentIdx+i)%len(segmentOrder)]
+		nextSegment := segmentOrder[(topSegmentIdx+i+1)%len(segmentOrder)]
+		detection.Correspondences = append(detection.Correspondences,
+			Correspondence{Name: fmt.Sprintf("%d/%d", segment, nextSegment), Image: toImage(doubleRing.RayIntersection(angle))},
+			Correspondence{Name: fmt.Sprintf("T%d/%d", segment, nextSegment), Image: toImage(trebleRing.RayIntersection(angle))},
+		)
+	}
+	detection.DoubleRing = scaleEllipse(doubleRing, detection.Bull, float64(factor))
+	detection.TrebleRing = scaleEllipse(trebleRing, detection.Bull, float64(factor))
+
+	// confidence: ring coverage and ellipse fit quality
+	coverage := clamp01(float64(min(len(doubleInliers), len(trebleInliers))) / (0.8 * rayCount))
+	fitQuality := clamp01(1-doubleResidual/0.04) * clamp01(1-trebleResidual/0.04)
+	detection.Confidence = coverage * fitQuality
+	return detection, nil
+}
+
+// classifyColors classifies each pixel as red, green (colors of the rings and the bull) or none.
+func classifyColors(img image.Image) [][]colorClass {
+	bounds := img.Bounds()
+	rgba, ok := img.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(bounds)
+		draw.Draw(rgba, bounds, img, bounds.Min, draw.Src)
+	}
+
+	classes := make([][]colorClass, bounds.Dy())
+	for y := 0; y < bounds.Dy(); y++ {
+		classes[y] = make([]colorClass, bounds.Dx())
+		for x := 0; x < bounds.Dx(); x++ {
+			c := rgba.RGBAAt(bounds.Min.X+x, bounds.Min.Y+y)
+			classes[y][x] = classifyColor(c)
+		}
+	}
+	return classes
+}
+
+func classifyColor(c color.RGBA) colorClass {
+	r, g, b := float64(c.R), float64(c.G), float64(c.B)
+	switch {
+	case r > 90 && r > 1.6*g && r > 1.6*b:
+		return colorRed
+	case g > 60 && g > 1.3*r && g > 1.15*b:
+		return colorGreen
+	default:
+		return colorNone
+	}
+}
+
+// findBull returns the center of the bull: the compact colored blob that is closest to the centroid of all colored pixels.
+func findBull(classes [][]colorClass) (Point, error) {
+	height := len(classes)
+	if height == 0 {
+		return Point{}, ErrBoardNotFound
+	}
+	width := len(classes[0])
+
+	var sumX, sumY, count float64
+	for y := range classes {
+		for x, c := range classes[y] {
+			if c != colorNone {
+				sumX += float64(x)
+				sumY += float64(y)
+				count++
+			}
+		}
+	}
+	if count < float64(width*height)/500 {
+		return Point{}, fmt.Errorf("findBull() - error: no colored rings found: %w", ErrBoardNotFound)
+	}
+	centroid := Point{X: sumX / count, Y: sumY / count}
+
+	// connected components of the colored pixels
+	visited := make([]bool, width*height)
+	best := Point{}
+	bestDistance := math.Inf(1)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if visited[y*width+x] || classes[y][x] == colorNone {
+				continue
+			}
+			blob := floodFill(classes, visited, x, y)
+			if blob.area < 5 {
+				continue
+			}
+			bboxWidth, bboxHeight := float64(blob.maxX-blob.minX+1), float64(blob.maxY-blob.minY+1)
+			aspect := bboxWidth / bboxHeight
+			fill := float64(blob.area) / (bboxWidth * bboxHeight)
+			if aspect < 0.4 || aspect > 2.5 || fill < 0.45 {
+				// --> not compact --> ring segment
+				continue
+			}
+			center := Point{X: blob.sumX / float64(blob.area), Y: blob.sumY / float64(blob.area)}
+			if d := center.Distance(centroid); d < bestDistance {
+				best, bestDistance = center, d
+			}
+		}
+	}
+	if math.IsInf(bestDistance, 1) {
+		return Point{}, fmt.Errorf("findBull() - error: no bull found: %w", ErrBoardNotFound)
+	}
+	return best, nil
+}
+
+type blob struct {
+	area                   int
+	sumX, sumY             float64
+	minX, minY, maxX, maxY int
+}
+
+// floodFill collects the connected colored pixels (4-neighbourhood) starting at x, y.
+func floodFill(classes [][]colorClass, visited []bool, startX, startY int) blob {
+	height, width := len(classes), len(classes[0])
+	b := blob{minX: startX, minY: startY, maxX: startX, maxY: startY}
+	stack := []image.Point{{X: startX, Y: startY}}
+	visited[startY*width+startX] = true
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		b.area++
+		b.sumX += float64(p.X)
+		b.sumY += float64(p.Y)
+		b.minX, b.maxX = min(b.minX, p.X), max(b.maxX, p.X)
+		b.minY, b.maxY = min(b.minY, p.Y), max(b.maxY, p.Y)
+
+		for _, n := range []image.Point{{X: p.X + 1, Y: p.Y}, {X: p.X - 1, Y: p.Y}, {X: p.X, Y: p.Y + 1}, {X: p.X, Y: p.Y - 1}} {
+			if n.X < 0 || n.Y < 0 || n.X >= width || n.Y >= height || visited[n.Y*width+n.X] || classes[n.Y][n.X] == colorNone {
+				continue
+			}
+			visited[n.Y*width+n.X] = true
+			stack = append(stack, n)
+		}
+	}
+	return b
+}
+
+// rayCount is the amount of rays that are cast from the bull to find the ring edges
+const rayCount = 360
+
+// findRingEdges casts rays from the bull and returns the outer edge points of the double and the treble ring.
+func findRingEdges(classes [][]colorClass, bull Point) (doubleEdge, trebleEdge []Point) {
+	height, width := len(classes), len(classes[0])
+	maxRadius := math.Hypot(float64(width), float64(height))
+
+	type run struct{ start, end float64 }
+	for i := 0; i < rayCount; i++ {
+		angle := 2 * math.Pi * float64(i) / rayCount
+		dx, dy := math.Cos(angle), math.Sin(angle)
+
+		// collect the colored runs along the ray
+		var runs []run
+		inRun := false
+		gap := 0
+		for t := 0.0; t < maxRadius; t += 0.5 {
+			x, y := int(math.Round(bull.X+t*dx)), int(math.Round(bull.Y+t*dy))
+			if x < 0 || y < 0 || x >= width || y >= height {
+				break
+			}
+			if classes[y][x] != colorNone {
+				if !inRun {
+					runs = append(runs, run{start: t})
+					inRun = true
+				}
+				runs[len(runs)-1].end = t
+				gap = 0
+			} else if inRun {
+				// tolerate single missing samples (noise)
+				gap++
+				if gap > 2 {
+					inRun = false
+				}
+			}
+		}
+
+		// the outermost pair of runs with the radius ratio of treble and double ring
+		for j := len(runs) - 1; j > 0; j-- {
+			double := runs[j]
+			if double.end-double.start < 1 {
+				continue
+			}
+			found := false
+			for k := j - 1; k >= 0; k-- {
+				ratio := runs[k].end / double.end
+				if ratio >= trebleToDoubleRatio-0.12 && ratio <= trebleToDoubleRatio+0.12 && runs[k].end-runs[k].start >= 1 {
+					doubleEdge = append(doubleEdge, Point{X: bull.X + double.end*dx, Y: bull.Y + double.end*dy})
+					trebleEdge = append(trebleEdge, Point{X: bull.X + runs[k].end*dx, Y: bull.Y + runs[k].end*dy})
+					found = true
+					break
+				}
+			}
+			if found {
+				break
+			}
+		}
+	}
+	return doubleEdge, trebleEdge
+}
+
+// findWires samples the colors in the middle of the double ring and returns the angles of the 20 color changes (the wires),
+// starting with the wire clockwise of the top segment.
+func findWires(classes [][]colorClass, bull Point, doubleRing Ellipse, topSegmentIdx int) ([]float64, error) {
+	height, width := len(classes), len(classes[0])
+	const samples = 1440
+
+	labels := make([]colorClass, samples)
+	for i := range labels {
+		angle := 2 * math.Pi * float64(i) / samples
+		edge := doubleRing.RayIntersection(angle)
+		x := int(math.Round(bull.X + (edge.X-bull.X)*doubleRingMidRatio))
+		y := int(math.Round(bull.Y + (edge.Y-bull.Y)*doubleRingMidRatio))
+		if x >= 0 && y >= 0 && x < width && y < height {
+			labels[i] = classes[y][x]
+		}
+	}
+
+	// find the first colored sample as start
+	start := -1
+	for i, l := range labels {
+		if l != colorNone {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("findWires() - error: double ring not found: %w", ErrBoardNotFound)
+	}
+
+	// color changes between red and green (the wire itself may be uncolored)
+	var boundaries []float64
+	lastLabel, lastIdx := labels[start], start
+	for n := 1; n <= samples; n++ {
+		i := (start + n) % samples
+		if labels[i] == colorNone {
+			continue
+		}
+		if labels[i] != lastLabel {
+			mid := float64(lastIdx) + float64((i-lastIdx+samples)%samples)/2
+			boundaries = append(boundaries, normalizeAngle(2*math.Pi*mid/samples))
+			lastLabel = labels[i]
+		}
+		lastIdx = i
+	}
+	if len(boundaries) != len(segmentOrder) {
+		return nil, fmt.Errorf("findWires() - error: %d segment wires found, expected %d: %w", len(boundaries), len(segmentOrder), ErrBoardNotFound)
+	}
+	sortAngles(boundaries)
+
+	// the top segment has the expected color and is the segment closest to the image top (angle -90°)
+	topColor := segmentColor(topSegmentIdx)
+	bestSegment, bestDistance := -1, math.Inf(1)
+	for i := range boundaries {
+		from, to := boundaries[i], boundaries[(i+1)%len(boundaries)]
+		mid := normalizeAngle(from + normalizeAngle(to-from)/2)
+		edge := doubleRing.RayIntersection(mid)
+		x := int(math.Round(bull.X + (edge.X-bull.X)*doubleRingMidRatio))
+		y := int(math.Round(bull.Y + (edge.Y-bull.Y)*doubleRingMidRatio))
+		if x < 0 || y < 0 || x >= width || y >= height || classes[y][x] != topColor {
+			continue
+		}
+		if d := angleDistance(mid, -math.Pi/2); d < bestDistance {
+			bestSegment, bestDistance = i, d
+		}
+	}
+	if bestSegment < 0 {
+		return nil, fmt.Errorf("findWires() - error: top segment not found: %w", ErrBoardNotFound)
+	}
+
+	// the image angle grows clockwise --> the wire after the top segment is its upper boundary
+	wires := make([]float64, len(boundaries))
+	for i := range wires {
+		wires[i] = boundaries[(bestSegment+1+i)%len(boundaries)]
+	}
+	return wires, nil
+}
+
+// rejectOutliers removes the reference points that do not fit the homography of all other points.
+// The image points are undistorted with the hand-overed function before the homography is computed.
+func rejectOutliers(correspondences []Correspondence, undistort func(Point) Point) []Correspondence {
+	for len(correspondences) > 4 {
+		var imagePoints, boardPoints []Point
+		for _, c := range correspondences {
+			boardPoint, err := BoardPoint(c.Name)
+			if err != nil {
+				return nil
+			}
+			imagePoints = append(imagePoints, undistort(c.Image))
+			boardPoints = append(boardPoints, boardPoint)
+		}
+		h, err := ComputeHomography(imagePoints, boardPoints)
+		if err != nil {
+			return nil
+		}
+
+		worst, worstError := -1, 0.0
+		for i := range correspondences {
+			if e := h.Apply(imagePoints[i]).Distance(boardPoints[i]); e > worstError {
+				worst, worstError = i, e
+			}
+		}
+		if worstError <= MaxReprojectionError {
+			return correspondences
+		}
+		correspondences = append(correspondences[:worst:worst], correspondences[worst+1:]...)
+	}
+	return correspondences
+}
+
+// scaleEllipse converts an ellipse of the downscaled image into the original image.
+func scaleEllipse(e Ellipse, origin Point, factor float64) Ellipse {
+	return Ellipse{
+		Origin: origin,
+		A:      e.A / (factor * factor),
+		B:      e.B / (factor * factor),
+		C:      e.C / (factor * factor),
+		D:      e.D / factor,
+		E:      e.E / factor,
+	}
+}
+
+// normalizeAngle maps an angle into [0, 2π).
+func normalizeAngle(angle float64) float64 {
+	angle = math.Mod(angle, 2*math.Pi)
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	return angle
+}
+
+// angleDistance returns the absolute difference of two angles in [0, π].
+func angleDistance(a, b float64) float64 {
+	d := normalizeAngle(a - b)
+	if d > math.Pi {
+		d = 2*math.Pi - d
+	}
+	return d
+}
+
+func sortAngles(angles []float64) {
+	for i := 1; i < len(angles); i++ {
+		for j := i; j > 0 && angles[j] < angles[j-1]; j-- {
+			angles[j], angles[j-1] = angles[j-1], angles[j]
+		}
+	}
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,163 @@
+package calibration
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MaxReprojectionError is the maximum allowed reprojection error (in mm) of a single reference point.
+const MaxReprojectionError = 3.0
+
+var ErrReprojectionError = errors.New("reprojection error too large")
+
+// Correspondence is a named reference point marked in a camera image.
+type Correspondence struct {
+	Name  string  `json:"name"`
+	Image Point   `json:"image"`
+	Board Point   `json:"board"`
+	Error float64 `json:"error"` // reprojection error in mm
+}
+
+// Calibration maps the pixels of a camera image to board coordinates (in mm, origin at the bull, y pointing up).
+// The image points refer to the frames as they are published by the camera (after the region of interest was applied).
+// If the lens distortion of the camera is known, the image points are undistorted before the homography is applied.
+type Calibration struct {
+	CameraID          int              `json:"cameraId"`
+	ImageWidth        int              `json:"imageWidth"`
+	ImageHeight       int              `json:"imageHeight"`
+	Correspondences   []Correspondence `json:"correspondences"`
+	Homography        Homography       `json:"homography"`
+	ReprojectionError float64          `json:"reprojectionError"` // root mean square error in mm
+	Intrinsics        *Intrinsics      `json:"intrinsics,omitempty"`
+	ImageOffset       Point            `json:"imageOffset"` // top left corner of the image inside the full camera frame (region of interest)
+	CreatedAt         time.Time        `json:"createdAt"`
+	inverse           Homography
+	hasInverse        bool
+}
+
+type Option func(*Calibration)
+
+// WithIntrinsics undistorts the image points with the lens distortion of the camera.
+// The offset is the top left corner of the calibrated image inside the full camera frame (i.e. the origin of the region of interest).
+func WithIntrinsics(intrinsics Intrinsics, offset Point) Option {
+	return func(c *Calibration) {
+		c.Intrinsics = &intrinsics
+		c.ImageOffset = offset
+	}
+}
+
+// Calibrate computes the calibration of a camera from marked reference points.
+// The board coordinates of the correspondences are derived from their names (see BoardPoint) if the name is set.
+// The calibration is rejected if a single point has a reprojection error larger than MaxReprojectionError
+// or if the marked points are mirrored (e.g. mixed up reference points).
+func Calibrate(cameraID int, imageWidth, imageHeight int, correspondences []Correspondence, opts ...Option) (Calibration, error) {
+	if len(correspondences) < 4 {
+		return Calibration{}, fmt.Errorf("Calibrate() - error: at least 4 reference points required, got %d", len(correspondences))
+	}
+
+	calibration := Calibration{
+		CameraID:    cameraID,
+		ImageWidth:  imageWidth,
+		ImageHeight: imageHeight,
+		CreatedAt:   time.Now(),
+	}
+	for _, opt := range opts {
+		opt(&calibration)
+	}
+
+	var imagePoints, boardPoints []Point
+	for i, c := range correspondences {
+		if c.Name != "" {
+			boardPoint, err := BoardPoint(c.Name)
+			if err != nil {
+				return Calibration{}, fmt.Errorf("Calibrate() - error: %v", err)
+			}
+			correspondences[i].Board = boardPoint
+		}
+		imagePoints = append(imagePoints, calibration.undistort(c.Image))
+		boardPoints = append(boardPoints, correspondences[i].Board)
+	}
+
+	h, err := ComputeHomography(imagePoints, boardPoints)
+	if err != nil {
+		return Calibration{}, fmt.Errorf("Calibrate() - error: %v", err)
+	}
+	calibration.Correspondences = correspondences
+	calibration.Homography = h
+	if err := calibration.Validate(); err != nil {
+		return Calibration{}, err
+	}
+	return calibration, nil
+}
+
+// Validate computes the reprojection errors of the reference points and checks the calibration for plausibility.
+func (c *Calibration) Validate() error {
+	if in := c.Intrinsics; in != nil {
+		if c.ImageOffset.X < 0 || c.ImageOffset.Y < 0 ||
+			c.ImageOffset.X+float64(c.ImageWidth) > float64(in.ImageWidth) || c.ImageOffset.Y+float64(c.ImageHeight) > float64(in.ImageHeight) {
+			return fmt.Errorf("Validate() - error: image %dx%d at %v exceeds the frame %dx%d of the lens calibration", c.ImageWidth, c.ImageHeight, c.ImageOffset, in.ImageWidth, in.ImageHeight)
+		}
+	}
+
+	inverse, err := c.Homography.Inverse()
+	if err != nil {
+		return fmt.Errorf("Validate() - error: %v", err)
+	}
+	c.inverse = inverse
+	c.hasInverse = true
+
+	// the image y-axis points down while the board y-axis points up --> a valid transform mirrors the orientation
+	if c.Homography.JacobianDeterminant(inverse.Apply(Point{})) >= 0 {
+		return fmt.Errorf("Validate() - error: the reference points are mirrored, check the order of the marked points")
+	}
+
+	var sumSquares float64
+	for i, corr := range c.Correspondences {
+		reprojectionError := c.ImageToBoard(corr.Image).Distance(corr.Board)
+		c.Correspondences[i].Error = reprojectionError
+		sumSquares += reprojectionError * reprojectionError
+		if reprojectionError > MaxReprojectionError {
+			return fmt.Errorf("Validate() - error: reference point %q: %.1f mm: %w", corr.Name, reprojectionError, ErrReprojectionError)
+		}
+	}
+	c.ReprojectionError = math.Sqrt(sumSquares / float64(len(c.Correspondences)))
+	return nil
+}
+
+// ImageToBoard maps an image point (pixels) to the board (mm).
+func (c *Calibration) ImageToBoard(p Point) Point {
+	return c.Homography.Apply(c.undistort(p))
+}
+
+// BoardToImage maps a board point (mm) to the image (pixels).
+func (c *Calibration) BoardToImage(p Point) Point {
+	if !c.hasInverse {
+		inverse, err := c.Homography.Inverse()
+		if err != nil {
+			return Point{X: math.NaN(), Y: math.NaN()}
+		}
+		c.inverse = inverse
+		c.hasInverse = true
+	}
+	return c.distort(c.inverse.Apply(p))
+}
+
+// undistort removes the lens distortion of an image point.
+func (c *Calibration) undistort(p Point) Point {
+	if c.Intrinsics == nil {
+		return p
+	}
+	u := c.Intrinsics.Undistort(Point{X: p.X + c.ImageOffset.X, Y: p.Y + c.ImageOffset.Y})
+	return Point{X: u.X - c.ImageOffset.X, Y: u.Y - c.ImageOffset.Y}
+}
+
+// distort applies the lens distortion to an ideal image point.
+func (c *Calibration) distort(p Point) Point {
+	if c.Intrinsics == nil {
+		return p
+	}
+	d := c.Intrinsics.Distort(Point{X: p.X + c.ImageOffset.X, Y: p.Y + c.ImageOffset.Y})
+	return Point{X: d.X - c.ImageOffset.X, Y: d.Y - c.ImageOffset.Y}
+}
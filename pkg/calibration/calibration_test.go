@@ -0,0 +1,72 @@
+package calibration
+
+import (
+	"math"
+	"testing"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+// cameraView maps the physical position of a point on the hung board (mm, y up) to a pixel of a camera image.
+type cameraView func(p Point) Point
+
+// perspectiveView looks at the board from below, so the upper half of the board appears smaller.
+func perspectiveView(p Point) Point {
+	w := 1 + 0.0008*p.Y
+	return Point{X: 640 + 1.6*p.X/w, Y: 360 - 1.2*p.Y/w}
+}
+
+// hung returns the physical position of a board coordinate on a board hung with the hand-overed clockwise rotation.
+func hung(p Point, rotation float64) Point {
+	r := rotation * math.Pi / 180
+	return Point{X: p.X*math.Cos(r) + p.Y*math.Sin(r), Y: -p.X*math.Sin(r) + p.Y*math.Cos(r)}
+}
+
+func TestCalibrateRotatedBoard(t *testing.T) {
+	references := append([]string{"BULL", "T5/20", "T17/3"}, DefaultReferencePoints...)
+	for _, rotation := range []float64{0, 18, 90, -36, 171} {
+		board, err := dartboard.NewBoard(dartboard.WithRotation(rotation))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the operator marks the wires where they are on the hung board
+		var correspondences []Correspondence
+		for _, name := range references {
+			p, err := BoardPoint(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			correspondences = append(correspondences, Correspondence{Name: name, Image: perspectiveView(hung(p, rotation))})
+		}
+		c, err := Calibrate(1, 1280, 720, correspondences)
+		if err != nil {
+			t.Fatalf("rotation %v: %v", rotation, err)
+		}
+
+		for _, label := range []string{"T20", "D1", "S6", "T19", "D16", "25", "BULL"} {
+			x, y, err := board.Target(label)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// a dart in the target of the hung board must score the target (the rotation must not be applied twice)
+			p := c.ImageToBoard(perspectiveView(hung(Point{X: x, Y: y}, rotation)))
+			if s := board.Score(p.X, p.Y); s.Label != label {
+				t.Errorf("rotation %v: dart at %s scored %s", rotation, label, s.Label)
+			}
+		}
+	}
+}
+
+func TestDetectionConfigFor(t *testing.T) {
+	board, err := dartboard.NewBoard(dartboard.WithRotation(-18))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config := DetectionConfigFor(board); config.TopSegment != 1 {
+		t.Errorf("TopSegment = %d, want 1", config.TopSegment)
+	}
+	if config := DetectionConfigFor(dartboard.Standard()); config.TopSegment != DefaultDetectionConfig().TopSegment {
+		t.Errorf("TopSegment = %d, want %d", config.TopSegment, DefaultDetectionConfig().TopSegment)
+	}
+}
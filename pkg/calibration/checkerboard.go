@@ -0,0 +1,320 @@
+package calibration
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+var ErrCheckerboardNotFound = errors.New("checkerboard not found")
+
+// checkerboardRadius is the radius (in pixels of the working image) of the ring that is sampled around a corner candidate.
+const checkerboardRadius = 5
+
+// checkerboardWorkingWidth is the width the snapshot is scaled down to for the corner search.
+const checkerboardWorkingWidth = 800
+
+// ringOffsets are the 16 sample positions of the corner response (clockwise, starting at the top).
+var ringOffsets = func() [16]image.Point {
+	var offsets [16]image.Point
+	for i := range offsets {
+		angle := float64(i) * 2 * math.Pi / 16
+		offsets[i] = image.Point{
+			X: int(math.Round(checkerboardRadius * math.Sin(angle))),
+			Y: int(math.Round(-checkerboardRadius * math.Cos(angle))),
+		}
+	}
+	return offsets
+}()
+
+type grayImage struct {
+	width, height int
+	pix           []float64
+}
+
+func (g *grayImage) at(x, y int) float64 {
+	return g.pix[y*g.width+x]
+}
+
+// FindCheckerboard finds the inner corners of a checkerboard with columns x rows inner corners in a snapshot.
+// The corners are returned row-major with sub-pixel accuracy. The whole checkerboard has to be visible.
+func FindCheckerboard(img image.Image, columns, rows int) (Checkerboard, error) {
+	if columns < 3 || rows < 3 {
+		return Checkerboard{}, fmt.Errorf("FindCheckerboard() - error: at least 3x3 inner corners required, got %dx%d", columns, rows)
+	}
+
+	full := toGray(img, 1)
+	scale := 1
+	for full.width/scale > checkerboardWorkingWidth {
+		scale++
+	}
+	working := toGray(img, scale)
+
+	candidates := cornerCandidates(working)
+	if len(candidates) < columns*rows {
+		return Checkerboard{}, fmt.Errorf("FindCheckerboard() - error: %d corner candidates for %d corners: %w", len(candidates), columns*rows, ErrCheckerboardNotFound)
+	}
+
+	grid, gridColumns, gridRows := buildGrid(candidates)
+	switch {
+	case gridColumns == columns && gridRows == rows:
+	case gridColumns == rows && gridRows == columns:
+		grid, gridColumns, gridRows = transposeGrid(grid, gridColumns, gridRows), gridRows, gridColumns
+	default:
+		return Checkerboard{}, fmt.Errorf("FindCheckerboard() - error: found a %dx%d grid instead of %dx%d: %w", gridColumns, gridRows, columns, rows, ErrCheckerboardNotFound)
+	}
+
+	corners := make([]Point, len(grid))
+	for i, p := range grid {
+		// --> back to the full resolution
+		corners[i] = refineCorner(full, Point{X: p.X*float64(scale) + float64(scale-1)/2, Y: p.Y*float64(scale) + float64(scale-1)/2}, checkerboardRadius*scale)
+	}
+	return Checkerboard{Columns: columns, Rows: rows, Corners: corners}, nil
+}
+
+// toGray converts an image into a gray image downscaled by the hand-overed factor (box filter).
+func toGray(img image.Image, scale int) *grayImage {
+	bounds := img.Bounds()
+	g := &grayImage{width: bounds.Dx() / scale, height: bounds.Dy() / scale}
+	g.pix = make([]float64, g.width*g.height)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			var sum float64
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					c := color.GrayModel.Convert(img.At(bounds.Min.X+x*scale+dx, bounds.Min.Y+y*scale+dy)).(color.Gray)
+					sum += float64(c.Y)
+				}
+			}
+			g.pix[y*g.width+x] = sum / float64(scale*scale)
+		}
+	}
+	return g
+}
+
+// cornerCandidates returns the local maxima of a saddle point response: around a checkerboard corner the
+// opposite quadrants of the sampled ring are equal while the neighbouring quadrants differ.
+func cornerCandidates(g *grayImage) []Point {
+	response := make([]float64, len(g.pix))
+	var maxResponse float64
+	for y := checkerboardRadius; y < g.height-checkerboardRadius; y++ {
+		for x := checkerboardRadius; x < g.width-checkerboardRadius; x++ {
+			var ring [16]float64
+			var ringSum float64
+			for i, o := range ringOffsets {
+				ring[i] = g.at(x+o.X, y+o.Y)
+				ringSum += ring[i]
+			}
+			var sumResponse, diffResponse float64
+			for i := 0; i < 4; i++ {
+				sumResponse += math.Abs(ring[i] + ring[i+8] - ring[i+4] - ring[i+12])
+			}
+			for i := 0; i < 8; i++ {
+				diffResponse += math.Abs(ring[i] - ring[i+8])
+			}
+			local := (g.at(x, y) + g.at(x-1, y) + g.at(x+1, y) + g.at(x, y-1) + g.at(x, y+1)) / 5
+			meanResponse := math.Abs(ringSum/16 - local)
+			r := sumResponse - diffResponse - 16*meanResponse
+			response[y*g.width+x] = r
+			maxResponse = math.Max(maxResponse, r)
+		}
+	}
+	if maxResponse <= 0 {
+		return nil
+	}
+
+	// non-maximum suppression
+	const suppression = checkerboardRadius
+	threshold := 0.15 * maxResponse
+	var candidates []Point
+	for y := checkerboardRadius; y < g.height-checkerboardRadius; y++ {
+		for x := checkerboardRadius; x < g.width-checkerboardRadius; x++ {
+			r := response[y*g.width+x]
+			if r < threshold {
+				continue
+			}
+			isMax := true
+			for dy := -suppression; dy <= suppression && isMax; dy++ {
+				for dx := -suppression; dx <= suppression; dx++ {
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= g.width || ny >= g.height || (dx == 0 && dy == 0) {
+						continue
+					}
+					n := response[ny*g.width+nx]
+					if n > r || (n == r && (dy < 0 || (dy == 0 && dx < 0))) {
+						isMax = false
+						break
+					}
+				}
+			}
+			if isMax {
+				candidates = append(candidates, Point{X: float64(x), Y: float64(y)})
+			}
+		}
+	}
+	return candidates
+}
+
+// buildGrid arranges the corner candidates into a grid, starting at the candidate closest to their center
+// and following the local grid directions. The grid is returned row-major together with its size.
+func buildGrid(candidates []Point) ([]Point, int, int) {
+	var center Point
+	for _, c := range candidates {
+		center.X += c.X / float64(len(candidates))
+		center.Y += c.Y / float64(len(candidates))
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Distance(center) < candidates[j].Distance(center)
+	})
+	start := candidates[0]
+
+	// the grid directions are the closest neighbour and the closest neighbour roughly perpendicular to it
+	neighbours := append([]Point(nil), candidates[1:]...)
+	sort.Slice(neighbours, func(i, j int) bool {
+		return neighbours[i].Distance(start) < neighbours[j].Distance(start)
+	})
+	if len(neighbours) < 2 {
+		return nil, 0, 0
+	}
+	u := Point{X: neighbours[0].X - start.X, Y: neighbours[0].Y - start.Y}
+	var v Point
+	for _, n := range neighbours[1:] {
+		d := Point{X: n.X - start.X, Y: n.Y - start.Y}
+		cos := (u.X*d.X + u.Y*d.Y) / (math.Hypot(u.X, u.Y) * math.Hypot(d.X, d.Y))
+		if math.Abs(cos) < 0.5 {
+			v = d
+			break
+		}
+	}
+	if v == (Point{}) {
+		return nil, 0, 0
+	}
+	// --> u points along the image x-axis, v along the image y-axis
+	if math.Abs(u.Y) > math.Abs(u.X) {
+		u, v = v, u
+	}
+	if u.X < 0 {
+		u = Point{X: -u.X, Y: -u.Y}
+	}
+	if v.Y < 0 {
+		v = Point{X: -v.X, Y: -v.Y}
+	}
+
+	type node struct{ col, row int }
+	type step struct{ u, v Point }
+	positions := map[node]Point{{0, 0}: start}
+	steps := map[node]step{{0, 0}: {u, v}}
+	used := map[Point]bool{start: true}
+	queue := []node{{0, 0}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		p, s := positions[n], steps[n]
+		for _, dir := range []struct {
+			dc, dr int
+			offset Point
+		}{
+			{1, 0, s.u}, {-1, 0, Point{X: -s.u.X, Y: -s.u.Y}},
+			{0, 1, s.v}, {0, -1, Point{X: -s.v.X, Y: -s.v.Y}},
+		} {
+			next := node{n.col + dir.dc, n.row + dir.dr}
+			if _, ok := positions[next]; ok {
+				continue
+			}
+			predicted := Point{X: p.X + dir.offset.X, Y: p.Y + dir.offset.Y}
+			tolerance := 0.35 * math.Hypot(dir.offset.X, dir.offset.Y)
+			best, bestDistance := Point{}, math.Inf(1)
+			for _, c := range candidates {
+				if d := c.Distance(predicted); d < bestDistance && !used[c] {
+					best, bestDistance = c, d
+				}
+			}
+			if bestDistance > tolerance {
+				continue
+			}
+			used[best] = true
+			positions[next] = best
+
+			// the step is updated with the observed distance, so the grid follows the perspective and the lens distortion
+			observed := Point{X: best.X - p.X, Y: best.Y - p.Y}
+			nextStep := s
+			if dir.dc != 0 {
+				nextStep.u = Point{X: observed.X * float64(dir.dc), Y: observed.Y * float64(dir.dc)}
+			} else {
+				nextStep.v = Point{X: observed.X * float64(dir.dr), Y: observed.Y * float64(dir.dr)}
+			}
+			steps[next] = nextStep
+			queue = append(queue, next)
+		}
+	}
+
+	minCol, maxCol, minRow, maxRow := 0, 0, 0, 0
+	for n := range positions {
+		minCol, maxCol = min(minCol, n.col), max(maxCol, n.col)
+		minRow, maxRow = min(minRow, n.row), max(maxRow, n.row)
+	}
+	columns, rows := maxCol-minCol+1, maxRow-minRow+1
+	if len(positions) != columns*rows {
+		// --> holes inside the grid
+		return nil, 0, 0
+	}
+	grid := make([]Point, 0, columns*rows)
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			grid = append(grid, positions[node{col, row}])
+		}
+	}
+	return grid, columns, rows
+}
+
+// transposeGrid swaps the columns and rows of a row-major grid.
+func transposeGrid(grid []Point, columns, rows int) []Point {
+	transposed := make([]Point, len(grid))
+	for row := 0; row < rows; row++ {
+		for col := 0; col < columns; col++ {
+			transposed[col*rows+row] = grid[row*columns+col]
+		}
+	}
+	return transposed
+}
+
+// refineCorner moves a corner to the point where the image gradients of its neighbourhood are orthogonal
+// to the direction towards the corner (sub-pixel saddle point).
+func refineCorner(g *grayImage, p Point, radius int) Point {
+	for it := 0; it < 10; it++ {
+		var a, b, c, bx, by float64
+		cx, cy := int(math.Round(p.X)), int(math.Round(p.Y))
+		for y := cy - radius; y <= cy+radius; y++ {
+			for x := cx - radius; x <= cx+radius; x++ {
+				if x < 1 || y < 1 || x >= g.width-1 || y >= g.height-1 {
+					continue
+				}
+				gx := (g.at(x+1, y) - g.at(x-1, y)) / 2
+				gy := (g.at(x, y+1) - g.at(x, y-1)) / 2
+				a += gx * gx
+				b += gx * gy
+				c += gy * gy
+				bx += gx*gx*float64(x) + gx*gy*float64(y)
+				by += gx*gy*float64(x) + gy*gy*float64(y)
+			}
+		}
+		det := a*c - b*b
+		if math.Abs(det) < 1e-9 {
+			return p
+		}
+		refined := Point{X: (c*bx - b*by) / det, Y: (a*by - b*bx) / det}
+		if refined.Distance(p) > float64(radius) {
+			// --> diverged, keep the coarse position
+			return p
+		}
+		converged := refined.Distance(p) < 0.01
+		p = refined
+		if converged {
+			break
+		}
+	}
+	return p
+}
@@ -0,0 +1,93 @@
+package calibration
+
+import (
+	"fmt"
+	"math"
+)
+
+// Ellipse is a conic A*x² + B*xy + C*y² + D*x + E*y = 1 relative to an origin inside the ellipse.
+type Ellipse struct {
+	Origin Point   `json:"origin"`
+	A      float64 `json:"a"`
+	B      float64 `json:"b"`
+	C      float64 `json:"c"`
+	D      float64 `json:"d"`
+	E      float64 `json:"e"`
+}
+
+// FitEllipse fits an ellipse to the hand-overed points in the least squares sense.
+// The origin has to lie inside the ellipse (e.g. the bull of the board).
+func FitEllipse(origin Point, points []Point) (Ellipse, error) {
+	if len(points) < 5 {
+		return Ellipse{}, fmt.Errorf("fit ellipse: at least 5 points required, got %d", len(points))
+	}
+	a := make([][]float64, len(points))
+	b := make([]float64, len(points))
+	for i, p := range points {
+		x, y := p.X-origin.X, p.Y-origin.Y
+		a[i] = []float64{x * x, x * y, y * y, x, y}
+		b[i] = 1
+	}
+	x, err := solveLeastSquares(a, b)
+	if err != nil {
+		return Ellipse{}, fmt.Errorf("fit ellipse: %w", ErrDegeneratePoints)
+	}
+	e := Ellipse{Origin: origin, A: x[0], B: x[1], C: x[2], D: x[3], E: x[4]}
+	if 4*e.A*e.C-e.B*e.B <= 0 {
+		return Ellipse{}, fmt.Errorf("fit ellipse: points do not form an ellipse")
+	}
+	return e, nil
+}
+
+// RayIntersection returns the point where the ray from the origin in direction angle (radians, image coordinates) leaves the ellipse.
+func (e Ellipse) RayIntersection(angle float64) Point {
+	c, s := math.Cos(angle), math.Sin(angle)
+	qa := e.A*c*c + e.B*c*s + e.C*s*s
+	qb := e.D*c + e.E*s
+	// qa*t² + qb*t - 1 = 0 --> positive root
+	t := (-qb + math.Sqrt(qb*qb+4*qa)) / (2 * qa)
+	return Point{X: e.Origin.X + t*c, Y: e.Origin.Y + t*s}
+}
+
+// Residual returns the relative radial distance of a point to the ellipse (0 = on the ellipse).
+func (e Ellipse) Residual(p Point) float64 {
+	dx, dy := p.X-e.Origin.X, p.Y-e.Origin.Y
+	onEllipse := e.RayIntersection(math.Atan2(dy, dx))
+	r := math.Hypot(dx, dy)
+	rEllipse := onEllipse.Distance(e.Origin)
+	if rEllipse == 0 {
+		return math.Inf(1)
+	}
+	return math.Abs(r-rEllipse) / rEllipse
+}
+
+// fitEllipseRobust fits an ellipse and refits it without the points whose residual is larger than maxResidual.
+// The fitted ellipse, the used points and the rms residual are returned.
+func fitEllipseRobust(origin Point, points []Point, maxResidual float64) (Ellipse, []Point, float64, error) {
+	e, err := FitEllipse(origin, points)
+	if err != nil {
+		return Ellipse{}, nil, 0, err
+	}
+	for iteration := 0; iteration < 3; iteration++ {
+		var inliers []Point
+		for _, p := range points {
+			if e.Residual(p) <= maxResidual {
+				inliers = append(inliers, p)
+			}
+		}
+		if len(inliers) == len(points) {
+			break
+		}
+		points = inliers
+		if e, err = FitEllipse(origin, points); err != nil {
+			return Ellipse{}, nil, 0, err
+		}
+	}
+
+	var sumSquares float64
+	for _, p := range points {
+		residual := e.Residual(p)
+		sumSquares += residual * residual
+	}
+	return e, points, math.Sqrt(sumSquares / float64(len(points))), nil
+}
@@ -0,0 +1,152 @@
+package calibration
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Point is a 2d point, either in image pixels or in board millimetres.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Distance returns the euclidean distance between two points.
+func (p Point) Distance(q Point) float64 {
+	return math.Hypot(p.X-q.X, p.Y-q.Y)
+}
+
+// Homography is a 3x3 perspective transform in row-major order.
+type Homography [9]float64
+
+var ErrDegeneratePoints = errors.New("degenerate point configuration")
+
+// Apply transforms a point with the homography.
+func (h Homography) Apply(p Point) Point {
+	w := h[6]*p.X + h[7]*p.Y + h[8]
+	return Point{
+		X: (h[0]*p.X + h[1]*p.Y + h[2]) / w,
+		Y: (h[3]*p.X + h[4]*p.Y + h[5]) / w,
+	}
+}
+
+// Inverse returns the inverse transform of the homography.
+func (h Homography) Inverse() (Homography, error) {
+	det := h[0]*(h[4]*h[8]-h[5]*h[7]) - h[1]*(h[3]*h[8]-h[5]*h[6]) + h[2]*(h[3]*h[7]-h[4]*h[6])
+	if math.Abs(det) < 1e-15 {
+		return Homography{}, ErrSingularMatrix
+	}
+	inv := Homography{
+		h[4]*h[8] - h[5]*h[7], h[2]*h[7] - h[1]*h[8], h[1]*h[5] - h[2]*h[4],
+		h[5]*h[6] - h[3]*h[8], h[0]*h[8] - h[2]*h[6], h[2]*h[3] - h[0]*h[5],
+		h[3]*h[7] - h[4]*h[6], h[1]*h[6] - h[0]*h[7], h[0]*h[4] - h[1]*h[3],
+	}
+	for i := range inv {
+		inv[i] /= det
+	}
+	return inv.normalize(), nil
+}
+
+// Multiply returns the concatenated transform h * o (o is applied first).
+func (h Homography) Multiply(o Homography) Homography {
+	var r Homography
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			for k := 0; k < 3; k++ {
+				r[row*3+col] += h[row*3+k] * o[k*3+col]
+			}
+		}
+	}
+	return r.normalize()
+}
+
+// JacobianDeterminant returns the determinant of the local linearization of the transform at a point.
+// A negative determinant means that the transform mirrors the orientation at that point.
+func (h Homography) JacobianDeterminant(p Point) float64 {
+	const eps = 1e-3
+	origin := h.Apply(p)
+	dx := h.Apply(Point{X: p.X + eps, Y: p.Y})
+	dy := h.Apply(Point{X: p.X, Y: p.Y + eps})
+	return ((dx.X-origin.X)*(dy.Y-origin.Y) - (dx.Y-origin.Y)*(dy.X-origin.X)) / (eps * eps)
+}
+
+// normalize scales the homography so that the last element is 1.
+func (h Homography) normalize() Homography {
+	if h[8] == 0 {
+		return h
+	}
+	scale := h[8]
+	for i := range h {
+		h[i] /= scale
+	}
+	return h
+}
+
+// ComputeHomography computes the perspective transform that maps the src points onto the dst points (direct linear transform).
+// At least 4 point pairs are required, additional pairs are fitted in the least squares sense.
+func ComputeHomography(src, dst []Point) (Homography, error) {
+	if len(src) != len(dst) {
+		return Homography{}, fmt.Errorf("compute homography: %d source points but %d destination points", len(src), len(dst))
+	}
+	if len(src) < 4 {
+		return Homography{}, fmt.Errorf("compute homography: at least 4 point pairs required, got %d", len(src))
+	}
+
+	// normalize the points for numerical stability
+	srcNorm, srcPoints := normalizePoints(src)
+	dstNorm, dstPoints := normalizePoints(dst)
+
+	a := make([][]float64, 0, 2*len(src))
+	b := make([]float64, 0, 2*len(src))
+	for i := range srcPoints {
+		x, y := srcPoints[i].X, srcPoints[i].Y
+		u, v := dstPoints[i].X, dstPoints[i].Y
+		a = append(a, []float64{x, y, 1, 0, 0, 0, -u * x, -u * y})
+		b = append(b, u)
+		a = append(a, []float64{0, 0, 0, x, y, 1, -v * x, -v * y})
+		b = append(b, v)
+	}
+
+	x, err := solveLeastSquares(a, b)
+	if err != nil {
+		return Homography{}, fmt.Errorf("compute homography: %w", ErrDegeneratePoints)
+	}
+	normalized := Homography{x[0], x[1], x[2], x[3], x[4], x[5], x[6], x[7], 1}
+
+	// denormalize: H = dstNorm^-1 * Hn * srcNorm
+	dstNormInv, err := dstNorm.Inverse()
+	if err != nil {
+		return Homography{}, fmt.Errorf("compute homography: %w", ErrDegeneratePoints)
+	}
+	return dstNormInv.Multiply(normalized).Multiply(srcNorm), nil
+}
+
+// normalizePoints translates the points to their centroid and scales them to an average distance of sqrt(2).
+// The normalizing transform and the normalized points are returned.
+func normalizePoints(points []Point) (Homography, []Point) {
+	var cx, cy float64
+	for _, p := range points {
+		cx += p.X
+		cy += p.Y
+	}
+	cx /= float64(len(points))
+	cy /= float64(len(points))
+
+	var meanDist float64
+	for _, p := range points {
+		meanDist += math.Hypot(p.X-cx, p.Y-cy)
+	}
+	meanDist /= float64(len(points))
+	scale := 1.0
+	if meanDist > 0 {
+		scale = math.Sqrt2 / meanDist
+	}
+
+	transform := Homography{scale, 0, -scale * cx, 0, scale, -scale * cy, 0, 0, 1}
+	normalized := make([]Point, len(points))
+	for i, p := range points {
+		normalized[i] = transform.Apply(p)
+	}
+	return transform, normalized
+}
@@ -0,0 +1,126 @@
+package calibration
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	atomicfile "github.com/One-Hundred-Eighty/Circle/pkg/atomic-file"
+	dartmasterlogger "github.com/One-Hundred-Eighty/Circle/pkg/dartmaster-logger"
+)
+
+// DefaultIntrinsicsStoreDir is the directory where the lens calibrations are persisted by default.
+const DefaultIntrinsicsStoreDir = "/var/lib/dartmaster/intrinsics"
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// IntrinsicsStore persists the lens calibrations per camera identity, so they survive a different device numbering
+// (e.g. after the cameras were plugged into other ports in a different order).
+type IntrinsicsStore struct {
+	logger     *dartmasterlogger.DartmasterLogger
+	mu         sync.RWMutex
+	dir        string
+	intrinsics map[string]Intrinsics
+}
+
+// NewIntrinsicsStore returns a store that persists the lens calibrations as json files inside the hand-overed directory.
+// All existing lens calibrations are loaded. Files that can't be loaded are logged and moved aside (suffix ".bad").
+func NewIntrinsicsStore(dir string) (*IntrinsicsStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("NewIntrinsicsStore() - error: creating directory %s: %v", dir, err)
+	}
+	store := &IntrinsicsStore{
+		logger:     dartmasterlogger.NewDartmasterLogger("[intrinsics-store] "),
+		dir:        dir,
+		intrinsics: make(map[string]Intrinsics),
+	}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Get returns the lens calibration of a camera identity.
+func (s *IntrinsicsStore) Get(identity string) (Intrinsics, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	in, ok := s.intrinsics[identity]
+	return in, ok
+}
+
+// Save persists the lens calibration of a camera identity. The file is replaced atomically.
+func (s *IntrinsicsStore) Save(in Intrinsics) error {
+	if in.Identity == "" {
+		return fmt.Errorf("Save() - error: lens calibration without camera identity")
+	}
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Save() - error: encoding lens calibration (identity: %s): %v", in.Identity, err)
+	}
+	if err := atomicfile.Write(s.path(in.Identity), data); err != nil {
+		return fmt.Errorf("Save() - error: writing lens calibration (identity: %s): %v", in.Identity, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.intrinsics[in.Identity] = in
+	return nil
+}
+
+// Delete removes the lens calibration of a camera identity.
+func (s *IntrinsicsStore) Delete(identity string) error {
+	if err := os.Remove(s.path(identity)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Delete() - error: removing lens calibration (identity: %s): %v", identity, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.intrinsics, identity)
+	return nil
+}
+
+// path returns the file of an identity. The identity is sanitized and extended by its hash to avoid collisions.
+func (s *IntrinsicsStore) path(identity string) string {
+	h := fnv.New32a()
+	h.Write([]byte(identity))
+	return filepath.Join(s.dir, fmt.Sprintf("lens-%s-%08x.json", unsafeFileChars.ReplaceAllString(identity, "_"), h.Sum32()))
+}
+
+// load loads all persisted lens calibrations. Files that can't be loaded are skipped.
+func (s *IntrinsicsStore) load() error {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "lens-*.json"))
+	if err != nil {
+		return fmt.Errorf("load() - error: %v", err)
+	}
+	for _, path := range paths {
+		in, err := loadIntrinsics(path)
+		if err != nil {
+			s.logger.PrintfErr("lens calibration %s skipped: %v", path, err)
+			if err := os.Rename(path, path+badFileSuffix); err != nil {
+				s.logger.PrintfErr("moving aside %s: %v", path, err)
+			}
+			continue
+		}
+		s.intrinsics[in.Identity] = in
+	}
+	return nil
+}
+
+// loadIntrinsics reads and validates a single lens calibration file.
+func loadIntrinsics(path string) (Intrinsics, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Intrinsics{}, err
+	}
+	var in Intrinsics
+	if err := json.Unmarshal(data, &in); err != nil {
+		return Intrinsics{}, fmt.Errorf("decoding: %v", err)
+	}
+	if in.Identity == "" || in.Fx == 0 || in.Fy == 0 {
+		return Intrinsics{}, fmt.Errorf("invalid lens calibration")
+	}
+	return in, nil
+}
@@ -0,0 +1,265 @@
+package calibration
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MinCheckerboardSnapshots is the minimum number of checkerboard snapshots required for the intrinsic calibration.
+const MinCheckerboardSnapshots = 3
+
+// MaxIntrinsicsError is the maximum allowed root mean square error (in pixels) of the straightened checkerboard corners.
+const MaxIntrinsicsError = 1.5
+
+var ErrIntrinsicsError = errors.New("intrinsic calibration error too large")
+
+// Intrinsics describes the lens distortion of a camera (Brown-Conrady model with radial coefficients k1, k2 and tangential coefficients p1, p2).
+// The pixel coordinates refer to the full camera frame (before the region of interest is applied).
+//
+// The distortion is estimated from straight lines only (plumb-line method), which cannot separate the focal length from the
+// radial coefficients. The normalization length (Fx, Fy) is therefore fixed to half of the image diagonal and the distortion
+// center (Cx, Cy) to the image center; the remaining perspective is part of the homography of the board calibration.
+type Intrinsics struct {
+	Identity    string    `json:"identity"` // identity of the camera device (see cameraAdmin.Identity)
+	ImageWidth  int       `json:"imageWidth"`
+	ImageHeight int       `json:"imageHeight"`
+	Fx          float64   `json:"fx"`
+	Fy          float64   `json:"fy"`
+	Cx          float64   `json:"cx"`
+	Cy          float64   `json:"cy"`
+	K1          float64   `json:"k1"`
+	K2          float64   `json:"k2"`
+	P1          float64   `json:"p1"`
+	P2          float64   `json:"p2"`
+	Snapshots   int       `json:"snapshots"`
+	Error       float64   `json:"error"`    // root mean square error of the straightened checkerboard corners in pixels
+	MaxShift    float64   `json:"maxShift"` // largest correction inside the image in pixels
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Checkerboard contains the inner corners of a checkerboard detected in a snapshot (row-major, Columns corners per row).
+type Checkerboard struct {
+	Columns int     `json:"columns"`
+	Rows    int     `json:"rows"`
+	Corners []Point `json:"corners"`
+}
+
+// newIntrinsics returns undistorted intrinsics of the hand-overed image size.
+func newIntrinsics(identity string, imageWidth, imageHeight int) Intrinsics {
+	f := math.Hypot(float64(imageWidth), float64(imageHeight)) / 2
+	return Intrinsics{
+		Identity:    identity,
+		ImageWidth:  imageWidth,
+		ImageHeight: imageHeight,
+		Fx:          f,
+		Fy:          f,
+		Cx:          float64(imageWidth) / 2,
+		Cy:          float64(imageHeight) / 2,
+	}
+}
+
+// CalibrateIntrinsics estimates the lens distortion of a camera from the checkerboards detected in several snapshots.
+// The coefficients are chosen so that the undistorted corners of every checkerboard are related to the ideal grid by a homography,
+// i.e. all straight lines of the checkerboard become straight again.
+func CalibrateIntrinsics(identity string, imageWidth, imageHeight int, checkerboards []Checkerboard) (Intrinsics, error) {
+	if len(checkerboards) < MinCheckerboardSnapshots {
+		return Intrinsics{}, fmt.Errorf("CalibrateIntrinsics() - error: at least %d checkerboard snapshots required, got %d", MinCheckerboardSnapshots, len(checkerboards))
+	}
+	for i, cb := range checkerboards {
+		if cb.Columns < 3 || cb.Rows < 3 || len(cb.Corners) != cb.Columns*cb.Rows {
+			return Intrinsics{}, fmt.Errorf("CalibrateIntrinsics() - error: snapshot %d: invalid checkerboard (%dx%d, %d corners)", i, cb.Columns, cb.Rows, len(cb.Corners))
+		}
+	}
+
+	intrinsics := newIntrinsics(identity, imageWidth, imageHeight)
+	params := []float64{0, 0, 0, 0}
+	residuals := func(params []float64) ([]float64, error) {
+		candidate := intrinsics
+		candidate.K1, candidate.K2, candidate.P1, candidate.P2 = params[0], params[1], params[2], params[3]
+		return candidate.gridResiduals(checkerboards)
+	}
+
+	params, err := levenbergMarquardt(residuals, params, 100)
+	if err != nil {
+		return Intrinsics{}, fmt.Errorf("CalibrateIntrinsics() - error: %v", err)
+	}
+	intrinsics.K1, intrinsics.K2, intrinsics.P1, intrinsics.P2 = params[0], params[1], params[2], params[3]
+
+	r, err := intrinsics.gridResiduals(checkerboards)
+	if err != nil {
+		return Intrinsics{}, fmt.Errorf("CalibrateIntrinsics() - error: %v", err)
+	}
+	intrinsics.Error = rms(r)
+	intrinsics.Snapshots = len(checkerboards)
+	intrinsics.MaxShift = intrinsics.maxShift()
+	intrinsics.CreatedAt = time.Now()
+	if intrinsics.Error > MaxIntrinsicsError {
+		return Intrinsics{}, fmt.Errorf("CalibrateIntrinsics() - error: %.2f px: %w", intrinsics.Error, ErrIntrinsicsError)
+	}
+	return intrinsics, nil
+}
+
+// Distort maps an ideal (undistorted) image point to the point recorded by the camera.
+func (in *Intrinsics) Distort(p Point) Point {
+	x := (p.X - in.Cx) / in.Fx
+	y := (p.Y - in.Cy) / in.Fy
+	xd, yd := in.distortNormalized(x, y)
+	return Point{X: xd*in.Fx + in.Cx, Y: yd*in.Fy + in.Cy}
+}
+
+// Undistort maps a point recorded by the camera to the ideal (undistorted) image point.
+// The distortion model has no closed-form inverse, so the point is refined iteratively.
+func (in *Intrinsics) Undistort(p Point) Point {
+	xd := (p.X - in.Cx) / in.Fx
+	yd := (p.Y - in.Cy) / in.Fy
+	x, y := xd, yd
+	for i := 0; i < 20; i++ {
+		r2 := x*x + y*y
+		radial := 1 + in.K1*r2 + in.K2*r2*r2
+		dx := 2*in.P1*x*y + in.P2*(r2+2*x*x)
+		dy := in.P1*(r2+2*y*y) + 2*in.P2*x*y
+		nx, ny := (xd-dx)/radial, (yd-dy)/radial
+		converged := math.Abs(nx-x) < 1e-10 && math.Abs(ny-y) < 1e-10
+		x, y = nx, ny
+		if converged {
+			break
+		}
+	}
+	return Point{X: x*in.Fx + in.Cx, Y: y*in.Fy + in.Cy}
+}
+
+func (in *Intrinsics) distortNormalized(x, y float64) (float64, float64) {
+	r2 := x*x + y*y
+	radial := 1 + in.K1*r2 + in.K2*r2*r2
+	xd := x*radial + 2*in.P1*x*y + in.P2*(r2+2*x*x)
+	yd := y*radial + in.P1*(r2+2*y*y) + 2*in.P2*x*y
+	return xd, yd
+}
+
+// gridResiduals undistorts the checkerboard corners and returns the distances (x and y in pixels)
+// to the ideal grid mapped by the best fitting homography of every snapshot.
+func (in *Intrinsics) gridResiduals(checkerboards []Checkerboard) ([]float64, error) {
+	var residuals []float64
+	for _, cb := range checkerboards {
+		grid := make([]Point, 0, len(cb.Corners))
+		undistorted := make([]Point, 0, len(cb.Corners))
+		for i, corner := range cb.Corners {
+			grid = append(grid, Point{X: float64(i % cb.Columns), Y: float64(i / cb.Columns)})
+			undistorted = append(undistorted, in.Undistort(corner))
+		}
+		h, err := ComputeHomography(grid, undistorted)
+		if err != nil {
+			return nil, err
+		}
+		for i, g := range grid {
+			p := h.Apply(g)
+			residuals = append(residuals, p.X-undistorted[i].X, p.Y-undistorted[i].Y)
+		}
+	}
+	return residuals, nil
+}
+
+// maxShift returns the largest correction of the undistortion at the image border.
+func (in *Intrinsics) maxShift() float64 {
+	var shift float64
+	w, h := float64(in.ImageWidth), float64(in.ImageHeight)
+	for _, p := range []Point{{0, 0}, {w / 2, 0}, {w, 0}, {0, h / 2}, {w, h / 2}, {0, h}, {w / 2, h}, {w, h}} {
+		shift = math.Max(shift, in.Undistort(p).Distance(p))
+	}
+	return shift
+}
+
+// levenbergMarquardt minimizes the sum of the squared residuals with a numerical jacobian.
+func levenbergMarquardt(residuals func([]float64) ([]float64, error), params []float64, iterations int) ([]float64, error) {
+	const epsilon = 1e-7
+	params = append([]float64(nil), params...)
+	r, err := residuals(params)
+	if err != nil {
+		return nil, err
+	}
+	cost := sumSquares(r)
+	lambda := 1e-3
+
+	for it := 0; it < iterations; it++ {
+		// numerical jacobian (residuals x params)
+		jacobian := make([][]float64, len(r))
+		for i := range jacobian {
+			jacobian[i] = make([]float64, len(params))
+		}
+		for j := range params {
+			shifted := append([]float64(nil), params...)
+			shifted[j] += epsilon
+			rj, err := residuals(shifted)
+			if err != nil {
+				return nil, err
+			}
+			for i := range r {
+				jacobian[i][j] = (rj[i] - r[i]) / epsilon
+			}
+		}
+
+		improved := false
+		for attempt := 0; attempt < 10 && !improved; attempt++ {
+			// (J^T*J + lambda*diag(J^T*J)) * delta = -J^T*r
+			a := make([][]float64, len(params))
+			b := make([]float64, len(params))
+			for i := range params {
+				a[i] = make([]float64, len(params))
+				for j := range params {
+					for row := range r {
+						a[i][j] += jacobian[row][i] * jacobian[row][j]
+					}
+				}
+				for row := range r {
+					b[i] -= jacobian[row][i] * r[row]
+				}
+				a[i][i] += lambda * math.Max(a[i][i], 1e-12)
+			}
+			delta, err := solveLinearSystem(a, b)
+			if err != nil {
+				lambda *= 10
+				continue
+			}
+
+			candidate := make([]float64, len(params))
+			for i := range params {
+				candidate[i] = params[i] + delta[i]
+			}
+			rc, err := residuals(candidate)
+			if err == nil && sumSquares(rc) < cost {
+				params, r = candidate, rc
+				improvement := cost - sumSquares(rc)
+				cost = sumSquares(rc)
+				lambda /= 10
+				improved = true
+				if improvement < 1e-12*math.Max(cost, 1) {
+					return params, nil
+				}
+			} else {
+				lambda *= 10
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+	return params, nil
+}
+
+func sumSquares(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v * v
+	}
+	return sum
+}
+
+// rms returns the root mean square error of residual pairs (x, y).
+func rms(residuals []float64) float64 {
+	if len(residuals) == 0 {
+		return 0
+	}
+	return math.Sqrt(sumSquares(residuals) / float64(len(residuals)/2))
+}
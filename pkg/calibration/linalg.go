@@ -0,0 +1,67 @@
+package calibration
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrSingularMatrix = errors.New("singular matrix")
+
+// solveLinearSystem solves a*x = b via gaussian elimination with partial pivoting. a has to be a square matrix.
+// The hand-overed slices are modified.
+func solveLinearSystem(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	for col := 0; col < n; col++ {
+		// find pivot
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, ErrSingularMatrix
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		// eliminate
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+			b[row] -= factor * b[col]
+		}
+	}
+
+	// back substitution
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * x[k]
+		}
+		x[row] = sum / a[row][row]
+	}
+	return x, nil
+}
+
+// solveLeastSquares solves the overdetermined system a*x = b in the least squares sense via the normal equations.
+func solveLeastSquares(a [][]float64, b []float64) ([]float64, error) {
+	cols := len(a[0])
+	ata := make([][]float64, cols)
+	atb := make([]float64, cols)
+	for i := 0; i < cols; i++ {
+		ata[i] = make([]float64, cols)
+		for j := 0; j < cols; j++ {
+			for row := range a {
+				ata[i][j] += a[row][i] * a[row][j]
+			}
+		}
+		for row := range a {
+			atb[i] += a[row][i] * b[row]
+		}
+	}
+	return solveLinearSystem(ata, atb)
+}
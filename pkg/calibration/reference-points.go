@@ -0,0 +1,89 @@
+package calibration
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+// board dimensions of a standard steel-tip dartboard in millimetres
+const (
+	doubleRingOuterRadius = dartboard.StandardDoubleOuterRadius
+	trebleRingOuterRadius = dartboard.StandardTrebleOuterRadius
+)
+
+// segmentOrder is the clockwise order of the segments, starting at the top of the board.
+var segmentOrder = dartboard.SegmentOrder
+
+// DefaultReferencePoints are the points an operator has to mark for a calibration:
+// the outer edge of the double ring at the wires between 20/1, 6/10, 3/19 and 11/14.
+var DefaultReferencePoints = []string{"20/1", "6/10", "3/19", "11/14"}
+
+// BoardPoint returns the board coordinate (in mm, origin at the bull, y pointing up) of a named reference point.
+//
+// supported names:
+// "BULL" (center of the board) |
+// "<a>/<b>" (outer edge of the double ring at the wire between the neighbouring segments a and b, e.g. "20/1") |
+// "T<a>/<b>" (outer edge of the treble ring at the wire between the neighbouring segments a and b, e.g. "T20/1")
+func BoardPoint(name string) (Point, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "BULL" {
+		return Point{}, nil
+	}
+
+	radius := doubleRingOuterRadius
+	if strings.HasPrefix(name, "T") {
+		radius = trebleRingOuterRadius
+		name = name[1:]
+	}
+
+	parts := strings.Split(name, "/")
+	if len(parts) != 2 {
+		return Point{}, fmt.Errorf("reference point %q: invalid name", name)
+	}
+	a, errA := strconv.Atoi(parts[0])
+	b, errB := strconv.Atoi(parts[1])
+	if errA != nil || errB != nil {
+		return Point{}, fmt.Errorf("reference point %q: invalid segment", name)
+	}
+
+	angle, err := wireAngle(a, b)
+	if err != nil {
+		return Point{}, fmt.Errorf("reference point %q: %w", name, err)
+	}
+	return Point{X: radius * math.Cos(angle), Y: radius * math.Sin(angle)}, nil
+}
+
+// wireAngle returns the angle (radians, counterclockwise from the positive x-axis) of the wire between two neighbouring segments.
+func wireAngle(a, b int) (float64, error) {
+	idxA, idxB := segmentIndex(a), segmentIndex(b)
+	if idxA < 0 || idxB < 0 {
+		return 0, fmt.Errorf("unknown segment")
+	}
+	n := len(segmentOrder)
+	var first int
+	switch {
+	case (idxA+1)%n == idxB:
+		first = idxA
+	case (idxB+1)%n == idxA:
+		first = idxB
+	default:
+		return 0, fmt.Errorf("segments %d and %d are not neighbours", a, b)
+	}
+
+	// segment i is centered at 90° - i*18°, its clockwise wire is 9° further
+	degrees := 90.0 - float64(first)*18.0 - 9.0
+	return degrees * math.Pi / 180.0, nil
+}
+
+func segmentIndex(segment int) int {
+	for i, s := range segmentOrder {
+		if s == segment {
+			return i
+		}
+	}
+	return -1
+}
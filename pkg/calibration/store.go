@@ -0,0 +1,129 @@
+package calibration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	atomicfile "github.com/One-Hundred-Eighty/Circle/pkg/atomic-file"
+	dartmasterlogger "github.com/One-Hundred-Eighty/Circle/pkg/dartmaster-logger"
+)
+
+// DefaultStoreDir is the directory where the calibrations are persisted by default.
+const DefaultStoreDir = "/var/lib/dartmaster/calibration"
+
+// badFileSuffix is appended to calibration files that can't be loaded.
+const badFileSuffix = ".bad"
+
+type Store struct {
+	logger       *dartmasterlogger.DartmasterLogger
+	mu           sync.RWMutex
+	dir          string
+	calibrations map[int]Calibration
+}
+
+// NewStore returns a calibration store that persists the calibrations as json files inside the hand-overed directory.
+// All existing calibrations are loaded. Files that can't be loaded are logged and moved aside (suffix ".bad"), so a single
+// broken file never stops the other cameras.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("NewStore() - error: creating directory %s: %v", dir, err)
+	}
+	store := &Store{
+		logger:       dartmasterlogger.NewDartmasterLogger("[calibration-store] "),
+		dir:          dir,
+		calibrations: make(map[int]Calibration),
+	}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Get returns the calibration of a camera.
+func (s *Store) Get(cameraID int) (Calibration, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.calibrations[cameraID]
+	return c, ok
+}
+
+// All returns the calibrations of all cameras.
+func (s *Store) All() []Calibration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var calibrations []Calibration
+	for _, c := range s.calibrations {
+		calibrations = append(calibrations, c)
+	}
+	return calibrations
+}
+
+// Save persists the calibration of a camera. The file is replaced atomically, so a crash never leaves a broken calibration behind.
+func (s *Store) Save(c Calibration) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Save() - error: encoding calibration (camera-id: %d): %v", c.CameraID, err)
+	}
+	if err := atomicfile.Write(s.path(c.CameraID), data); err != nil {
+		return fmt.Errorf("Save() - error: writing calibration (camera-id: %d): %v", c.CameraID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calibrations[c.CameraID] = c
+	return nil
+}
+
+// Delete removes the calibration of a camera.
+func (s *Store) Delete(cameraID int) error {
+	if err := os.Remove(s.path(cameraID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Delete() - error: removing calibration (camera-id: %d): %v", cameraID, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.calibrations, cameraID)
+	return nil
+}
+
+func (s *Store) path(cameraID int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("camera-%d.json", cameraID))
+}
+
+// load loads all persisted calibrations. Files that can't be loaded are skipped.
+func (s *Store) load() error {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "camera-*.json"))
+	if err != nil {
+		return fmt.Errorf("load() - error: %v", err)
+	}
+	for _, path := range paths {
+		c, err := loadCalibration(path)
+		if err != nil {
+			s.logger.PrintfErr("calibration %s skipped: %v", path, err)
+			if err := os.Rename(path, path+badFileSuffix); err != nil {
+				s.logger.PrintfErr("moving aside %s: %v", path, err)
+			}
+			continue
+		}
+		s.calibrations[c.CameraID] = c
+	}
+	return nil
+}
+
+// loadCalibration reads and validates a single calibration file.
+func loadCalibration(path string) (Calibration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Calibration{}, err
+	}
+	var c Calibration
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Calibration{}, fmt.Errorf("decoding: %v", err)
+	}
+	if err := c.Validate(); err != nil {
+		return Calibration{}, fmt.Errorf("invalid calibration: %v", err)
+	}
+	return c, nil
+}
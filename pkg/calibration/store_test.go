@@ -0,0 +1,63 @@
+package calibration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestCalibration(t *testing.T, cameraID int) Calibration {
+	t.Helper()
+	var correspondences []Correspondence
+	for _, name := range DefaultReferencePoints {
+		p, err := BoardPoint(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		correspondences = append(correspondences, Correspondence{Name: name, Image: Point{X: 640 + 1.5*p.X, Y: 360 - 1.5*p.Y}})
+	}
+	c, err := Calibrate(cameraID, 1280, 720, correspondences)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// a calibration file that can't be loaded is moved aside, the other cameras keep their calibrations
+func TestNewStoreSkipsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(newTestCalibration(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{name: "truncated", file: "camera-2.json", data: `{"cameraId":2,`},
+		{name: "invalid", file: "camera-3.json", data: `{"cameraId":3}`},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err = NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() error: %v", err)
+	}
+	if _, ok := store.Get(1); !ok || len(store.All()) != 1 {
+		t.Errorf("%d calibrations loaded, want the calibration of camera 1", len(store.All()))
+	}
+	for _, tt := range tests {
+		if _, err := os.Stat(filepath.Join(dir, tt.file+badFileSuffix)); err != nil {
+			t.Errorf("%s: file not moved aside: %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package cameraadmin
+
+import "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+
+type Option func(*cameraAdmin)
+
+// WithCameras replaces the default cameras by the hand-overed device paths. The camera-ids are assigned in order, starting with 1.
+// This option has to be applied before camera specific options (e.g. WithROI).
+func WithCameras(devicePaths ...string) Option {
+	return func(ca *cameraAdmin) {
+		ca.cameras = nil
+		for i, devicePath := range devicePaths {
+			ca.cameras = append(ca.cameras, newCamera(i+1, devicePath, ca.logger))
+		}
+	}
+}
+
+// WithROI sets the region of interest (e.g. the dartboard area) of a camera in sensor pixels.
+// The region is cropped by the camera hardware if the driver supports it, otherwise the frames are cropped in software.
+func WithROI(cameraID int, roi v4l2.Rect) Option {
+	return func(ca *cameraAdmin) {
+		if c := ca.camera(cameraID); c != nil {
+			c.roi = roi
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package cameraadmin
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	motiondetector "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/motion-detector"
+	subscriptionhandler "github.com/One-Hundred-Eighty/Circle/pkg/subscription-handler"
+)
+
+type cameraMotion struct {
+	mu                  sync.Mutex
+	detector            *motiondetector.Detector
+	subscriptionHandler *subscriptionhandler.SubscriptionHandler[motiondetector.Event]
+	frameCh             chan frame.Frame
+}
+
+func newCameraMotion(cameraID int, config motiondetector.Config) *cameraMotion {
+	return &cameraMotion{
+		detector:            motiondetector.NewDetector(cameraID, config),
+		subscriptionHandler: subscriptionhandler.NewSubscriptionHandler[motiondetector.Event](),
+		frameCh:             make(chan frame.Frame, 1),
+	}
+}
+
+// WithMotionConfig sets the motion detector configuration of a camera.
+func WithMotionConfig(cameraID int, config motiondetector.Config) Option {
+	return func(ca *cameraAdmin) {
+		if c := ca.camera(cameraID); c != nil {
+			c.motion.detector = motiondetector.NewDetector(cameraID, config)
+		}
+	}
+}
+
+// SubscribeMotion subscribes on the motion events of a camera based on the hand-overed cameraID.
+// The motion detection only runs as long as at least one client is subscribed.
+// The subscriberName is optional for logging purposes.
+func (ca *cameraAdmin) SubscribeMotion(cameraID int, subscriberName string) (<-chan motiondetector.Event, error) {
+	c := ca.camera(cameraID)
+	if c == nil {
+		return nil, fmt.Errorf("SubscribeMotion() - error: unknown camera (camera-id: %d)", cameraID)
+	}
+
+	if c.motion.subscriptionHandler.Subscriptions() == 0 {
+		// --> the reference background is outdated, because no frames were processed in the meanwhile
+		c.motion.mu.Lock()
+		c.motion.detector.Reset()
+		c.motion.mu.Unlock()
+	}
+	eventCh := c.motion.subscriptionHandler.Subscribe()
+
+	currentSubscribers := c.motion.subscriptionHandler.Subscriptions()
+	if subscriberName != "" {
+		ca.logger.Printf("motion client added on camera %v. client ID: %s. %d registered clients", cameraID, subscriberName, currentSubscribers)
+	} else {
+		ca.logger.Printf("motion client added on camera %v. %d registered clients", cameraID, currentSubscribers)
+	}
+	return eventCh, nil
+}
+
+// UnsubscribeMotion unsubscribes from the motion events of a camera based on the hand-overed cameraID and its matching event-channel.
+// The subscriberName is optional for logging purposes.
+func (ca *cameraAdmin) UnsubscribeMotion(cameraID int, eventCh <-chan motiondetector.Event, subscriberName string) {
+	c := ca.camera(cameraID)
+	if c == nil {
+		return
+	}
+	c.motion.subscriptionHandler.Unsubscribe(eventCh)
+
+	currentSubscribers := c.motion.subscriptionHandler.Subscriptions()
+	if subscriberName != "" {
+		ca.logger.Printf("motion client removed from camera %v. client ID: %s. %d registered clients", cameraID, subscriberName, currentSubscribers)
+	} else {
+		ca.logger.Printf("motion client removed from camera %v. %d registered clients", cameraID, currentSubscribers)
+	}
+}
+
+// ResetMotionReference drops the reference background of a camera's motion detector (e.g. after the darts were removed).
+func (ca *cameraAdmin) ResetMotionReference(cameraID int) error {
+	c := ca.camera(cameraID)
+	if c == nil {
+		return fmt.Errorf("ResetMotionReference() - error: unknown camera (camera-id: %d)", cameraID)
+	}
+	c.motion.mu.Lock()
+	defer c.motion.mu.Unlock()
+	c.motion.detector.Reset()
+	return nil
+}
+
+// feed hands a frame over to the motion detector. If the detector is still busy with the previous frame, the frame is dropped.
+func (cm *cameraMotion) feed(f frame.Frame) {
+	select {
+	case cm.frameCh <- f:
+	default:
+		// --> detector is busy --> drop the frame
+	}
+}
+
+// startMotionDetector starts processing the fed frames and publishes the resulting motion events with all subscribed clients.
+func (c *camera) startMotionDetector() {
+	go func() {
+		for {
+			select {
+			case <-c.stopPublisherCh:
+				// stop signal received, exit the motion detector
+				return
+			case f := <-c.motion.frameCh:
+				c.motion.mu.Lock()
+				events, err := c.motion.detector.Process(f)
+				c.motion.mu.Unlock()
+				if err != nil {
+					c.logger.PrintlnErr(err)
+					continue
+				}
+				for _, event := range events {
+					c.motion.subscriptionHandler.Publish(event)
+				}
+			}
+		}
+	}()
+}
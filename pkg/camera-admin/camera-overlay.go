@@ -0,0 +1,113 @@
+package cameraadmin
+
+import (
+	"fmt"
+	"image"
+
+	camerasubscriptionhandler "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/camera-subscription-handler"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/overlay"
+)
+
+type cameraOverlay struct {
+	subscriptionHandler *camerasubscriptionhandler.CameraSubscriptionHandler[frame.Frame]
+	frameCh             chan frame.Frame
+}
+
+func newCameraOverlay() *cameraOverlay {
+	return &cameraOverlay{
+		subscriptionHandler: camerasubscriptionhandler.NewCameraSubscriptionHandler[frame.Frame](),
+		frameCh:             make(chan frame.Frame, 1),
+	}
+}
+
+// SetOverlaySource sets the source of the data (calibration, detected darts) that is drawn onto the overlay streams.
+func (ca *cameraAdmin) SetOverlaySource(source overlay.Source) {
+	ca.overlayMu.Lock()
+	defer ca.overlayMu.Unlock()
+	ca.overlaySource = source
+}
+
+// SubscribeOverlay subscribes on the annotated stream of a camera: the calibrated board segments, the region of interest
+// and the latest detected darts are drawn onto the frames, which are re-encoded as mjpeg frames.
+// The overlay is only rendered as long as at least one client is subscribed.
+// The subscriberName is optional for logging purposes.
+func (ca *cameraAdmin) SubscribeOverlay(cameraID int, subscriberName string) (<-chan frame.Frame, error) {
+	c := ca.camera(cameraID)
+	if c == nil {
+		return nil, fmt.Errorf("SubscribeOverlay() - error: unknown camera (camera-id: %d)", cameraID)
+	}
+	frameCh := c.overlay.subscriptionHandler.Subscribe()
+
+	currentSubscribers := c.overlay.subscriptionHandler.Subscriptions()
+	if subscriberName != "" {
+		ca.logger.Printf("overlay client added on camera %v. client ID: %s. %d registered clients", cameraID, subscriberName, currentSubscribers)
+	} else {
+		ca.logger.Printf("overlay client added on camera %v. %d registered clients", cameraID, currentSubscribers)
+	}
+	return frameCh, nil
+}
+
+// UnsubscribeOverlay unsubscribes from the annotated stream of a camera based on the hand-overed cameraID and its matching frame-channel.
+// The subscriberName is optional for logging purposes.
+func (ca *cameraAdmin) UnsubscribeOverlay(cameraID int, frameCh <-chan frame.Frame, subscriberName string) {
+	c := ca.camera(cameraID)
+	if c == nil {
+		return
+	}
+	c.overlay.subscriptionHandler.Unsubscribe(frameCh)
+
+	currentSubscribers := c.overlay.subscriptionHandler.Subscriptions()
+	if subscriberName != "" {
+		ca.logger.Printf("overlay client removed from camera %v. client ID: %s. %d registered clients", cameraID, subscriberName, currentSubscribers)
+	} else {
+		ca.logger.Printf("overlay client removed from camera %v. %d registered clients", cameraID, currentSubscribers)
+	}
+}
+
+// feed hands a frame over to the overlay renderer. If the renderer is still busy with the previous frame, the frame is dropped.
+func (co *cameraOverlay) feed(f frame.Frame) {
+	select {
+	case co.frameCh <- f:
+	default:
+		// --> renderer is busy --> drop the frame
+	}
+}
+
+// startOverlayRenderer starts rendering the fed frames and publishes the annotated frames with all subscribed clients.
+func (ca *cameraAdmin) startOverlayRenderer(c *camera) {
+	go func() {
+		for {
+			select {
+			case <-c.stopPublisherCh:
+				// stop signal received, exit the overlay renderer
+				return
+			case f := <-c.overlay.frameCh:
+				annotated, err := overlay.Render(f, ca.overlayData(c))
+				if err != nil {
+					c.logger.PrintlnErr(err)
+					continue
+				}
+				c.overlay.subscriptionHandler.Publish(annotated)
+			}
+		}
+	}()
+}
+
+// overlayData returns the overlay data of a camera. The region of interest of the motion detection is drawn if no other roi is set.
+func (ca *cameraAdmin) overlayData(c *camera) overlay.Data {
+	ca.overlayMu.RLock()
+	source := ca.overlaySource
+	ca.overlayMu.RUnlock()
+
+	var data overlay.Data
+	if source != nil {
+		data = source(c.id)
+	}
+	if data.ROI == (image.Rectangle{}) {
+		c.motion.mu.Lock()
+		data.ROI = c.motion.detector.Config().ROI
+		c.motion.mu.Unlock()
+	}
+	return data
+}
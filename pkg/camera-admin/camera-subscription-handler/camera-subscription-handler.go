@@ -44,6 +44,8 @@ func (sh *CameraSubscriptionHandler[T]) Publish(item T) {
 
 // Subscriptions return the current amount of subscriptions.
 func (sh *CameraSubscriptionHandler[T]) Subscriptions() int {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
 	return len(sh.subscriptions)
 }
 
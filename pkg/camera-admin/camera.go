@@ -4,46 +4,70 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	camerasubscriptionhandler "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/camera-subscription-handler"
 	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/device"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	motiondetector "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/motion-detector"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/overlay"
 	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
 	dartmasterlogger "github.com/One-Hundred-Eighty/Circle/pkg/dartmaster-logger"
 )
 
 type cameraAdmin struct {
-	logger  *dartmasterlogger.DartmasterLogger
-	cameras []*camera
+	logger        *dartmasterlogger.DartmasterLogger
+	cameras       []*camera
+	overlayMu     sync.RWMutex
+	overlaySource overlay.Source // set after the detection was set up
 }
 
 type camera struct {
-	subscriptionHandler *camerasubscriptionhandler.CameraSubscriptionHandler[[]byte]
+	subscriptionHandler *camerasubscriptionhandler.CameraSubscriptionHandler[frame.Frame]
 	stopPublisherCh     chan struct{}
 	outputCh            <-chan []byte
+	source              FrameSource // virtual camera instead of a device
+	sourceCh            <-chan frame.Frame
 	id                  int
 	devicePath          string
 	device              *device.Device
+	roiMu               sync.RWMutex // guards roi and softwareCrop (set via the api, read by the frame publisher)
+	roi                 v4l2.Rect
+	softwareCrop        bool
+	logger              *dartmasterlogger.DartmasterLogger
+	motion              *cameraMotion
+	overlay             *cameraOverlay
 }
 
-func NewCameraAdmin() *cameraAdmin {
+func NewCameraAdmin(options ...Option) *cameraAdmin {
 	cameraAdmin := &cameraAdmin{
 		logger: dartmasterlogger.NewDartmasterLogger("[camera-admin] "),
-		cameras: []*camera{
-			{subscriptionHandler: camerasubscriptionhandler.NewCameraSubscriptionHandler[[]byte](),
-				id:         1,
-				devicePath: "/dev/video0"},
-			{subscriptionHandler: camerasubscriptionhandler.NewCameraSubscriptionHandler[[]byte](),
-				id:         2,
-				devicePath: "/dev/video2"},
-			{subscriptionHandler: camerasubscriptionhandler.NewCameraSubscriptionHandler[[]byte](),
-				id:         3,
-				devicePath: "/dev/video4"},
-		},
+	}
+	cameraAdmin.cameras = []*camera{
+		newCamera(1, "/dev/video0", cameraAdmin.logger),
+		newCamera(2, "/dev/video2", cameraAdmin.logger),
+		newCamera(3, "/dev/video4", cameraAdmin.logger),
+	}
+
+	// apply options
+	for _, o := range options {
+		o(cameraAdmin)
 	}
 	return cameraAdmin
 }
 
+func newCamera(id int, devicePath string, logger *dartmasterlogger.DartmasterLogger) *camera {
+	return &camera{
+		subscriptionHandler: camerasubscriptionhandler.NewCameraSubscriptionHandler[frame.Frame](),
+		id:                  id,
+		devicePath:          devicePath,
+		logger:              logger,
+		motion:              newCameraMotion(id, motiondetector.DefaultConfig()),
+		overlay:             newCameraOverlay(),
+	}
+}
+
 // Start starts all cameras
 //
 // width: resolution-width in pixels |
@@ -55,6 +79,18 @@ func (ca *cameraAdmin) Start(width, height int) error {
 	var err error
 
 	for i, c := range ca.cameras {
+		if c.source != nil {
+			// --> virtual camera
+			if err := ca.startSource(c, width, height); err != nil {
+				return fmt.Errorf("Start() - error: starting virtual camera (camera-id: %d): %v", c.id, err)
+			}
+			c.stopPublisherCh = make(chan struct{})
+			c.startFramePublisher()
+			c.startMotionDetector()
+			ca.startOverlayRenderer(c)
+			continue
+		}
+
 		// open camera
 		ca.cameras[i].device, err = device.Open(
 			c.devicePath,
@@ -65,6 +101,14 @@ func (ca *cameraAdmin) Start(width, height int) error {
 			return errors.New(errMsg)
 		}
 
+		// log the device summary
+		ca.logger.Printf("camera %d: %v", c.id, ca.cameras[i].device.Info())
+
+		// set region of interest
+		if c.ROI() != (v4l2.Rect{}) {
+			ca.setROI(ca.cameras[i])
+		}
+
 		// start camera
 		err := ca.cameras[i].device.Start(context.TODO())
 		if err != nil {
@@ -79,8 +123,10 @@ func (ca *cameraAdmin) Start(width, height int) error {
 		// grep cameraOutput channel
 		ca.cameras[i].outputCh = ca.cameras[i].device.GetOutput()
 
-		// start frame publisher
+		// start frame publisher, motion detector and overlay renderer
 		ca.cameras[i].startFramePublisher()
+		ca.cameras[i].startMotionDetector()
+		ca.startOverlayRenderer(ca.cameras[i])
 	}
 	return nil
 }
@@ -92,6 +138,8 @@ func (ca *cameraAdmin) ShutDown() error {
 	for i, c := range ca.cameras {
 		// unsubscribe all clients from the camera
 		ca.cameras[i].subscriptionHandler.UnsubscribeAll()
+		ca.cameras[i].motion.subscriptionHandler.UnsubscribeAll()
+		ca.cameras[i].overlay.subscriptionHandler.UnsubscribeAll()
 
 		// close the stopPublisherCh
 		// this channel is used by the frame-publisher to receive the massage that the frame-publisher should stop publishing frames
@@ -104,6 +152,15 @@ func (ca *cameraAdmin) ShutDown() error {
 		// on the raspberry pi 4 tested required minimum delay was 150ms --> 500ms should be more than enough
 		time.Sleep(500 * time.Millisecond)
 
+		if c.source != nil {
+			c.source.Stop()
+			continue
+		}
+		if c.device == nil {
+			// --> camera was never started
+			continue
+		}
+
 		// now we can close the cameras, because we can ensure, that nobody is pulling on the camera-frames anymore.
 		err := ca.cameras[i].device.Close()
 		if err != nil {
@@ -116,7 +173,7 @@ func (ca *cameraAdmin) ShutDown() error {
 
 // Subscribe subscribes on a camera based on the hand-overed cameraID and returns a channel to receive the camera live view.
 // The subscriberName is optional for logging purposes.
-func (ca *cameraAdmin) Subscribe(cameraID int, subscriberName string) <-chan []byte {
+func (ca *cameraAdmin) Subscribe(cameraID int, subscriberName string) <-chan frame.Frame {
 	cameraIDX := cameraID - 1
 	logCh := ca.cameras[cameraIDX].subscriptionHandler.Subscribe()
 
@@ -131,7 +188,7 @@ func (ca *cameraAdmin) Subscribe(cameraID int, subscriberName string) <-chan []b
 
 // Unsubscribe unsubscribes from a camera based on the hand-overed cameraID and its matching log-channel.
 // The subscriberName is optional for logging purposes.
-func (ca *cameraAdmin) Unsubscribe(cameraID int, logChan <-chan []byte, subscriberName string) {
+func (ca *cameraAdmin) Unsubscribe(cameraID int, logChan <-chan frame.Frame, subscriberName string) {
 	cameraIDX := cameraID - 1
 	ca.cameras[cameraIDX].subscriptionHandler.Unsubscribe(logChan)
 
@@ -143,6 +200,158 @@ func (ca *cameraAdmin) Unsubscribe(cameraID int, logChan <-chan []byte, subscrib
 	}
 }
 
+// Info returns a human-readable summary of the camera device (capabilities and active format).
+func (ca *cameraAdmin) Info(cameraID int) (device.Info, error) {
+	c := ca.camera(cameraID)
+	if c == nil {
+		return device.Info{}, fmt.Errorf("Info() - error: unknown camera (camera-id: %d)", cameraID)
+	}
+	if c.source != nil {
+		return device.Info{Path: c.devicePath, Driver: "virtual", Streaming: c.sourceCh != nil}, nil
+	}
+	if c.device == nil {
+		return device.Info{}, fmt.Errorf("Info() - error: camera not started (camera-id: %d)", cameraID)
+	}
+	return c.device.Info(), nil
+}
+
+// Identity returns a stable identity of the camera device (model and usb port), which does not change if the devices are numbered differently.
+// It is used to persist per-device data like the lens calibration.
+func (ca *cameraAdmin) Identity(cameraID int) (string, error) {
+	info, err := ca.Info(cameraID)
+	if err != nil {
+		return "", fmt.Errorf("Identity() - error: %v", err)
+	}
+	if info.Driver == "virtual" {
+		return "virtual:" + info.Path, nil
+	}
+	if info.BusInfo == "" {
+		return info.Card + "@" + info.Path, nil
+	}
+	return info.Card + "@" + info.BusInfo, nil
+}
+
+// CameraIDs returns the ids of all configured cameras.
+func (ca *cameraAdmin) CameraIDs() []int {
+	var ids []int
+	for _, c := range ca.cameras {
+		ids = append(ids, c.id)
+	}
+	return ids
+}
+
+// Snapshot returns the next recorded frame of a camera. An error is returned if no frame was received within the timeout.
+func (ca *cameraAdmin) Snapshot(cameraID int, timeout time.Duration) (frame.Frame, error) {
+	if ca.camera(cameraID) == nil {
+		return frame.Frame{}, fmt.Errorf("Snapshot() - error: unknown camera (camera-id: %d)", cameraID)
+	}
+	frameCh := ca.Subscribe(cameraID, "snapshot")
+	defer ca.Unsubscribe(cameraID, frameCh, "snapshot")
+
+	timeoutCh := time.After(timeout)
+	for {
+		select {
+		case f, ok := <-frameCh:
+			if !ok {
+				return frame.Frame{}, fmt.Errorf("Snapshot() - error: camera was shut down (camera-id: %d)", cameraID)
+			}
+			if f.IsEmpty() {
+				continue
+			}
+			return f, nil
+		case <-timeoutCh:
+			return frame.Frame{}, fmt.Errorf("Snapshot() - error: no frame received within %v (camera-id: %d)", timeout, cameraID)
+		}
+	}
+}
+
+// Stats returns the capture statistics of a camera (frame rate, latency and dropped frames).
+func (ca *cameraAdmin) Stats(cameraID int) (device.Stats, error) {
+	c := ca.camera(cameraID)
+	if c == nil || c.device == nil {
+		return device.Stats{}, fmt.Errorf("Stats() - error: camera not started (camera-id: %d)", cameraID)
+	}
+	return c.device.Stats(), nil
+}
+
+// ResetStats resets the capture statistics of a camera.
+func (ca *cameraAdmin) ResetStats(cameraID int) error {
+	c := ca.camera(cameraID)
+	if c == nil || c.device == nil {
+		return fmt.Errorf("ResetStats() - error: camera not started (camera-id: %d)", cameraID)
+	}
+	c.device.ResetStats()
+	return nil
+}
+
+// SetROI sets the region of interest of a running camera. A zero rectangle resets the region of interest to the full frame.
+func (ca *cameraAdmin) SetROI(cameraID int, roi v4l2.Rect) error {
+	c := ca.camera(cameraID)
+	if c == nil {
+		return fmt.Errorf("SetROI() - error: unknown camera (camera-id: %d)", cameraID)
+	}
+	c.roiMu.Lock()
+	c.roi = roi
+	if c.source != nil {
+		// --> virtual cameras are always cropped in software
+		c.softwareCrop = roi != (v4l2.Rect{})
+	}
+	c.roiMu.Unlock()
+	if c.source != nil || c.device == nil {
+		// --> camera not started yet --> roi is applied on start
+		return nil
+	}
+	ca.setROI(c)
+	return nil
+}
+
+// ROI returns the region of interest of a camera. A zero rectangle means the full frame.
+func (ca *cameraAdmin) ROI(cameraID int) v4l2.Rect {
+	c := ca.camera(cameraID)
+	if c == nil {
+		return v4l2.Rect{}
+	}
+	return c.ROI()
+}
+
+// setROI applies the region of interest of a camera via hardware cropping.
+// If the driver does not support cropping, the camera falls back to software cropping.
+func (ca *cameraAdmin) setROI(c *camera) {
+	c.roiMu.Lock()
+	defer c.roiMu.Unlock()
+	if c.roi == (v4l2.Rect{}) {
+		c.softwareCrop = false
+		c.device.ResetCropRect()
+		return
+	}
+
+	applied, err := c.device.SetCropRect(c.roi)
+	if err != nil {
+		ca.logger.Printf("camera %d: hardware cropping not supported (%v) --> fallback to software cropping", c.id, err)
+		c.softwareCrop = true
+		return
+	}
+	ca.logger.Printf("camera %d: hardware cropping active: %+v", c.id, applied)
+	c.softwareCrop = false
+}
+
+// ROI returns the region of interest of the camera. A zero rectangle means the full frame.
+func (c *camera) ROI() v4l2.Rect {
+	c.roiMu.RLock()
+	defer c.roiMu.RUnlock()
+	return c.roi
+}
+
+// camera returns the camera with the hand-overed cameraID or nil if the camera does not exist.
+func (ca *cameraAdmin) camera(cameraID int) *camera {
+	for _, c := range ca.cameras {
+		if c.id == cameraID {
+			return c
+		}
+	}
+	return nil
+}
+
 // startFramePublisher starts publishing the recorded frames with all subscribed clients.
 func (c *camera) startFramePublisher() {
 	go func() {
@@ -151,17 +360,59 @@ func (c *camera) startFramePublisher() {
 			case <-c.stopPublisherCh:
 				// stop signal received, exit the publisher
 				return
-			case frame, ok := <-c.outputCh:
+			case data, ok := <-c.outputCh:
 				if !ok {
 					// channel was closed --> camera was shut down in the meanwhile
 					return
 				}
-				if c.subscriptionHandler.Subscriptions() > 0 {
-					c.subscriptionHandler.Publish(frame)
+				if c.hasSubscribers() {
+					c.publish(frame.Frame{
+						CameraID:  c.id,
+						Data:      data,
+						PixFormat: c.device.GetPixFormat(),
+						Timestamp: time.Now(),
+					})
 				} else {
 					// --> no subscribed clients
 				}
+			case f, ok := <-c.sourceCh:
+				if !ok {
+					// channel was closed --> virtual camera was stopped in the meanwhile
+					return
+				}
+				if c.hasSubscribers() {
+					f.CameraID = c.id
+					c.publish(f)
+				}
 			}
 		}
 	}()
 }
+
+func (c *camera) hasSubscribers() bool {
+	return c.subscriptionHandler.Subscriptions() > 0 || c.motion.subscriptionHandler.Subscriptions() > 0 || c.overlay.subscriptionHandler.Subscriptions() > 0
+}
+
+// publish publishes a frame with the subscribed clients and the motion detector. The region of interest is cropped in software if necessary.
+func (c *camera) publish(f frame.Frame) {
+	c.roiMu.RLock()
+	softwareCrop, roi := c.softwareCrop, c.roi
+	c.roiMu.RUnlock()
+	if softwareCrop && !f.IsEmpty() {
+		croppedFrame, err := cropFrame(f, roi)
+		if err != nil {
+			c.logger.PrintlnErr(err)
+			return
+		}
+		f = croppedFrame
+	}
+	if c.subscriptionHandler.Subscriptions() > 0 {
+		c.subscriptionHandler.Publish(f)
+	}
+	if c.motion.subscriptionHandler.Subscriptions() > 0 {
+		c.motion.feed(f)
+	}
+	if c.overlay.subscriptionHandler.Subscriptions() > 0 {
+		c.overlay.feed(f)
+	}
+}
@@ -10,6 +10,7 @@ type config struct {
 	bufSize   uint32
 	fps       uint32
 	bufType   uint32
+	cropRect  v4l2.Rect
 }
 
 type Option func(*config)
@@ -19,3 +20,11 @@ func WithPixFormat(pixFmt v4l2.PixFormat) Option {
 		o.pixFormat = pixFmt
 	}
 }
+
+// WithCropRect sets a region of interest, that is applied via hardware cropping when the device is opened.
+// If the driver rejects the cropping, the device is opened with the full frame (empty crop rectangle in the device info).
+func WithCropRect(r v4l2.Rect) Option {
+	return func(o *config) {
+		o.cropRect = r
+	}
+}
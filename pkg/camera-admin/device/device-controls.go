@@ -0,0 +1,50 @@
+package device
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// Controls returns all enabled user controls of the device (e.g. brightness, exposure) with their current values.
+func (d *Device) Controls() ([]v4l2.Control, error) {
+	controls, err := v4l2.QueryControls(d.fd)
+	if err != nil {
+		return nil, fmt.Errorf("device: controls: %w", err)
+	}
+	return controls, nil
+}
+
+// Control returns the control with the given name. The name is matched case-insensitive,
+// spaces and underscores are ignored (e.g. "white_balance_automatic" matches "White Balance, Automatic").
+func (d *Device) Control(name string) (v4l2.Control, error) {
+	controls, err := d.Controls()
+	if err != nil {
+		return v4l2.Control{}, err
+	}
+	for _, c := range controls {
+		if normalizeControlName(c.Name) == normalizeControlName(name) {
+			return c, nil
+		}
+	}
+	return v4l2.Control{}, fmt.Errorf("device: control %q: %w", name, v4l2.ErrorUnsupportedFeature)
+}
+
+// SetControl sets the value of a control. The value is validated against the control's range.
+func (d *Device) SetControl(control v4l2.Control, value int32) error {
+	if control.Flags&v4l2.CtrlFlagReadOnly != 0 {
+		return fmt.Errorf("device: set control %q: control is read-only", control.Name)
+	}
+	if control.Type != v4l2.CtrlTypeButton && (value < control.Minimum || value > control.Maximum) {
+		return fmt.Errorf("device: set control %q: value %d out of range [%d, %d]", control.Name, value, control.Minimum, control.Maximum)
+	}
+	if err := v4l2.SetControlValue(d.fd, control.ID, value); err != nil {
+		return fmt.Errorf("device: set control %q: %w", control.Name, err)
+	}
+	return nil
+}
+
+func normalizeControlName(name string) string {
+	return strings.NewReplacer(" ", "", "_", "", ",", "", "-", "").Replace(strings.ToLower(name))
+}
@@ -0,0 +1,48 @@
+package device
+
+import (
+	"fmt"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// FormatSupport describes a pixel format supported by the device with its frame sizes and frame intervals.
+type FormatSupport struct {
+	Description v4l2.FormatDescription
+	FrameSizes  []FrameSizeSupport
+}
+
+// FrameSizeSupport describes a frame size with its supported frame intervals.
+type FrameSizeSupport struct {
+	Size      v4l2.FrameSize
+	Intervals []v4l2.FrameInterval
+}
+
+// Formats enumerates all pixel formats, frame sizes and frame intervals supported by the device.
+func (d *Device) Formats() ([]FormatSupport, error) {
+	descriptions, err := v4l2.GetFormatDescriptions(d.fd, d.bufType)
+	if err != nil {
+		return nil, fmt.Errorf("device: formats: %w", err)
+	}
+
+	var formats []FormatSupport
+	for _, desc := range descriptions {
+		format := FormatSupport{Description: desc}
+		sizes, err := v4l2.GetFrameSizes(d.fd, desc.PixelFormat)
+		if err != nil {
+			return nil, fmt.Errorf("device: formats: %w", err)
+		}
+		for _, size := range sizes {
+			sizeSupport := FrameSizeSupport{Size: size}
+			if size.Type == v4l2.FrameSizeTypeDiscrete {
+				sizeSupport.Intervals, err = v4l2.GetFrameIntervals(d.fd, desc.PixelFormat, size.MaxWidth, size.MaxHeight)
+				if err != nil {
+					return nil, fmt.Errorf("device: formats: %w", err)
+				}
+			}
+			format.FrameSizes = append(format.FrameSizes, sizeSupport)
+		}
+		formats = append(formats, format)
+	}
+	return formats, nil
+}
@@ -0,0 +1,65 @@
+package device
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// Info is a human-readable summary of an opened device.
+type Info struct {
+	Path          string    `json:"path"`
+	Driver        string    `json:"driver"`
+	Card          string    `json:"card"`
+	BusInfo       string    `json:"busInfo"`
+	DriverVersion string    `json:"driverVersion"`
+	Capabilities  []string  `json:"capabilities"`
+	PixelFormat   string    `json:"pixelFormat"`
+	Width         uint32    `json:"width"`
+	Height        uint32    `json:"height"`
+	BytesPerLine  uint32    `json:"bytesPerLine"`
+	SizeImage     uint32    `json:"sizeImage"`
+	Field         string    `json:"field"`
+	Colorspace    string    `json:"colorspace"`
+	FPS           uint32    `json:"fps"`
+	CropRect      v4l2.Rect `json:"cropRect"`
+	Streaming     bool      `json:"streaming"`
+}
+
+// Info returns a human-readable summary of the device (capabilities and active format).
+func (d *Device) Info() Info {
+	d.formatMu.RLock()
+	pixFmt, cropRect := d.config.pixFormat, d.config.cropRect
+	d.formatMu.RUnlock()
+	return Info{
+		Path:          d.path,
+		Driver:        d.cap.Driver,
+		Card:          d.cap.Card,
+		BusInfo:       d.cap.BusInfo,
+		DriverVersion: d.cap.DriverVersion(),
+		Capabilities:  d.cap.CapabilityDescriptions(),
+		PixelFormat:   pixFmt.PixelFormat.String(),
+		Width:         pixFmt.Width,
+		Height:        pixFmt.Height,
+		BytesPerLine:  pixFmt.BytesPerLine,
+		SizeImage:     pixFmt.SizeImage,
+		Field:         pixFmt.Field.String(),
+		Colorspace:    pixFmt.Colorspace.String(),
+		FPS:           d.config.fps,
+		CropRect:      cropRect,
+		Streaming:     d.streaming,
+	}
+}
+
+// String returns a multi-line summary of the device for logging purposes.
+func (i Info) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s (driver: %s %s, bus: %s)\n", i.Path, i.Card, i.Driver, i.DriverVersion, i.BusInfo)
+	fmt.Fprintf(&sb, "\tcapabilities: %s\n", strings.Join(i.Capabilities, ", "))
+	fmt.Fprintf(&sb, "\tformat: %s %dx%d @ %d fps, field: %s, colorspace: %s", i.PixelFormat, i.Width, i.Height, i.FPS, i.Field, i.Colorspace)
+	if i.CropRect != (v4l2.Rect{}) {
+		fmt.Fprintf(&sb, "\n\tcrop: %dx%d at (%d, %d)", i.CropRect.Width, i.CropRect.Height, i.CropRect.Left, i.CropRect.Top)
+	}
+	return sb.String()
+}
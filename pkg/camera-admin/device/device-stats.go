@@ -0,0 +1,94 @@
+package device
+
+import (
+	"sync"
+	"time"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+	sys "golang.org/x/sys/unix"
+)
+
+// Stats are the capture statistics of a streaming device.
+type Stats struct {
+	Since         time.Time     `json:"since"`
+	Frames        uint64        `json:"frames"`
+	ErrorFrames   uint64        `json:"errorFrames"`
+	DroppedFrames uint64        `json:"droppedFrames"` // gaps in the frame sequence numbers of the driver
+	LatencySum    time.Duration `json:"latencySum"`    // sum of the time between capture (driver timestamp) and dequeue
+	MaxLatency    time.Duration `json:"maxLatency"`
+}
+
+// FPS returns the average frame rate since the statistics were reset.
+func (s Stats) FPS() float64 {
+	elapsed := time.Since(s.Since).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Frames) / elapsed
+}
+
+// AvgLatency returns the average time between capture and dequeue of a frame.
+func (s Stats) AvgLatency() time.Duration {
+	if s.Frames == 0 {
+		return 0
+	}
+	return s.LatencySum / time.Duration(s.Frames)
+}
+
+type statsRecorder struct {
+	mu           sync.Mutex
+	stats        Stats
+	lastSequence uint32
+	hasSequence  bool
+}
+
+// record records a dequeued buffer.
+func (sr *statsRecorder) record(buff v4l2.Buffer) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.stats.Frames++
+	if buff.Flags&v4l2.BufFlagError != 0 {
+		sr.stats.ErrorFrames++
+	}
+	if sr.hasSequence && buff.Sequence > sr.lastSequence+1 {
+		sr.stats.DroppedFrames += uint64(buff.Sequence - sr.lastSequence - 1)
+	}
+	sr.lastSequence = buff.Sequence
+	sr.hasSequence = true
+
+	// the driver timestamps are taken from the monotonic clock
+	if buff.Timestamp.Sec != 0 || buff.Timestamp.Usec != 0 {
+		var now sys.Timespec
+		if err := sys.ClockGettime(sys.CLOCK_MONOTONIC, &now); err == nil {
+			latency := time.Duration(now.Nano() - buff.Timestamp.Nano())
+			sr.stats.LatencySum += latency
+			if latency > sr.stats.MaxLatency {
+				sr.stats.MaxLatency = latency
+			}
+		}
+	}
+}
+
+func (sr *statsRecorder) get() Stats {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	return sr.stats
+}
+
+func (sr *statsRecorder) reset() {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.stats = Stats{Since: time.Now()}
+	sr.hasSequence = false
+}
+
+// Stats returns the capture statistics of the device since streaming started or the statistics were reset.
+func (d *Device) Stats() Stats {
+	return d.stats.get()
+}
+
+// ResetStats resets the capture statistics of the device.
+func (d *Device) ResetStats() {
+	d.stats.reset()
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 	sys "syscall"
 
 	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
@@ -16,12 +17,14 @@ type Device struct {
 	path         string
 	fd           uintptr
 	config       config
+	formatMu     sync.RWMutex // guards the pixel format and the crop rectangle of the config (changed while streaming)
 	bufType      v4l2.BufType
 	cap          v4l2.Capability
 	buffers      [][]byte
 	requestedBuf v4l2.RequestBuffers
 	streaming    bool
 	output       chan []byte
+	stats        statsRecorder
 }
 
 // Open opens the underlying device at specified path for streaming.
@@ -82,11 +85,7 @@ func Open(path string, options ...Option) (*Device, error) {
 	dev.config.ioType = v4l2.IOTypeMMAP
 
 	// Reset crop, only if cropping supported
-	if cropcap, err := v4l2.GetCropCapability(dev.fd, dev.bufType); err == nil {
-		if err := v4l2.SetCropRect(dev.fd, cropcap.DefaultRect); err != nil {
-			// ignore errors
-		}
-	}
+	dev.ResetCropRect()
 
 	// set pix format
 	if !reflect.ValueOf(dev.config.pixFormat).IsZero() {
@@ -100,6 +99,14 @@ func Open(path string, options ...Option) (*Device, error) {
 		}
 	}
 
+	// set crop (region of interest) --> if the driver rejects it, the device streams the full frame and the caller has to
+	// crop in software (the crop rectangle of the device info stays empty)
+	if !reflect.ValueOf(dev.config.cropRect).IsZero() {
+		if _, err := dev.SetCropRect(dev.config.cropRect); err != nil {
+			dev.config.cropRect = v4l2.Rect{}
+		}
+	}
+
 	// set fps
 	if !reflect.ValueOf(dev.config.fps).IsZero() {
 		if err := dev.SetFrameRate(dev.config.fps); err != nil {
@@ -141,6 +148,7 @@ func (d *Device) Start(ctx context.Context) error {
 		return fmt.Errorf("device: make mapped buffers: %s", err)
 	}
 
+	d.stats.reset()
 	if err := d.startStreamLoop(ctx); err != nil {
 		return fmt.Errorf("device: start stream loop: %s", err)
 	}
@@ -219,10 +227,72 @@ func (d *Device) SetPixFormat(pixFmt v4l2.PixFormat) error {
 	if err := v4l2.SetPixFormat(d.fd, pixFmt); err != nil {
 		return fmt.Errorf("device: %w", err)
 	}
+	d.formatMu.Lock()
 	d.config.pixFormat = pixFmt
+	d.formatMu.Unlock()
 	return nil
 }
 
+// SetCropRect sets the hardware cropping rectangle (region of interest) of the device.
+// The selection API (VIDIOC_S_SELECTION) is preferred, the legacy crop API (VIDIOC_S_CROP) is used as fallback.
+// The driver may adjust the rectangle, the applied rectangle is returned. An error is returned if the
+// driver does not support cropping at all.
+func (d *Device) SetCropRect(r v4l2.Rect) (v4l2.Rect, error) {
+	if !d.cap.IsVideoCaptureSupported() {
+		return v4l2.Rect{}, v4l2.ErrorUnsupportedFeature
+	}
+
+	applied, err := v4l2.SetSelection(d.fd, d.bufType, v4l2.SelectionTargetCrop, v4l2.SelectionFlagLE, r)
+	if err != nil {
+		// --> selection api not supported by the driver --> try legacy crop api
+		if err := v4l2.SetCropRect(d.fd, r); err != nil {
+			return v4l2.Rect{}, fmt.Errorf("device: set crop: %w", err)
+		}
+		applied = r
+	}
+
+	// cropping may change the format of the device
+	d.formatMu.Lock()
+	defer d.formatMu.Unlock()
+	if pixFmt, err := v4l2.GetPixFormat(d.fd); err == nil {
+		d.config.pixFormat = pixFmt
+	}
+	d.config.cropRect = applied
+	return applied, nil
+}
+
+// GetCropRect returns the active hardware cropping rectangle of the device.
+func (d *Device) GetCropRect() (v4l2.Rect, error) {
+	r, err := v4l2.GetSelection(d.fd, d.bufType, v4l2.SelectionTargetCrop)
+	if err != nil {
+		return v4l2.Rect{}, fmt.Errorf("device: get crop: %w", err)
+	}
+	return r, nil
+}
+
+// GetPixFormat returns the active pixel format of the device.
+func (d *Device) GetPixFormat() v4l2.PixFormat {
+	d.formatMu.RLock()
+	defer d.formatMu.RUnlock()
+	return d.config.pixFormat
+}
+
+// ResetCropRect resets the cropping rectangle to the driver's default, only if cropping is supported.
+func (d *Device) ResetCropRect() {
+	if defaultRect, err := v4l2.GetSelection(d.fd, d.bufType, v4l2.SelectionTargetCropDefault); err == nil {
+		if _, err := v4l2.SetSelection(d.fd, d.bufType, v4l2.SelectionTargetCrop, 0, defaultRect); err == nil {
+			return
+		}
+	}
+
+	// --> selection api not supported by the driver --> try legacy crop api
+	if cropcap, err := v4l2.GetCropCapability(d.fd, d.bufType); err == nil {
+		if err := v4l2.SetCropRect(d.fd, cropcap.DefaultRect); err != nil {
+			// ignore errors
+		}
+	}
+}
+
 // GetStreamParam returns streaming parameter information for device
 func (d *Device) GetStreamParam() (v4l2.StreamParam, error) {
 	if !d.cap.IsVideoCaptureSupported() && d.cap.IsVideoOutputSupported() {
@@ -331,6 +401,8 @@ func (d *Device) startStreamLoop(ctx context.Context) error {
 					panic(fmt.Sprintf("device: stream loop dequeue: %s", err))
 				}
 
+				d.stats.record(buff)
+
 				// copy mapped buffer (copying avoids polluted data from subsequent dequeue ops)
 				if buff.Flags&v4l2.BufFlagMapped != 0 && buff.Flags&v4l2.BufFlagError == 0 {
 					frame = make([]byte, buff.BytesUsed)
@@ -0,0 +1,50 @@
+package device
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	sys "syscall"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// Description describes a discovered video device.
+type Description struct {
+	Path       string
+	Capability v4l2.Capability
+}
+
+// Discover lists all video devices (/dev/video*) that support video capture via streaming IO.
+// Device nodes that only provide metadata (e.g. the second node of an uvc camera) are skipped.
+func Discover() ([]Description, error) {
+	paths, err := filepath.Glob("/dev/video*")
+	if err != nil {
+		return nil, fmt.Errorf("device discover: %w", err)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		// sort numerically: /dev/video2 before /dev/video10
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) < len(paths[j])
+		}
+		return paths[i] < paths[j]
+	})
+
+	var descriptions []Description
+	for _, path := range paths {
+		fd, err := v4l2.OpenDevice(path, sys.O_RDWR|sys.O_NONBLOCK, 0)
+		if err != nil {
+			// --> device busy or not accessible
+			continue
+		}
+		cap, err := v4l2.GetCapability(fd)
+		v4l2.CloseDevice(fd)
+		if err != nil {
+			continue
+		}
+		if cap.IsVideoCaptureSupported() && cap.IsStreamingSupported() {
+			descriptions = append(descriptions, Description{Path: path, Capability: cap})
+		}
+	}
+	return descriptions, nil
+}
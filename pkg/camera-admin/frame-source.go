@@ -0,0 +1,41 @@
+package cameraadmin
+
+import (
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// FrameSource is a virtual camera that delivers frames instead of a V4L2 device (e.g. a simulator or a recording).
+type FrameSource interface {
+	// Start starts the source with the requested resolution and returns the channel of the produced frames.
+	Start(width, height int) (<-chan frame.Frame, error)
+	// Stop stops the source and closes the frame channel.
+	Stop()
+}
+
+// WithFrameSources replaces the default cameras by virtual cameras. The camera-ids are assigned in order, starting with 1.
+// This option has to be applied before camera specific options (e.g. WithROI).
+func WithFrameSources(sources ...FrameSource) Option {
+	return func(ca *cameraAdmin) {
+		ca.cameras = nil
+		for i, source := range sources {
+			c := newCamera(i+1, "virtual", ca.logger)
+			c.source = source
+			ca.cameras = append(ca.cameras, c)
+		}
+	}
+}
+
+// startSource starts a virtual camera. The region of interest is applied in software.
+func (ca *cameraAdmin) startSource(c *camera, width, height int) error {
+	sourceCh, err := c.source.Start(width, height)
+	if err != nil {
+		return err
+	}
+	c.sourceCh = sourceCh
+	c.roiMu.Lock()
+	c.softwareCrop = c.roi != (v4l2.Rect{})
+	c.roiMu.Unlock()
+	ca.logger.Printf("camera %d: virtual camera started (%dx%d)", c.id, width, height)
+	return nil
+}
@@ -0,0 +1,41 @@
+package frame
+
+import (
+	"image"
+	"time"
+
+	pixelconverter "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/pixel-converter"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// Frame is a single frame recorded by a camera in the camera's native pixel format.
+type Frame struct {
+	CameraID  int
+	Data      []byte
+	PixFormat v4l2.PixFormat
+	Timestamp time.Time
+}
+
+// Image converts the frame into an image, independent of the camera's native pixel format.
+func (f Frame) Image() (image.Image, error) {
+	return pixelconverter.ToImage(f.Data, f.PixFormat)
+}
+
+// Gray converts the frame into a grayscale (luma) plane, independent of the camera's native pixel format.
+func (f Frame) Gray() (*image.Gray, error) {
+	return pixelconverter.ToGray(f.Data, f.PixFormat)
+}
+
+// GrayScaled converts the frame into a grayscale plane that is downscaled by an integer factor.
+func (f Frame) GrayScaled(factor int) (*image.Gray, error) {
+	gray, err := f.Gray()
+	if err != nil {
+		return nil, err
+	}
+	return pixelconverter.DownscaleGray(gray, factor), nil
+}
+
+// IsEmpty returns true if the frame holds no data (e.g. the driver reported a buffer error).
+func (f Frame) IsEmpty() bool {
+	return len(f.Data) == 0
+}
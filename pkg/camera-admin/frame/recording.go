@@ -0,0 +1,51 @@
+package frame
+
+import (
+	"bytes"
+	"fmt"
+	"image/jpeg"
+	"io"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// jpeg start of image marker (followed by the marker of the first segment)
+var jpegStart = []byte{0xFF, 0xD8, 0xFF}
+
+// ReadMJPEG reads a recorded mjpeg stream (concatenated jpeg frames, e.g. recorded by cir-camera record) into frames.
+// The frames have no timestamps.
+func ReadMJPEG(r io.Reader, cameraID int) ([]Frame, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("ReadMJPEG() - error: %v", err)
+	}
+
+	var frames []Frame
+	for len(data) > 0 {
+		if !bytes.HasPrefix(data, jpegStart) {
+			return nil, fmt.Errorf("ReadMJPEG() - error: frame %d: missing jpeg start marker", len(frames))
+		}
+		end := bytes.Index(data[len(jpegStart):], jpegStart)
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += len(jpegStart)
+		}
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(data[:end]))
+		if err != nil {
+			return nil, fmt.Errorf("ReadMJPEG() - error: frame %d: %v", len(frames), err)
+		}
+		frames = append(frames, Frame{
+			CameraID: cameraID,
+			Data:     data[:end],
+			PixFormat: v4l2.PixFormat{
+				Width:       uint32(config.Width),
+				Height:      uint32(config.Height),
+				PixelFormat: v4l2.PixelFmtMJPEG,
+			},
+		})
+		data = data[end:]
+	}
+	return frames, nil
+}
@@ -0,0 +1,280 @@
+package motiondetector
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"time"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+)
+
+type EventType string
+
+const (
+	// MotionStarted is emitted when the frames start to differ from each other (e.g. a dart is flying in or a hand is in the picture).
+	MotionStarted EventType = "motion-started"
+	// MotionSettled is emitted when the frames are still again after a motion.
+	MotionSettled EventType = "motion-settled"
+	// PersistentChange is emitted after MotionSettled if the still picture differs from the reference before the motion (e.g. a dart landed).
+	PersistentChange EventType = "persistent-change"
+)
+
+// Event is a motion event of a camera. Before is the last still frame before the motion started, After the first still frame after the motion.
+// Both frames and the motion ratio are only set for MotionSettled and PersistentChange events. A dart changes a small
+// area during the motion, a hand or a player a large one.
+type Event struct {
+	CameraID     int       `json:"cameraId"`
+	Type         EventType `json:"type"`
+	Timestamp    time.Time `json:"timestamp"`
+	ChangedRatio float64   `json:"changedRatio"` // ratio of changed pixels inside the roi
+	MotionRatio  float64   `json:"motionRatio"`  // largest ratio of changed pixels between two frames during the motion
+	Before       frame.Frame
+	After        frame.Frame
+}
+
+type Config struct {
+	// Scale is the downscale factor of the frames before comparing them
+	Scale int
+	// ROI is the region of interest inside the frame in frame pixels. An empty rectangle means the full frame.
+	ROI image.Rectangle
+	// PixelThreshold is the minimum luma difference of a pixel to count as changed
+	PixelThreshold uint8
+	// MotionRatio is the minimum ratio of changed pixels between two consecutive frames to count as motion
+	MotionRatio float64
+	// SettleFrames is the amount of consecutive still frames after which a motion counts as settled
+	SettleFrames int
+	// ChangeRatio is the minimum ratio of changed pixels between the reference and the settled frame to count as persistent change
+	ChangeRatio float64
+	// BackgroundRate is the blending rate of the rolling reference background while the picture is still (adapts to light changes)
+	BackgroundRate float64
+}
+
+// DefaultConfig returns a configuration that works for a 1920x1080 camera that looks at a dartboard.
+func DefaultConfig() Config {
+	return Config{
+		Scale:          4,
+		PixelThreshold: 25,
+		MotionRatio:    0.002,
+		SettleFrames:   5,
+		ChangeRatio:    0.0003,
+		BackgroundRate: 0.05,
+	}
+}
+
+type Detector struct {
+	cameraID    int
+	config      Config
+	reference   []float32 // rolling reference background
+	previous    *image.Gray
+	stillFrame  frame.Frame // last still frame
+	beforeFrame frame.Frame // last still frame before the current motion
+	moving      bool
+	stillFrames int
+	peakRatio   float64 // largest motion ratio of the current motion
+}
+
+// NewDetector returns a new motion detector for a camera. Zero values of the config are replaced by the default values.
+func NewDetector(cameraID int, config Config) *Detector {
+	defaults := DefaultConfig()
+	if config.Scale == 0 {
+		config.Scale = defaults.Scale
+	}
+	if config.PixelThreshold == 0 {
+		config.PixelThreshold = defaults.PixelThreshold
+	}
+	if config.MotionRatio == 0 {
+		config.MotionRatio = defaults.MotionRatio
+	}
+	if config.SettleFrames == 0 {
+		config.SettleFrames = defaults.SettleFrames
+	}
+	if config.ChangeRatio == 0 {
+		config.ChangeRatio = defaults.ChangeRatio
+	}
+	if config.BackgroundRate == 0 {
+		config.BackgroundRate = defaults.BackgroundRate
+	}
+	return &Detector{
+		cameraID: cameraID,
+		config:   config,
+	}
+}
+
+// Config returns the configuration of the detector.
+func (d *Detector) Config() Config {
+	return d.config
+}
+
+// Reset drops the reference background. The next processed frame becomes the new reference.
+func (d *Detector) Reset() {
+	d.reference = nil
+	d.previous = nil
+	d.moving = false
+	d.stillFrames = 0
+}
+
+// IsMoving returns true if a motion is in progress.
+func (d *Detector) IsMoving() bool {
+	return d.moving
+}
+
+// Reference returns the current reference background (downscaled roi).
+func (d *Detector) Reference() *image.Gray {
+	if d.previous == nil {
+		return nil
+	}
+	reference := image.NewGray(d.previous.Bounds())
+	for i, v := range d.reference {
+		reference.Pix[i] = uint8(v + 0.5)
+	}
+	return reference
+}
+
+// Process compares a frame with the previous frame and the reference background and returns the resulting events.
+func (d *Detector) Process(f frame.Frame) ([]Event, error) {
+	if f.IsEmpty() {
+		return nil, nil
+	}
+	gray, err := f.GrayScaled(d.config.Scale)
+	if err != nil {
+		return nil, fmt.Errorf("Process() - error: converting frame (camera-id: %d): %v", d.cameraID, err)
+	}
+	gray = d.crop(gray)
+
+	// first frame or the frame size has changed --> new reference
+	if d.previous == nil || d.previous.Bounds() != gray.Bounds() {
+		d.Reset()
+		d.setReference(gray)
+		d.previous = gray
+		d.stillFrame = f
+		return nil, nil
+	}
+
+	var events []Event
+	motionRatio := changedRatio(gray.Pix, d.previous.Pix, d.config.PixelThreshold)
+	d.previous = gray
+
+	if !d.moving {
+		if motionRatio > d.config.MotionRatio {
+			d.moving = true
+			d.stillFrames = 0
+			d.peakRatio = motionRatio
+			d.beforeFrame = d.stillFrame
+			events = append(events, d.newEvent(MotionStarted, f, motionRatio))
+			return events, nil
+		}
+		d.updateReference(gray)
+		d.stillFrame = f
+		return nil, nil
+	}
+
+	// --> motion in progress --> wait until the picture is still for some frames
+	d.peakRatio = math.Max(d.peakRatio, motionRatio)
+	if motionRatio > d.config.MotionRatio {
+		d.stillFrames = 0
+		return nil, nil
+	}
+	d.stillFrames++
+	if d.stillFrames < d.config.SettleFrames {
+		return nil, nil
+	}
+
+	d.moving = false
+	d.stillFrame = f
+	referenceRatio := changedRatioFloat(gray.Pix, d.reference, d.config.PixelThreshold)
+	settled := d.newEvent(MotionSettled, f, referenceRatio)
+	settled.Before = d.beforeFrame
+	settled.After = f
+	settled.MotionRatio = d.peakRatio
+	events = append(events, settled)
+
+	if referenceRatio > d.config.ChangeRatio {
+		change := settled
+		change.Type = PersistentChange
+		events = append(events, change)
+	}
+
+	// the settled picture is the new reference
+	d.setReference(gray)
+	return events, nil
+}
+
+func (d *Detector) newEvent(eventType EventType, f frame.Frame, ratio float64) Event {
+	timestamp := f.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	return Event{
+		CameraID:     d.cameraID,
+		Type:         eventType,
+		Timestamp:    timestamp,
+		ChangedRatio: ratio,
+	}
+}
+
+// crop crops the downscaled frame to the roi.
+func (d *Detector) crop(gray *image.Gray) *image.Gray {
+	if d.config.ROI.Empty() {
+		return gray
+	}
+	scale := d.config.Scale
+	if scale < 1 {
+		scale = 1
+	}
+	roi := image.Rect(d.config.ROI.Min.X/scale, d.config.ROI.Min.Y/scale, d.config.ROI.Max.X/scale, d.config.ROI.Max.Y/scale).Intersect(gray.Bounds())
+	if roi.Empty() {
+		return gray
+	}
+	cropped := image.NewGray(image.Rect(0, 0, roi.Dx(), roi.Dy()))
+	for y := 0; y < roi.Dy(); y++ {
+		offset := gray.PixOffset(roi.Min.X, roi.Min.Y+y)
+		copy(cropped.Pix[y*cropped.Stride:(y+1)*cropped.Stride], gray.Pix[offset:offset+roi.Dx()])
+	}
+	return cropped
+}
+
+func (d *Detector) setReference(gray *image.Gray) {
+	d.reference = make([]float32, len(gray.Pix))
+	for i, v := range gray.Pix {
+		d.reference[i] = float32(v)
+	}
+}
+
+// updateReference blends the still frame into the rolling reference background.
+func (d *Detector) updateReference(gray *image.Gray) {
+	rate := float32(d.config.BackgroundRate)
+	for i, v := range gray.Pix {
+		d.reference[i] += (float32(v) - d.reference[i]) * rate
+	}
+}
+
+// changedRatio returns the ratio of pixels that differ more than the threshold.
+func changedRatio(a, b []uint8, threshold uint8) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	changed := 0
+	for i := range a {
+		diff := int(a[i]) - int(b[i])
+		if diff > int(threshold) || -diff > int(threshold) {
+			changed++
+		}
+	}
+	return float64(changed) / float64(len(a))
+}
+
+// changedRatioFloat returns the ratio of pixels that differ more than the threshold from the reference.
+func changedRatioFloat(a []uint8, reference []float32, threshold uint8) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	changed := 0
+	for i := range a {
+		diff := float32(a[i]) - reference[i]
+		if diff > float32(threshold) || -diff > float32(threshold) {
+			changed++
+		}
+	}
+	return float64(changed) / float64(len(a))
+}
@@ -0,0 +1,232 @@
+package motiondetector
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+const (
+	testWidth  = 40
+	testHeight = 30
+)
+
+var testStart = time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+
+// testConfig compares the frames without downscaling. 10 changed pixels (of 1200) count as motion or persistent change.
+func testConfig() Config {
+	return Config{
+		Scale:          1,
+		PixelThreshold: 25,
+		MotionRatio:    0.005,
+		SettleFrames:   3,
+		ChangeRatio:    0.005,
+		BackgroundRate: 0.05,
+	}
+}
+
+// grayFrame returns a GREY frame with a background of 100 and the hand-overed rectangles filled with the luma.
+func grayFrame(seq int, luma uint8, rects ...image.Rectangle) frame.Frame {
+	data := make([]byte, testWidth*testHeight)
+	for i := range data {
+		data[i] = 100
+	}
+	for _, rect := range rects {
+		rect = rect.Intersect(image.Rect(0, 0, testWidth, testHeight))
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				data[y*testWidth+x] = luma
+			}
+		}
+	}
+	return frame.Frame{
+		CameraID:  1,
+		Data:      data,
+		PixFormat: v4l2.PixFormat{Width: testWidth, Height: testHeight, PixelFormat: v4l2.PixelFmtGrey},
+		Timestamp: testStart.Add(time.Duration(seq) * 100 * time.Millisecond),
+	}
+}
+
+// the frames of a test sequence
+var (
+	empty = func(seq int) frame.Frame { return grayFrame(seq, 100) }
+	// dart is a small dark area in the board (15 pixels)
+	dart     = func(seq int) frame.Frame { return grayFrame(seq, 20, image.Rect(10, 10, 13, 15)) }
+	twoDarts = func(seq int) frame.Frame {
+		return grayFrame(seq, 20, image.Rect(10, 10, 13, 15), image.Rect(30, 20, 33, 25))
+	}
+	// hand is a large dark area at the hand-overed column
+	hand = func(x int) func(seq int) frame.Frame {
+		return func(seq int) frame.Frame { return grayFrame(seq, 20, image.Rect(x, 0, x+10, testHeight)) }
+	}
+	// faint differs from the background less than the pixel threshold
+	faint = func(seq int) frame.Frame { return grayFrame(seq, 110, image.Rect(0, 0, testWidth, testHeight)) }
+	// speck changes less pixels than the motion ratio
+	speck = func(seq int) frame.Frame { return grayFrame(seq, 20, image.Rect(0, 0, 2, 2)) }
+)
+
+// process feeds the frames into the detector and returns the types of the events and the events.
+func process(t *testing.T, d *Detector, frames []func(seq int) frame.Frame) ([]EventType, []Event) {
+	t.Helper()
+	var types []EventType
+	var events []Event
+	for seq, f := range frames {
+		frameEvents, err := d.Process(f(seq))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, event := range frameEvents {
+			types = append(types, event.Type)
+			events = append(events, event)
+		}
+	}
+	return types, events
+}
+
+func repeat(f func(seq int) frame.Frame, n int) []func(seq int) frame.Frame {
+	frames := make([]func(seq int) frame.Frame, n)
+	for i := range frames {
+		frames[i] = f
+	}
+	return frames
+}
+
+func sequence(parts ...[]func(seq int) frame.Frame) []func(seq int) frame.Frame {
+	var frames []func(seq int) frame.Frame
+	for _, part := range parts {
+		frames = append(frames, part...)
+	}
+	return frames
+}
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames []func(seq int) frame.Frame
+		want   []EventType
+	}{
+		{
+			name:   "still board",
+			frames: repeat(empty, 10),
+		},
+		{
+			name:   "dart lands",
+			frames: sequence(repeat(empty, 3), repeat(dart, 4)),
+			want:   []EventType{MotionStarted, MotionSettled, PersistentChange},
+		},
+		{
+			name:   "not settled yet",
+			frames: sequence(repeat(empty, 3), repeat(dart, 3)),
+			want:   []EventType{MotionStarted},
+		},
+		{
+			name:   "hand passes",
+			frames: sequence(repeat(empty, 3), []func(seq int) frame.Frame{hand(0), hand(10), hand(20), hand(30)}, repeat(empty, 4)),
+			want:   []EventType{MotionStarted, MotionSettled},
+		},
+		{
+			// --> the hand rests for 2 still frames, the next move restarts the settle time
+			name:   "motion restarts the settle time",
+			frames: sequence(repeat(empty, 3), repeat(hand(0), 3), repeat(hand(10), 3)),
+			want:   []EventType{MotionStarted},
+		},
+		{
+			name:   "hand rests",
+			frames: sequence(repeat(empty, 3), repeat(hand(0), 3), repeat(hand(10), 4)),
+			want:   []EventType{MotionStarted, MotionSettled, PersistentChange},
+		},
+		{
+			name:   "below the pixel threshold",
+			frames: sequence(repeat(empty, 3), repeat(faint, 5)),
+		},
+		{
+			name:   "below the motion ratio",
+			frames: sequence(repeat(empty, 3), repeat(speck, 5)),
+		},
+		{
+			// --> the settled dart is the new reference, the next dart is a new persistent change
+			name:   "second dart",
+			frames: sequence(repeat(empty, 3), repeat(dart, 4), repeat(twoDarts, 4)),
+			want:   []EventType{MotionStarted, MotionSettled, PersistentChange, MotionStarted, MotionSettled, PersistentChange},
+		},
+	}
+	for _, tt := range tests {
+		got, _ := process(t, NewDetector(1, testConfig()), tt.frames)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: events %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: events %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// the settled events hold the last still frame before and the first still frame after the motion
+func TestProcessFrames(t *testing.T) {
+	d := NewDetector(1, testConfig())
+	_, events := process(t, d, sequence(repeat(empty, 3), repeat(dart, 4)))
+	if len(events) != 3 {
+		t.Fatalf("%d events, want 3", len(events))
+	}
+
+	started, settled, change := events[0], events[1], events[2]
+	if want := testStart.Add(300 * time.Millisecond); !started.Timestamp.Equal(want) {
+		t.Errorf("motion started at %v, want %v", started.Timestamp, want)
+	}
+	if want := testStart.Add(600 * time.Millisecond); !settled.Timestamp.Equal(want) {
+		t.Errorf("motion settled at %v, want %v", settled.Timestamp, want)
+	}
+	if !settled.Before.Timestamp.Equal(testStart.Add(200*time.Millisecond)) || !settled.After.Timestamp.Equal(settled.Timestamp) {
+		t.Errorf("before frame %v, after frame %v", settled.Before.Timestamp, settled.After.Timestamp)
+	}
+	if change.Before.Timestamp != settled.Before.Timestamp || change.After.Timestamp != settled.After.Timestamp {
+		t.Error("persistent change holds other frames than the settled event")
+	}
+	if want := 15.0 / (testWidth * testHeight); change.ChangedRatio < want*0.99 || change.ChangedRatio > want*1.01 {
+		t.Errorf("changed ratio %f, want %f", change.ChangedRatio, want)
+	}
+	if d.IsMoving() {
+		t.Error("detector still moving after the motion settled")
+	}
+}
+
+// the reference adapts to slow light changes, so they never count as persistent change
+func TestProcessLightChange(t *testing.T) {
+	d := NewDetector(1, testConfig())
+	var frames []func(seq int) frame.Frame
+	for luma := 100; luma <= 160; luma += 2 {
+		luma := uint8(luma)
+		frames = append(frames, repeat(func(seq int) frame.Frame { return grayFrame(seq, luma, image.Rect(0, 0, testWidth, testHeight)) }, 5)...)
+	}
+	if got, _ := process(t, d, frames); len(got) != 0 {
+		t.Errorf("events %v for a slow light change", got)
+	}
+	if reference := d.Reference(); reference.GrayAt(0, 0).Y < 140 {
+		t.Errorf("reference %d did not follow the light change", reference.GrayAt(0, 0).Y)
+	}
+}
+
+func TestProcessROI(t *testing.T) {
+	config := testConfig()
+	config.ROI = image.Rect(20, 0, testWidth, testHeight)
+	// --> the dart is outside of the roi
+	if got, _ := process(t, NewDetector(1, config), sequence(repeat(empty, 3), repeat(dart, 4))); len(got) != 0 {
+		t.Errorf("events %v for a change outside of the roi", got)
+	}
+}
+
+func TestNewDetectorDefaults(t *testing.T) {
+	d := NewDetector(1, Config{PixelThreshold: 10})
+	want := DefaultConfig()
+	want.PixelThreshold = 10
+	if d.Config() != want {
+		t.Errorf("config %+v, want %+v", d.Config(), want)
+	}
+}
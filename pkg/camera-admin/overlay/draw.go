@@ -0,0 +1,55 @@
+package overlay
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// fillRect fills a rectangle (clipped to the image).
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// drawLine draws a line with the hand-overed thickness (clipped to the image).
+func drawLine(img *image.RGBA, x0, y0, x1, y1 float64, thickness int, c color.RGBA) {
+	if math.IsNaN(x0) || math.IsNaN(y0) || math.IsNaN(x1) || math.IsNaN(y1) {
+		return
+	}
+	steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0)))
+	if steps > 4*(img.Rect.Dx()+img.Rect.Dy()) {
+		// --> degenerated projection (e.g. a point behind the camera)
+		return
+	}
+	half := thickness / 2
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		x := int(math.Round(x0 + t*(x1-x0)))
+		y := int(math.Round(y0 + t*(y1-y0)))
+		fillRect(img, image.Rect(x-half, y-half, x-half+thickness, y-half+thickness), c)
+	}
+}
+
+// drawRect draws the outline of a rectangle.
+func drawRect(img *image.RGBA, r image.Rectangle, thickness int, c color.RGBA) {
+	x0, y0, x1, y1 := float64(r.Min.X), float64(r.Min.Y), float64(r.Max.X-1), float64(r.Max.Y-1)
+	drawLine(img, x0, y0, x1, y0, thickness, c)
+	drawLine(img, x1, y0, x1, y1, thickness, c)
+	drawLine(img, x1, y1, x0, y1, thickness, c)
+	drawLine(img, x0, y1, x0, y0, thickness, c)
+}
+
+// drawCross draws a cross marker centered at x, y.
+func drawCross(img *image.RGBA, x, y float64, size int, thickness int, c color.RGBA) {
+	s := float64(size)
+	drawLine(img, x-s, y-s, x+s, y+s, thickness, c)
+	drawLine(img, x-s, y+s, x+s, y-s, thickness, c)
+}
@@ -0,0 +1,134 @@
+package overlay
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"math"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+// overlay colors
+var (
+	colorBoard     = color.RGBA{R: 255, G: 220, B: 0, A: 255}
+	colorROI       = color.RGBA{R: 0, G: 200, B: 255, A: 255}
+	colorDart      = color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	colorUncertain = color.RGBA{R: 255, G: 60, B: 60, A: 255}
+	colorLabelBg   = color.RGBA{R: 0, G: 0, B: 0, A: 255}
+)
+
+// jpegQuality is the quality of the re-encoded overlay frames
+const jpegQuality = 80
+
+// Dart is a detected dart that is drawn onto the frame.
+type Dart struct {
+	Image     calibration.Point // tip in frame pixels
+	Label     string            // score, e.g. "T20"
+	Uncertain bool              // the throw needs a manual confirmation
+}
+
+// Data is the information that is drawn onto the frames of a camera.
+type Data struct {
+	Calibration *calibration.Calibration // board segments are drawn if the camera is calibrated
+	Board       *dartboard.Board         // board geometry, the standard board if nil
+	ROI         image.Rectangle          // region of interest in frame pixels, not drawn if empty
+	Darts       []Dart
+}
+
+// Source returns the overlay data of a camera.
+type Source func(cameraID int) Data
+
+// Render draws the overlay data onto a frame and returns the annotated frame as mjpeg frame.
+func Render(f frame.Frame, data Data) (frame.Frame, error) {
+	src, err := f.Image()
+	if err != nil {
+		return frame.Frame{}, fmt.Errorf("Render() - error: converting frame (camera-id: %d): %v", f.CameraID, err)
+	}
+	bounds := src.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(img, img.Rect, src, bounds.Min, draw.Src)
+
+	// line thickness and font scale grow with the resolution
+	thickness := max(img.Rect.Dx()/640, 1)
+
+	if data.Calibration != nil {
+		board := data.Board
+		if board == nil {
+			board = dartboard.Standard()
+		}
+		drawBoard(img, data.Calibration, board, thickness)
+	}
+	if !data.ROI.Empty() {
+		drawRect(img, data.ROI, thickness, colorROI)
+	}
+	for _, dart := range data.Darts {
+		drawDart(img, dart, thickness)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return frame.Frame{}, fmt.Errorf("Render() - error: encoding jpeg (camera-id: %d): %v", f.CameraID, err)
+	}
+	return frame.Frame{
+		CameraID: f.CameraID,
+		Data:     buf.Bytes(),
+		PixFormat: v4l2.PixFormat{
+			Width:       uint32(img.Rect.Dx()),
+			Height:      uint32(img.Rect.Dy()),
+			PixelFormat: v4l2.PixelFmtMJPEG,
+		},
+		Timestamp: f.Timestamp,
+	}, nil
+}
+
+// drawBoard draws the rings and the segment wires of the board as projected by the calibration.
+func drawBoard(img *image.RGBA, c *calibration.Calibration, board *dartboard.Board, thickness int) {
+	d := board.Dimensions()
+	for _, radius := range []float64{d.InnerBullRadius, d.OuterBullRadius, d.TrebleInnerRadius, d.TrebleOuterRadius, d.DoubleInnerRadius, d.DoubleOuterRadius} {
+		const steps = 180
+		previous := c.BoardToImage(calibration.Point{X: radius})
+		for i := 1; i <= steps; i++ {
+			angle := 2 * math.Pi * float64(i) / steps
+			p := c.BoardToImage(calibration.Point{X: radius * math.Cos(angle), Y: radius * math.Sin(angle)})
+			drawLine(img, previous.X, previous.Y, p.X, p.Y, thickness, colorBoard)
+			previous = p
+		}
+	}
+
+	for _, segment := range dartboard.SegmentOrder {
+		center, err := board.SegmentAngle(segment)
+		if err != nil {
+			continue
+		}
+		// the wire on the clockwise side of the segment
+		angle := (center - 9) * math.Pi / 180
+		from := c.BoardToImage(calibration.Point{X: d.OuterBullRadius * math.Cos(angle), Y: d.OuterBullRadius * math.Sin(angle)})
+		to := c.BoardToImage(calibration.Point{X: d.DoubleOuterRadius * math.Cos(angle), Y: d.DoubleOuterRadius * math.Sin(angle)})
+		drawLine(img, from.X, from.Y, to.X, to.Y, thickness, colorBoard)
+	}
+}
+
+// drawDart marks the tip of a dart and writes its score next to it.
+func drawDart(img *image.RGBA, dart Dart, thickness int) {
+	c := colorDart
+	if dart.Uncertain {
+		c = colorUncertain
+	}
+	drawCross(img, dart.Image.X, dart.Image.Y, 6*thickness, thickness+1, c)
+	if dart.Label == "" {
+		return
+	}
+
+	scale := thickness + 1
+	origin := image.Point{X: int(dart.Image.X) + 8*thickness, Y: int(dart.Image.Y) + 8*thickness}
+	size := textSize(dart.Label, scale)
+	fillRect(img, image.Rectangle{Min: origin, Max: origin.Add(size)}.Inset(-scale), colorLabelBg)
+	drawText(img, origin, dart.Label, scale, c)
+}
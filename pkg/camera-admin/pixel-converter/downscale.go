@@ -0,0 +1,69 @@
+package pixelconverter
+
+import (
+	"image"
+	"image/color"
+)
+
+// DownscaleGray downscales a grayscale image by an integer factor. Each output pixel is the average of a factor x factor block.
+// A factor <= 1 returns the hand-overed image.
+func DownscaleGray(img *image.Gray, factor int) *image.Gray {
+	if factor <= 1 {
+		return img
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx()/factor, bounds.Dy()/factor
+	dst := image.NewGray(image.Rect(0, 0, width, height))
+	blockSize := factor * factor
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sum := 0
+			for by := 0; by < factor; by++ {
+				offset := img.PixOffset(bounds.Min.X+x*factor, bounds.Min.Y+y*factor+by)
+				for _, v := range img.Pix[offset : offset+factor] {
+					sum += int(v)
+				}
+			}
+			dst.Pix[y*dst.Stride+x] = uint8(sum / blockSize)
+		}
+	}
+	return dst
+}
+
+// Downscale downscales an image by an integer factor. Each output pixel is the average of a factor x factor block.
+// A factor <= 1 returns the hand-overed image.
+func Downscale(img image.Image, factor int) image.Image {
+	if factor <= 1 {
+		return img
+	}
+	if gray, ok := img.(*image.Gray); ok {
+		return DownscaleGray(gray, factor)
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx()/factor, bounds.Dy()/factor
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	blockSize := uint32(factor * factor)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var sumR, sumG, sumB, sumA uint32
+			for by := 0; by < factor; by++ {
+				for bx := 0; bx < factor; bx++ {
+					r, g, b, a := img.At(bounds.Min.X+x*factor+bx, bounds.Min.Y+y*factor+by).RGBA()
+					sumR += r
+					sumG += g
+					sumB += b
+					sumA += a
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(sumR / blockSize >> 8),
+				G: uint8(sumG / blockSize >> 8),
+				B: uint8(sumB / blockSize >> 8),
+				A: uint8(sumA / blockSize >> 8),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,157 @@
+package pixelconverter
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// ToImage converts a raw camera frame in the hand-overed pixel format into an image. Jpeg frames without huffman tables
+// (common for uvc cameras) are decoded with the default tables.
+//
+// supported pixel formats: MJPEG, JPEG, YUYV, UYVY, GREY
+func ToImage(data []byte, pixFmt v4l2.PixFormat) (image.Image, error) {
+	switch pixFmt.PixelFormat {
+	case v4l2.PixelFmtMJPEG, v4l2.PixelFmtJPEG:
+		img, err := jpeg.Decode(bytes.NewReader(addHuffmanTables(data)))
+		if err != nil {
+			return nil, fmt.Errorf("ToImage() - error: decoding jpeg frame: %v", err)
+		}
+		return img, nil
+	case v4l2.PixelFmtYUYV, v4l2.PixelFmtUYVY:
+		return packedYUV422ToYCbCr(data, pixFmt)
+	case v4l2.PixelFmtGrey:
+		return greyToGray(data, pixFmt)
+	default:
+		return nil, fmt.Errorf("ToImage() - error: unsupported pixel format: %v", pixFmt.PixelFormat)
+	}
+}
+
+// ToGray converts a raw camera frame in the hand-overed pixel format into a grayscale (luma) plane.
+// For YUV based formats the luma channel is extracted directly without a color conversion.
+//
+// supported pixel formats: MJPEG, JPEG, YUYV, UYVY, GREY
+func ToGray(data []byte, pixFmt v4l2.PixFormat) (*image.Gray, error) {
+	switch pixFmt.PixelFormat {
+	case v4l2.PixelFmtGrey:
+		return greyToGray(data, pixFmt)
+	case v4l2.PixelFmtYUYV, v4l2.PixelFmtUYVY:
+		return packedYUV422ToGray(data, pixFmt)
+	default:
+		img, err := ToImage(data, pixFmt)
+		if err != nil {
+			return nil, err
+		}
+		return ImageToGray(img), nil
+	}
+}
+
+// ImageToGray converts an image into a grayscale plane. The luma plane of YCbCr images (e.g. decoded jpegs) is used directly.
+func ImageToGray(img image.Image) *image.Gray {
+	bounds := img.Bounds()
+	switch src := img.(type) {
+	case *image.Gray:
+		return src
+	case *image.YCbCr:
+		gray := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		for y := 0; y < bounds.Dy(); y++ {
+			srcOffset := src.YOffset(bounds.Min.X, bounds.Min.Y+y)
+			copy(gray.Pix[y*gray.Stride:y*gray.Stride+bounds.Dx()], src.Y[srcOffset:srcOffset+bounds.Dx()])
+		}
+		return gray
+	default:
+		gray := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(gray, gray.Bounds(), img, bounds.Min, draw.Src)
+		return gray
+	}
+}
+
+// bytesPerLine returns the line length of a frame. Drivers may pad the lines, so the reported value is preferred.
+func bytesPerLine(pixFmt v4l2.PixFormat, bytesPerPixel int) int {
+	if pixFmt.BytesPerLine != 0 {
+		return int(pixFmt.BytesPerLine)
+	}
+	return int(pixFmt.Width) * bytesPerPixel
+}
+
+// checkFrameSize checks that the frame holds enough data for the hand-overed pixel format.
+func checkFrameSize(data []byte, pixFmt v4l2.PixFormat, bytesPerPixel int) error {
+	if pixFmt.Width == 0 || pixFmt.Height == 0 {
+		return fmt.Errorf("invalid frame dimension: %dx%d", pixFmt.Width, pixFmt.Height)
+	}
+	stride := bytesPerLine(pixFmt, bytesPerPixel)
+	required := stride*(int(pixFmt.Height)-1) + int(pixFmt.Width)*bytesPerPixel
+	if len(data) < required {
+		return fmt.Errorf("frame too short: %d bytes, expected at least %d bytes", len(data), required)
+	}
+	return nil
+}
+
+// greyToGray wraps a GREY (8 bit luma) frame into a grayscale image.
+func greyToGray(data []byte, pixFmt v4l2.PixFormat) (*image.Gray, error) {
+	if err := checkFrameSize(data, pixFmt, 1); err != nil {
+		return nil, fmt.Errorf("greyToGray() - error: %v", err)
+	}
+	width, height := int(pixFmt.Width), int(pixFmt.Height)
+	gray := image.NewGray(image.Rect(0, 0, width, height))
+	stride := bytesPerLine(pixFmt, 1)
+	for y := 0; y < height; y++ {
+		copy(gray.Pix[y*gray.Stride:y*gray.Stride+width], data[y*stride:y*stride+width])
+	}
+	return gray, nil
+}
+
+// yuv422Offsets returns the byte offsets of the first luma, second luma, cb and cr sample inside a packed 4:2:2 macro pixel.
+func yuv422Offsets(pixelFormat v4l2.FourCCType) (y0, y1, cb, cr int) {
+	if pixelFormat == v4l2.PixelFmtUYVY {
+		return 1, 3, 0, 2
+	}
+	// --> YUYV
+	return 0, 2, 1, 3
+}
+
+// packedYUV422ToYCbCr converts a packed YUYV or UYVY frame into a 4:2:2 subsampled YCbCr image.
+func packedYUV422ToYCbCr(data []byte, pixFmt v4l2.PixFormat) (*image.YCbCr, error) {
+	if err := checkFrameSize(data, pixFmt, 2); err != nil {
+		return nil, fmt.Errorf("packedYUV422ToYCbCr() - error: %v", err)
+	}
+	width, height := int(pixFmt.Width), int(pixFmt.Height)
+	img := image.NewYCbCr(image.Rect(0, 0, width, height), image.YCbCrSubsampleRatio422)
+	stride := bytesPerLine(pixFmt, 2)
+	y0, y1, cb, cr := yuv422Offsets(pixFmt.PixelFormat)
+
+	for y := 0; y < height; y++ {
+		line := data[y*stride:]
+		for x := 0; x+1 < width; x += 2 {
+			macroPixel := line[x*2 : x*2+4]
+			img.Y[y*img.YStride+x] = macroPixel[y0]
+			img.Y[y*img.YStride+x+1] = macroPixel[y1]
+			img.Cb[y*img.CStride+x/2] = macroPixel[cb]
+			img.Cr[y*img.CStride+x/2] = macroPixel[cr]
+		}
+	}
+	return img, nil
+}
+
+// packedYUV422ToGray extracts the luma channel of a packed YUYV or UYVY frame.
+func packedYUV422ToGray(data []byte, pixFmt v4l2.PixFormat) (*image.Gray, error) {
+	if err := checkFrameSize(data, pixFmt, 2); err != nil {
+		return nil, fmt.Errorf("packedYUV422ToGray() - error: %v", err)
+	}
+	width, height := int(pixFmt.Width), int(pixFmt.Height)
+	gray := image.NewGray(image.Rect(0, 0, width, height))
+	stride := bytesPerLine(pixFmt, 2)
+	y0, _, _, _ := yuv422Offsets(pixFmt.PixelFormat)
+
+	for y := 0; y < height; y++ {
+		line := data[y*stride:]
+		for x := 0; x < width; x++ {
+			gray.Pix[y*gray.Stride+x] = line[x*2+y0]
+		}
+	}
+	return gray, nil
+}
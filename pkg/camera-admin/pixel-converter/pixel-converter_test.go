@@ -0,0 +1,214 @@
+package pixelconverter
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// luma of the 4x2 test frames
+var testLuma = []uint8{
+	10, 20, 30, 40,
+	50, 60, 70, 80,
+}
+
+// packed returns a 4x2 packed 4:2:2 frame with the test luma, cb 100 and cr 200. Every line is padded by the hand-overed
+// amount of bytes.
+func packed(pixelFormat v4l2.FourCCType, padding int) ([]byte, v4l2.PixFormat) {
+	stride := 4*2 + padding
+	data := make([]byte, 2*stride)
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x += 2 {
+			macroPixel := []byte{testLuma[y*4+x], 100, testLuma[y*4+x+1], 200} // YUYV
+			if pixelFormat == v4l2.PixelFmtUYVY {
+				macroPixel = []byte{100, testLuma[y*4+x], 200, testLuma[y*4+x+1]}
+			}
+			copy(data[y*stride+x*2:], macroPixel)
+		}
+	}
+	return data, v4l2.PixFormat{Width: 4, Height: 2, PixelFormat: pixelFormat, BytesPerLine: uint32(stride)}
+}
+
+// grey returns a 4x2 GREY frame with the test luma. Every line is padded by the hand-overed amount of bytes.
+func grey(padding int) ([]byte, v4l2.PixFormat) {
+	stride := 4 + padding
+	data := make([]byte, 2*stride)
+	for y := 0; y < 2; y++ {
+		copy(data[y*stride:], testLuma[y*4:y*4+4])
+	}
+	return data, v4l2.PixFormat{Width: 4, Height: 2, PixelFormat: v4l2.PixelFmtGrey, BytesPerLine: uint32(stride)}
+}
+
+func TestToGray(t *testing.T) {
+	yuyv, yuyvFormat := packed(v4l2.PixelFmtYUYV, 0)
+	uyvy, uyvyFormat := packed(v4l2.PixelFmtUYVY, 0)
+	paddedYUYV, paddedYUYVFormat := packed(v4l2.PixelFmtYUYV, 6)
+	greyData, greyFormat := grey(0)
+	paddedGrey, paddedGreyFormat := grey(3)
+
+	tests := []struct {
+		name   string
+		data   []byte
+		pixFmt v4l2.PixFormat
+	}{
+		{name: "YUYV", data: yuyv, pixFmt: yuyvFormat},
+		{name: "UYVY", data: uyvy, pixFmt: uyvyFormat},
+		{name: "YUYV with padded stride", data: paddedYUYV, pixFmt: paddedYUYVFormat},
+		{name: "GREY", data: greyData, pixFmt: greyFormat},
+		{name: "GREY with padded stride", data: paddedGrey, pixFmt: paddedGreyFormat},
+	}
+	for _, tt := range tests {
+		gray, err := ToGray(tt.data, tt.pixFmt)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if gray.Bounds() != image.Rect(0, 0, 4, 2) {
+			t.Errorf("%s: bounds %v, want 4x2", tt.name, gray.Bounds())
+			continue
+		}
+		for i, want := range testLuma {
+			if got := gray.GrayAt(i%4, i/4).Y; got != want {
+				t.Errorf("%s: luma at (%d,%d) = %d, want %d", tt.name, i%4, i/4, got, want)
+			}
+		}
+	}
+}
+
+func TestToImageYUV422(t *testing.T) {
+	for _, pixelFormat := range []v4l2.FourCCType{v4l2.PixelFmtYUYV, v4l2.PixelFmtUYVY} {
+		data, pixFmt := packed(pixelFormat, 2)
+		img, err := ToImage(data, pixFmt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ycbcr, ok := img.(*image.YCbCr)
+		if !ok {
+			t.Fatalf("%v: image type %T, want *image.YCbCr", pixelFormat, img)
+		}
+		for i, want := range testLuma {
+			if got := ycbcr.YCbCrAt(i%4, i/4); got != (color.YCbCr{Y: want, Cb: 100, Cr: 200}) {
+				t.Errorf("%v: color at (%d,%d) = %v, want %d/100/200", pixelFormat, i%4, i/4, got, want)
+			}
+		}
+	}
+}
+
+func TestToImageErrors(t *testing.T) {
+	yuyv, yuyvFormat := packed(v4l2.PixelFmtYUYV, 0)
+	tests := []struct {
+		name   string
+		data   []byte
+		pixFmt v4l2.PixFormat
+	}{
+		{name: "frame too short", data: yuyv[:len(yuyv)-1], pixFmt: yuyvFormat},
+		{name: "no dimension", data: yuyv, pixFmt: v4l2.PixFormat{PixelFormat: v4l2.PixelFmtYUYV}},
+		{name: "unsupported pixel format", data: yuyv, pixFmt: v4l2.PixFormat{Width: 4, Height: 2}},
+		{name: "invalid jpeg", data: []byte{0xFF, 0xD8, 0x00}, pixFmt: v4l2.PixFormat{PixelFormat: v4l2.PixelFmtMJPEG}},
+	}
+	for _, tt := range tests {
+		if _, err := ToImage(tt.data, tt.pixFmt); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+// stripHuffmanTables removes the dht segments of a jpeg frame (like the mjpeg frames of many uvc cameras).
+func stripHuffmanTables(t *testing.T, data []byte) []byte {
+	t.Helper()
+	stripped := append([]byte(nil), data[:2]...)
+	for i := 2; ; {
+		if data[i] != 0xFF {
+			t.Fatalf("no marker at offset %d", i)
+		}
+		if data[i+1] == markerSOS {
+			return append(stripped, data[i:]...)
+		}
+		end := i + 2 + (int(data[i+2])<<8 | int(data[i+3]))
+		if data[i+1] != markerDHT {
+			stripped = append(stripped, data[i:end]...)
+		}
+		i = end
+	}
+}
+
+func TestToImageMJPEG(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 32, 16))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i % 32 * 8)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	withTables := buf.Bytes()
+	withoutTables := stripHuffmanTables(t, withTables)
+	if bytes.Contains(withoutTables, []byte{0xFF, markerDHT}) {
+		t.Fatal("huffman tables not stripped")
+	}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		pixelFormat v4l2.FourCCType
+	}{
+		{name: "MJPEG", data: withTables, pixelFormat: v4l2.PixelFmtMJPEG},
+		{name: "MJPEG without huffman tables", data: withoutTables, pixelFormat: v4l2.PixelFmtMJPEG},
+		{name: "JPEG", data: withTables, pixelFormat: v4l2.PixelFmtJPEG},
+	}
+	for _, tt := range tests {
+		gray, err := ToGray(tt.data, v4l2.PixFormat{Width: 32, Height: 16, PixelFormat: tt.pixelFormat})
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if gray.Bounds() != src.Bounds() {
+			t.Errorf("%s: bounds %v, want %v", tt.name, gray.Bounds(), src.Bounds())
+			continue
+		}
+		for i := range src.Pix {
+			if diff := int(gray.Pix[i]) - int(src.Pix[i]); diff > 8 || diff < -8 {
+				t.Errorf("%s: luma at %d = %d, want %d", tt.name, i, gray.Pix[i], src.Pix[i])
+				break
+			}
+		}
+	}
+}
+
+func TestDownscaleGray(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 5, 3))
+	copy(src.Pix, []uint8{
+		0, 2, 10, 20, 99,
+		4, 6, 30, 40, 99,
+		99, 99, 99, 99, 99,
+	})
+
+	tests := []struct {
+		name   string
+		factor int
+		want   []uint8
+		bounds image.Rectangle
+	}{
+		{name: "factor 1", factor: 1, want: src.Pix, bounds: src.Bounds()},
+		{name: "factor 0", factor: 0, want: src.Pix, bounds: src.Bounds()},
+		// --> the remaining column and line are dropped
+		{name: "factor 2", factor: 2, want: []uint8{3, 25}, bounds: image.Rect(0, 0, 2, 1)},
+		{name: "factor 3", factor: 3, want: []uint8{(0 + 2 + 10 + 4 + 6 + 30 + 3*99) / 9}, bounds: image.Rect(0, 0, 1, 1)},
+	}
+	for _, tt := range tests {
+		dst := DownscaleGray(src, tt.factor)
+		if dst.Bounds() != tt.bounds || !bytes.Equal(dst.Pix, tt.want) {
+			t.Errorf("%s: %v %v, want %v %v", tt.name, dst.Bounds(), dst.Pix, tt.bounds, tt.want)
+		}
+	}
+
+	// a sub image starts at its own origin
+	sub := src.SubImage(image.Rect(2, 0, 4, 2)).(*image.Gray)
+	if dst := DownscaleGray(sub, 2); !bytes.Equal(dst.Pix, []uint8{25}) {
+		t.Errorf("sub image: %v, want [25]", dst.Pix)
+	}
+}
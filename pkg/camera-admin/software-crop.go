@@ -0,0 +1,108 @@
+package cameraadmin
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	pixelconverter "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/pixel-converter"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// jpegQuality is the quality used to re-encode software cropped frames.
+const jpegQuality = 90
+
+type subImager interface {
+	SubImage(r image.Rectangle) image.Image
+}
+
+// cropFrame crops a frame to the given region of interest in software. The region is clipped to the frame bounds.
+// Compressed frames are re-encoded, raw frames are cropped without a conversion and keep their pixel format.
+func cropFrame(f frame.Frame, roi v4l2.Rect) (frame.Frame, error) {
+	switch f.PixFormat.PixelFormat {
+	case v4l2.PixelFmtMJPEG, v4l2.PixelFmtJPEG:
+		return cropJPEG(f, roi)
+	case v4l2.PixelFmtYUYV, v4l2.PixelFmtUYVY:
+		// --> a macro pixel holds two pixels --> the left border has to be even
+		return cropRaw(f, roi, 2, 2)
+	case v4l2.PixelFmtGrey:
+		return cropRaw(f, roi, 1, 1)
+	default:
+		return frame.Frame{}, fmt.Errorf("cropFrame() - error: unsupported pixel format: %v", f.PixFormat.PixelFormat)
+	}
+}
+
+// cropJPEG crops a jpeg encoded frame to the given region of interest and returns the re-encoded frame.
+func cropJPEG(f frame.Frame, roi v4l2.Rect) (frame.Frame, error) {
+	img, err := pixelconverter.ToImage(f.Data, f.PixFormat)
+	if err != nil {
+		return frame.Frame{}, fmt.Errorf("cropJPEG() - error: %v", err)
+	}
+
+	roiRect := roiRectangle(roi, img.Bounds(), 1)
+	if roiRect.Empty() {
+		return frame.Frame{}, fmt.Errorf("cropJPEG() - error: roi %+v is outside of the frame %v", roi, img.Bounds())
+	}
+
+	subImg, ok := img.(subImager)
+	if !ok {
+		return frame.Frame{}, fmt.Errorf("cropJPEG() - error: image type %T does not support cropping", img)
+	}
+
+	var buf bytes.Buffer
+	err = jpeg.Encode(&buf, subImg.SubImage(roiRect), &jpeg.Options{Quality: jpegQuality})
+	if err != nil {
+		return frame.Frame{}, fmt.Errorf("cropJPEG() - error: encoding frame: %v", err)
+	}
+
+	f.Data = buf.Bytes()
+	f.PixFormat.Width = uint32(roiRect.Dx())
+	f.PixFormat.Height = uint32(roiRect.Dy())
+	f.PixFormat.BytesPerLine = 0
+	return f, nil
+}
+
+// cropRaw crops an uncompressed frame line by line.
+// alignment is the pixel alignment of the left border and the width.
+func cropRaw(f frame.Frame, roi v4l2.Rect, bytesPerPixel int, alignment int) (frame.Frame, error) {
+	bounds := image.Rect(0, 0, int(f.PixFormat.Width), int(f.PixFormat.Height))
+	roiRect := roiRectangle(roi, bounds, alignment)
+	if roiRect.Empty() {
+		return frame.Frame{}, fmt.Errorf("cropRaw() - error: roi %+v is outside of the frame %v", roi, bounds)
+	}
+
+	srcStride := int(f.PixFormat.BytesPerLine)
+	if srcStride == 0 {
+		srcStride = bounds.Dx() * bytesPerPixel
+	}
+	if len(f.Data) < srcStride*bounds.Dy() {
+		return frame.Frame{}, fmt.Errorf("cropRaw() - error: frame too short: %d bytes", len(f.Data))
+	}
+
+	dstStride := roiRect.Dx() * bytesPerPixel
+	data := make([]byte, dstStride*roiRect.Dy())
+	for y := 0; y < roiRect.Dy(); y++ {
+		srcOffset := (roiRect.Min.Y+y)*srcStride + roiRect.Min.X*bytesPerPixel
+		copy(data[y*dstStride:(y+1)*dstStride], f.Data[srcOffset:srcOffset+dstStride])
+	}
+
+	f.Data = data
+	f.PixFormat.Width = uint32(roiRect.Dx())
+	f.PixFormat.Height = uint32(roiRect.Dy())
+	f.PixFormat.BytesPerLine = uint32(dstStride)
+	f.PixFormat.SizeImage = uint32(len(data))
+	return f, nil
+}
+
+// roiRectangle converts a region of interest into a rectangle that is clipped to the bounds and aligned to the hand-overed pixel alignment.
+func roiRectangle(roi v4l2.Rect, bounds image.Rectangle, alignment int) image.Rectangle {
+	r := image.Rect(int(roi.Left), int(roi.Top), int(roi.Left)+int(roi.Width), int(roi.Top)+int(roi.Height)).Intersect(bounds)
+	if alignment > 1 {
+		width := r.Dx()
+		r.Min.X -= r.Min.X % alignment
+		r.Max.X = r.Min.X + width/alignment*alignment
+	}
+	return r
+}
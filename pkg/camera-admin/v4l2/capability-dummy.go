@@ -2,7 +2,45 @@
 
 package v4l2
 
+const (
+	CapVideoCapture       uint32 = 0x00000001
+	CapVideoOutput        uint32 = 0x00000002
+	CapVideoOverlay       uint32 = 0x00000004
+	CapVBICapture         uint32 = 0x00000010
+	CapVBIOutput          uint32 = 0x00000020
+	CapSlicedVBICapture   uint32 = 0x00000040
+	CapSlicedVBIOutput    uint32 = 0x00000080
+	CapRDSCapture         uint32 = 0x00000100
+	CapVideoOutputOverlay uint32 = 0x00000200
+	CapHWFrequencySeek    uint32 = 0x00000400
+	CapRDSOutput          uint32 = 0x00000800
+	CapVideoCaptureMPlane uint32 = 0x00001000
+	CapVideoOutputMPlane  uint32 = 0x00002000
+	CapVideoMem2MemMPlane uint32 = 0x00004000
+	CapVideoMem2Mem       uint32 = 0x00008000
+	CapTuner              uint32 = 0x00010000
+	CapAudio              uint32 = 0x00020000
+	CapRadio              uint32 = 0x00040000
+	CapModulator          uint32 = 0x00080000
+	CapSDRCapture         uint32 = 0x00100000
+	CapExtendedPixFormat  uint32 = 0x00200000
+	CapSDROutput          uint32 = 0x00400000
+	CapMetadataCapture    uint32 = 0x00800000
+	CapReadWrite          uint32 = 0x01000000
+	CapStreaming          uint32 = 0x04000000
+	CapMetadataOutput     uint32 = 0x08000000
+	CapTouch              uint32 = 0x10000000
+	CapIOMediaController  uint32 = 0x20000000
+	CapDeviceCapabilities uint32 = 0x80000000
+)
+
 type Capability struct {
+	Driver             string
+	Card               string
+	BusInfo            string
+	Version            uint32
+	Capabilities       uint32
+	DeviceCapabilities uint32
 }
 
 // IsStreamingSupported returns caps & CapStreaming
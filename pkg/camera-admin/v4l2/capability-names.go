@@ -0,0 +1,84 @@
+package v4l2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// capabilityNames maps the V4L2_CAP_* flags to human-readable descriptions.
+var capabilityNames = []struct {
+	flag uint32
+	name string
+}{
+	{CapVideoCapture, "video capture"},
+	{CapVideoOutput, "video output"},
+	{CapVideoOverlay, "video overlay"},
+	{CapVBICapture, "raw VBI capture"},
+	{CapVBIOutput, "raw VBI output"},
+	{CapSlicedVBICapture, "sliced VBI capture"},
+	{CapSlicedVBIOutput, "sliced VBI output"},
+	{CapRDSCapture, "RDS capture"},
+	{CapVideoOutputOverlay, "video output overlay"},
+	{CapHWFrequencySeek, "hardware frequency seek"},
+	{CapRDSOutput, "RDS output"},
+	{CapVideoCaptureMPlane, "video capture (multi-planar)"},
+	{CapVideoOutputMPlane, "video output (multi-planar)"},
+	{CapVideoMem2MemMPlane, "memory-to-memory (multi-planar)"},
+	{CapVideoMem2Mem, "memory-to-memory"},
+	{CapTuner, "tuner"},
+	{CapAudio, "audio"},
+	{CapRadio, "radio"},
+	{CapModulator, "modulator"},
+	{CapSDRCapture, "SDR capture"},
+	{CapExtendedPixFormat, "extended pixel format"},
+	{CapSDROutput, "SDR output"},
+	{CapMetadataCapture, "metadata capture"},
+	{CapReadWrite, "read/write IO"},
+	{CapStreaming, "streaming IO"},
+	{CapMetadataOutput, "metadata output"},
+	{CapTouch, "touch"},
+	{CapIOMediaController, "media controller IO"},
+	{CapDeviceCapabilities, "device capabilities"},
+}
+
+// EffectiveCapabilities returns the capabilities of the opened device node. If the driver sets CapDeviceCapabilities
+// the device capabilities are used, otherwise the capabilities of the whole physical device.
+func (c Capability) EffectiveCapabilities() uint32 {
+	if c.Capabilities&CapDeviceCapabilities != 0 {
+		return c.DeviceCapabilities
+	}
+	return c.Capabilities
+}
+
+// CapabilityDescriptions returns human-readable descriptions of all effective capability flags.
+func (c Capability) CapabilityDescriptions() []string {
+	return CapabilityFlagDescriptions(c.EffectiveCapabilities())
+}
+
+// DriverVersion returns the driver version as "major.minor.patch".
+func (c Capability) DriverVersion() string {
+	return fmt.Sprintf("%d.%d.%d", (c.Version>>16)&0xff, (c.Version>>8)&0xff, c.Version&0xff)
+}
+
+// String returns a one-line summary of the capability.
+func (c Capability) String() string {
+	return fmt.Sprintf("driver: %s, card: %s, bus: %s, version: %s, capabilities: [%s]",
+		c.Driver, c.Card, c.BusInfo, c.DriverVersion(), strings.Join(c.CapabilityDescriptions(), ", "))
+}
+
+// CapabilityFlagDescriptions returns human-readable descriptions of all set V4L2_CAP_* flags.
+// Unknown flags are reported as hex values.
+func CapabilityFlagDescriptions(flags uint32) []string {
+	var descriptions []string
+	var known uint32
+	for _, c := range capabilityNames {
+		known |= c.flag
+		if flags&c.flag != 0 {
+			descriptions = append(descriptions, c.name)
+		}
+	}
+	if unknown := flags &^ known; unknown != 0 {
+		descriptions = append(descriptions, fmt.Sprintf("unknown (0x%08x)", unknown))
+	}
+	return descriptions
+}
@@ -16,9 +16,35 @@ import (
 )
 
 const (
-	CapVideoCapture uint32 = C.V4L2_CAP_VIDEO_CAPTURE
-	CapVideoOutput  uint32 = C.V4L2_CAP_VIDEO_OUTPUT
-	CapStreaming    uint32 = C.V4L2_CAP_STREAMING
+	CapVideoCapture       uint32 = C.V4L2_CAP_VIDEO_CAPTURE
+	CapVideoOutput        uint32 = C.V4L2_CAP_VIDEO_OUTPUT
+	CapVideoOverlay       uint32 = C.V4L2_CAP_VIDEO_OVERLAY
+	CapVBICapture         uint32 = C.V4L2_CAP_VBI_CAPTURE
+	CapVBIOutput          uint32 = C.V4L2_CAP_VBI_OUTPUT
+	CapSlicedVBICapture   uint32 = C.V4L2_CAP_SLICED_VBI_CAPTURE
+	CapSlicedVBIOutput    uint32 = C.V4L2_CAP_SLICED_VBI_OUTPUT
+	CapRDSCapture         uint32 = C.V4L2_CAP_RDS_CAPTURE
+	CapVideoOutputOverlay uint32 = C.V4L2_CAP_VIDEO_OUTPUT_OVERLAY
+	CapHWFrequencySeek    uint32 = C.V4L2_CAP_HW_FREQ_SEEK
+	CapRDSOutput          uint32 = C.V4L2_CAP_RDS_OUTPUT
+	CapVideoCaptureMPlane uint32 = C.V4L2_CAP_VIDEO_CAPTURE_MPLANE
+	CapVideoOutputMPlane  uint32 = C.V4L2_CAP_VIDEO_OUTPUT_MPLANE
+	CapVideoMem2MemMPlane uint32 = C.V4L2_CAP_VIDEO_M2M_MPLANE
+	CapVideoMem2Mem       uint32 = C.V4L2_CAP_VIDEO_M2M
+	CapTuner              uint32 = C.V4L2_CAP_TUNER
+	CapAudio              uint32 = C.V4L2_CAP_AUDIO
+	CapRadio              uint32 = C.V4L2_CAP_RADIO
+	CapModulator          uint32 = C.V4L2_CAP_MODULATOR
+	CapSDRCapture         uint32 = C.V4L2_CAP_SDR_CAPTURE
+	CapExtendedPixFormat  uint32 = C.V4L2_CAP_EXT_PIX_FORMAT
+	CapSDROutput          uint32 = C.V4L2_CAP_SDR_OUTPUT
+	CapMetadataCapture    uint32 = C.V4L2_CAP_META_CAPTURE
+	CapReadWrite          uint32 = C.V4L2_CAP_READWRITE
+	CapStreaming          uint32 = C.V4L2_CAP_STREAMING
+	CapMetadataOutput     uint32 = C.V4L2_CAP_META_OUTPUT
+	CapTouch              uint32 = C.V4L2_CAP_TOUCH
+	CapIOMediaController  uint32 = C.V4L2_CAP_IO_MC
+	CapDeviceCapabilities uint32 = C.V4L2_CAP_DEVICE_CAPS
 )
 
 type Capability struct {
@@ -32,7 +58,7 @@ type Capability struct {
 
 // IsStreamingSupported returns caps & CapStreaming
 func (c Capability) IsStreamingSupported() bool {
-	return c.Capabilities&CapStreaming != 0
+	return c.EffectiveCapabilities()&CapStreaming != 0
 }
 
 // GetCapability retrieves capability info for device
@@ -53,10 +79,10 @@ func GetCapability(fd uintptr) (Capability, error) {
 
 // IsVideoCaptureSupported returns caps & CapVideoCapture
 func (c Capability) IsVideoCaptureSupported() bool {
-	return c.Capabilities&CapVideoCapture != 0
+	return c.EffectiveCapabilities()&CapVideoCapture != 0
 }
 
 // IsVideoOutputSupported returns caps & CapVideoOutput
 func (c Capability) IsVideoOutputSupported() bool {
-	return c.Capabilities&CapVideoOutput != 0
+	return c.EffectiveCapabilities()&CapVideoOutput != 0
 }
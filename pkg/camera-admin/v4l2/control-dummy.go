@@ -0,0 +1,67 @@
+//go:build !linux
+
+package v4l2
+
+type CtrlType = uint32
+
+const (
+	CtrlTypeInteger     CtrlType = 1
+	CtrlTypeBoolean     CtrlType = 2
+	CtrlTypeMenu        CtrlType = 3
+	CtrlTypeButton      CtrlType = 4
+	CtrlTypeInteger64   CtrlType = 5
+	CtrlTypeClass       CtrlType = 6
+	CtrlTypeString      CtrlType = 7
+	CtrlTypeBitmask     CtrlType = 8
+	CtrlTypeIntegerMenu CtrlType = 9
+)
+
+type CtrlFlag = uint32
+
+const (
+	CtrlFlagDisabled  CtrlFlag = 0x0001
+	CtrlFlagReadOnly  CtrlFlag = 0x0004
+	CtrlFlagInactive  CtrlFlag = 0x0010
+	CtrlFlagWriteOnly CtrlFlag = 0x0040
+)
+
+type CtrlID = uint32
+
+type Control struct {
+	ID      CtrlID
+	Type    CtrlType
+	Name    string
+	Minimum int32
+	Maximum int32
+	Step    int32
+	Default int32
+	Flags   CtrlFlag
+	Value   int32
+	Menu    []ControlMenuItem
+}
+
+type ControlMenuItem struct {
+	Index uint32
+	Name  string
+	Value int64
+}
+
+// IsReadable returns true if the current value of the control could be read
+func (c Control) IsReadable() bool {
+	return false
+}
+
+// QueryControls enumerates all enabled controls of the driver with their current value (via VIDIOC_QUERYCTRL)
+func QueryControls(fd uintptr) ([]Control, error) {
+	return nil, nil
+}
+
+// GetControlValue retrieves the current value of a control (via VIDIOC_G_CTRL)
+func GetControlValue(fd uintptr, id CtrlID) (int32, error) {
+	return 0, nil
+}
+
+// SetControlValue sets the value of a control (via VIDIOC_S_CTRL)
+func SetControlValue(fd uintptr, id CtrlID, value int32) error {
+	return nil
+}
@@ -0,0 +1,157 @@
+//go:build linux
+
+package v4l2
+
+// #include <linux/videodev2.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+type CtrlType = uint32
+
+const (
+	CtrlTypeInteger     CtrlType = C.V4L2_CTRL_TYPE_INTEGER
+	CtrlTypeBoolean     CtrlType = C.V4L2_CTRL_TYPE_BOOLEAN
+	CtrlTypeMenu        CtrlType = C.V4L2_CTRL_TYPE_MENU
+	CtrlTypeButton      CtrlType = C.V4L2_CTRL_TYPE_BUTTON
+	CtrlTypeInteger64   CtrlType = C.V4L2_CTRL_TYPE_INTEGER64
+	CtrlTypeClass       CtrlType = C.V4L2_CTRL_TYPE_CTRL_CLASS
+	CtrlTypeString      CtrlType = C.V4L2_CTRL_TYPE_STRING
+	CtrlTypeBitmask     CtrlType = C.V4L2_CTRL_TYPE_BITMASK
+	CtrlTypeIntegerMenu CtrlType = C.V4L2_CTRL_TYPE_INTEGER_MENU
+)
+
+type CtrlFlag = uint32
+
+const (
+	CtrlFlagDisabled  CtrlFlag = C.V4L2_CTRL_FLAG_DISABLED
+	CtrlFlagReadOnly  CtrlFlag = C.V4L2_CTRL_FLAG_READ_ONLY
+	CtrlFlagInactive  CtrlFlag = C.V4L2_CTRL_FLAG_INACTIVE
+	CtrlFlagWriteOnly CtrlFlag = C.V4L2_CTRL_FLAG_WRITE_ONLY
+	ctrlFlagNextCtrl  uint32   = C.V4L2_CTRL_FLAG_NEXT_CTRL
+)
+
+type CtrlID = uint32
+
+// Control describes a user control of the driver (v4l2_queryctrl) and its current value
+type Control struct {
+	ID       CtrlID
+	Type     CtrlType
+	Name     string
+	Minimum  int32
+	Maximum  int32
+	Step     int32
+	Default  int32
+	Flags    CtrlFlag
+	Value    int32
+	Menu     []ControlMenuItem
+	readable bool
+}
+
+// ControlMenuItem is an entry of a menu control (v4l2_querymenu)
+type ControlMenuItem struct {
+	Index uint32
+	Name  string
+	Value int64
+}
+
+// IsReadable returns true if the current value of the control could be read
+func (c Control) IsReadable() bool {
+	return c.readable
+}
+
+// QueryControls enumerates all enabled controls of the driver with their current value (via VIDIOC_QUERYCTRL)
+func QueryControls(fd uintptr) ([]Control, error) {
+	var controls []Control
+	id := ctrlFlagNextCtrl
+	for {
+		var query C.struct_v4l2_queryctrl
+		query.id = C.uint(id)
+
+		if err := send(fd, C.VIDIOC_QUERYCTRL, uintptr(unsafe.Pointer(&query))); err != nil {
+			if errors.Is(err, ErrorBadArgument) {
+				// --> end of enumeration
+				return controls, nil
+			}
+			return nil, fmt.Errorf("query controls: %w", err)
+		}
+		id = uint32(query.id) | ctrlFlagNextCtrl
+
+		control := Control{
+			ID:      uint32(query.id),
+			Type:    uint32(query._type),
+			Name:    C.GoString((*C.char)(unsafe.Pointer(&query.name[0]))),
+			Minimum: int32(query.minimum),
+			Maximum: int32(query.maximum),
+			Step:    int32(query.step),
+			Default: int32(query.default_value),
+			Flags:   uint32(query.flags),
+		}
+		if control.Flags&CtrlFlagDisabled != 0 || control.Type == CtrlTypeClass {
+			continue
+		}
+
+		if control.Flags&CtrlFlagWriteOnly == 0 && control.Type != CtrlTypeButton {
+			if value, err := GetControlValue(fd, control.ID); err == nil {
+				control.Value = value
+				control.readable = true
+			}
+		}
+
+		if control.Type == CtrlTypeMenu || control.Type == CtrlTypeIntegerMenu {
+			control.Menu = queryMenu(fd, control)
+		}
+		controls = append(controls, control)
+	}
+}
+
+// queryMenu returns all valid menu items of a menu control (via VIDIOC_QUERYMENU)
+func queryMenu(fd uintptr, control Control) []ControlMenuItem {
+	var items []ControlMenuItem
+	for index := control.Minimum; index <= control.Maximum; index++ {
+		var menu C.struct_v4l2_querymenu
+		menu.id = C.uint(control.ID)
+		menu.index = C.uint(index)
+
+		if err := send(fd, C.VIDIOC_QUERYMENU, uintptr(unsafe.Pointer(&menu))); err != nil {
+			// --> menu items may be skipped by the driver
+			continue
+		}
+		item := ControlMenuItem{Index: uint32(index)}
+		if control.Type == CtrlTypeIntegerMenu {
+			item.Value = *(*int64)(unsafe.Pointer(&menu.anon0[0]))
+			item.Name = fmt.Sprintf("%d", item.Value)
+		} else {
+			item.Name = C.GoString((*C.char)(unsafe.Pointer(&menu.anon0[0])))
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// GetControlValue retrieves the current value of a control (via VIDIOC_G_CTRL)
+func GetControlValue(fd uintptr, id CtrlID) (int32, error) {
+	var ctrl C.struct_v4l2_control
+	ctrl.id = C.uint(id)
+
+	if err := send(fd, C.VIDIOC_G_CTRL, uintptr(unsafe.Pointer(&ctrl))); err != nil {
+		return 0, fmt.Errorf("get control value: %w", err)
+	}
+	return int32(ctrl.value), nil
+}
+
+// SetControlValue sets the value of a control (via VIDIOC_S_CTRL)
+func SetControlValue(fd uintptr, id CtrlID, value int32) error {
+	var ctrl C.struct_v4l2_control
+	ctrl.id = C.uint(id)
+	ctrl.value = C.int(value)
+
+	if err := send(fd, C.VIDIOC_S_CTRL, uintptr(unsafe.Pointer(&ctrl))); err != nil {
+		return fmt.Errorf("set control value: %w", err)
+	}
+	return nil
+}
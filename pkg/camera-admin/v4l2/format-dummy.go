@@ -3,17 +3,58 @@
 package v4l2
 
 // FourCCType represents the four character encoding value
-type FourCCType = uint32
+type FourCCType uint32
 
-// Some Predefined pixel format definitions
+// Some Predefined pixel format definitions (v4l2_fourcc values)
 var (
-	PixelFmtMJPEG FourCCType = 0
+	PixelFmtGrey  FourCCType = 'G' | 'R'<<8 | 'E'<<16 | 'Y'<<24
+	PixelFmtYUYV  FourCCType = 'Y' | 'U'<<8 | 'Y'<<16 | 'V'<<24
+	PixelFmtUYVY  FourCCType = 'U' | 'Y'<<8 | 'V'<<16 | 'Y'<<24
+	PixelFmtMJPEG FourCCType = 'M' | 'J'<<8 | 'P'<<16 | 'G'<<24
+	PixelFmtJPEG  FourCCType = 'J' | 'P'<<8 | 'E'<<16 | 'G'<<24
+)
+
+type FieldType uint32
+
+const (
+	FieldAny          FieldType = 0
+	FieldNone         FieldType = 1
+	FieldTop          FieldType = 2
+	FieldBottom       FieldType = 3
+	FieldInterlaced   FieldType = 4
+	FieldSeqTopBottom FieldType = 5
+	FieldSeqBottomTop FieldType = 6
+	FieldAlternate    FieldType = 7
+	FieldInterlacedTB FieldType = 8
+	FieldInterlacedBT FieldType = 9
+)
+
+type ColorspaceType uint32
+
+const (
+	ColorspaceDefault     ColorspaceType = 0
+	ColorspaceSMPTE170M   ColorspaceType = 1
+	ColorspaceSMPTE240M   ColorspaceType = 2
+	ColorspaceREC709      ColorspaceType = 3
+	ColorspaceBT878       ColorspaceType = 4
+	Colorspace470SystemM  ColorspaceType = 5
+	Colorspace470SystemBG ColorspaceType = 6
+	ColorspaceJPEG        ColorspaceType = 7
+	ColorspaceSRGB        ColorspaceType = 8
+	ColorspaceOPRGB       ColorspaceType = 9
+	ColorspaceBT2020      ColorspaceType = 10
+	ColorspaceRaw         ColorspaceType = 11
+	ColorspaceDCIP3       ColorspaceType = 12
 )
 
 type PixFormat struct {
-	Width       uint32
-	Height      uint32
-	PixelFormat FourCCType
+	Width        uint32
+	Height       uint32
+	PixelFormat  FourCCType
+	Field        FieldType
+	BytesPerLine uint32
+	SizeImage    uint32
+	Colorspace   ColorspaceType
 }
 
 // GetPixFormat retrieves pixel information for the specified driver (via v4l2_format and v4l2_pix_format)
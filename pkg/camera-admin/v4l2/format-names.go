@@ -0,0 +1,76 @@
+package v4l2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fourCCBigEndian is set on pixel formats that are the big-endian variant of a format (V4L2_PIX_FMT_FLAG_BE)
+const fourCCBigEndian FourCCType = 1 << 31
+
+// String returns the four character code of a pixel format, e.g. "MJPG" or "YUYV".
+func (f FourCCType) String() string {
+	code := f &^ fourCCBigEndian
+	chars := []byte{byte(code), byte(code >> 8), byte(code >> 16), byte(code >> 24)}
+	for _, c := range chars {
+		if c < 0x20 || c > 0x7e {
+			return fmt.Sprintf("0x%08x", uint32(f))
+		}
+	}
+	name := strings.TrimRight(string(chars), " ")
+	if f&fourCCBigEndian != 0 {
+		name += "-BE"
+	}
+	return name
+}
+
+var fieldNames = map[FieldType]string{
+	FieldAny:          "any",
+	FieldNone:         "none (progressive)",
+	FieldTop:          "top",
+	FieldBottom:       "bottom",
+	FieldInterlaced:   "interlaced",
+	FieldSeqTopBottom: "sequential top-bottom",
+	FieldSeqBottomTop: "sequential bottom-top",
+	FieldAlternate:    "alternate",
+	FieldInterlacedTB: "interlaced top-bottom",
+	FieldInterlacedBT: "interlaced bottom-top",
+}
+
+// String returns the name of a field order.
+func (f FieldType) String() string {
+	if name, ok := fieldNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown (%d)", uint32(f))
+}
+
+var colorspaceNames = map[ColorspaceType]string{
+	ColorspaceDefault:     "default",
+	ColorspaceSMPTE170M:   "SMPTE 170M",
+	ColorspaceSMPTE240M:   "SMPTE 240M",
+	ColorspaceREC709:      "Rec. 709",
+	ColorspaceBT878:       "BT.878",
+	Colorspace470SystemM:  "470 System M",
+	Colorspace470SystemBG: "470 System BG",
+	ColorspaceJPEG:        "JPEG",
+	ColorspaceSRGB:        "sRGB",
+	ColorspaceOPRGB:       "opRGB",
+	ColorspaceBT2020:      "BT.2020",
+	ColorspaceRaw:         "raw",
+	ColorspaceDCIP3:       "DCI-P3",
+}
+
+// String returns the name of a colorspace.
+func (c ColorspaceType) String() string {
+	if name, ok := colorspaceNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown (%d)", uint32(c))
+}
+
+// String returns a one-line summary of the pixel format.
+func (p PixFormat) String() string {
+	return fmt.Sprintf("%s %dx%d, field: %s, colorspace: %s, bytes per line: %d, image size: %d",
+		p.PixelFormat, p.Width, p.Height, p.Field, p.Colorspace, p.BytesPerLine, p.SizeImage)
+}
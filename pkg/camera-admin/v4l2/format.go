@@ -13,7 +13,7 @@ import (
 )
 
 // FourCCType represents the four character encoding value
-type FourCCType = uint32
+type FourCCType uint32
 
 // Some Predefined pixel format definitions
 var (
@@ -31,9 +31,38 @@ var (
 	PixelFmtMPEG4 FourCCType = C.V4L2_PIX_FMT_MPEG4
 )
 
-type FieldType = uint32
+type FieldType uint32
+
+const (
+	FieldAny          FieldType = C.V4L2_FIELD_ANY
+	FieldNone         FieldType = C.V4L2_FIELD_NONE
+	FieldTop          FieldType = C.V4L2_FIELD_TOP
+	FieldBottom       FieldType = C.V4L2_FIELD_BOTTOM
+	FieldInterlaced   FieldType = C.V4L2_FIELD_INTERLACED
+	FieldSeqTopBottom FieldType = C.V4L2_FIELD_SEQ_TB
+	FieldSeqBottomTop FieldType = C.V4L2_FIELD_SEQ_BT
+	FieldAlternate    FieldType = C.V4L2_FIELD_ALTERNATE
+	FieldInterlacedTB FieldType = C.V4L2_FIELD_INTERLACED_TB
+	FieldInterlacedBT FieldType = C.V4L2_FIELD_INTERLACED_BT
+)
 
-type ColorspaceType = uint32
+type ColorspaceType uint32
+
+const (
+	ColorspaceDefault     ColorspaceType = C.V4L2_COLORSPACE_DEFAULT
+	ColorspaceSMPTE170M   ColorspaceType = C.V4L2_COLORSPACE_SMPTE170M
+	ColorspaceSMPTE240M   ColorspaceType = C.V4L2_COLORSPACE_SMPTE240M
+	ColorspaceREC709      ColorspaceType = C.V4L2_COLORSPACE_REC709
+	ColorspaceBT878       ColorspaceType = C.V4L2_COLORSPACE_BT878
+	Colorspace470SystemM  ColorspaceType = C.V4L2_COLORSPACE_470_SYSTEM_M
+	Colorspace470SystemBG ColorspaceType = C.V4L2_COLORSPACE_470_SYSTEM_BG
+	ColorspaceJPEG        ColorspaceType = C.V4L2_COLORSPACE_JPEG
+	ColorspaceSRGB        ColorspaceType = C.V4L2_COLORSPACE_SRGB
+	ColorspaceOPRGB       ColorspaceType = C.V4L2_COLORSPACE_OPRGB
+	ColorspaceBT2020      ColorspaceType = C.V4L2_COLORSPACE_BT2020
+	ColorspaceRaw         ColorspaceType = C.V4L2_COLORSPACE_RAW
+	ColorspaceDCIP3       ColorspaceType = C.V4L2_COLORSPACE_DCI_P3
+)
 
 type YCbCrEncodingType = uint32
 
@@ -72,11 +101,11 @@ func GetPixFormat(fd uintptr) (PixFormat, error) {
 	return PixFormat{
 		Width:        uint32(v4l2PixFmt.width),
 		Height:       uint32(v4l2PixFmt.height),
-		PixelFormat:  uint32(v4l2PixFmt.pixelformat),
-		Field:        uint32(v4l2PixFmt.field),
+		PixelFormat:  FourCCType(v4l2PixFmt.pixelformat),
+		Field:        FieldType(v4l2PixFmt.field),
 		BytesPerLine: uint32(v4l2PixFmt.bytesperline),
 		SizeImage:    uint32(v4l2PixFmt.sizeimage),
-		Colorspace:   uint32(v4l2PixFmt.colorspace),
+		Colorspace:   ColorspaceType(v4l2PixFmt.colorspace),
 		Priv:         uint32(v4l2PixFmt.priv),
 		Flags:        uint32(v4l2PixFmt.flags),
 		YcbcrEnc:     *(*uint32)(unsafe.Pointer(&v4l2PixFmt.anon0[0])),
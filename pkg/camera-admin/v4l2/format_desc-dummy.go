@@ -0,0 +1,66 @@
+//go:build !linux
+
+package v4l2
+
+type FormatFlag = uint32
+
+const (
+	FormatFlagCompressed FormatFlag = 0
+	FormatFlagEmulated   FormatFlag = 0
+)
+
+type FrameSizeType = uint32
+
+const (
+	FrameSizeTypeDiscrete   FrameSizeType = 1
+	FrameSizeTypeContinuous FrameSizeType = 2
+	FrameSizeTypeStepwise   FrameSizeType = 3
+)
+
+type FrameIntervalType = uint32
+
+const (
+	FrameIntervalTypeDiscrete   FrameIntervalType = 1
+	FrameIntervalTypeContinuous FrameIntervalType = 2
+	FrameIntervalTypeStepwise   FrameIntervalType = 3
+)
+
+type FormatDescription struct {
+	Index       uint32
+	StreamType  BufType
+	Flags       FormatFlag
+	Description string
+	PixelFormat FourCCType
+}
+
+type FrameSize struct {
+	Type       FrameSizeType
+	MinWidth   uint32
+	MaxWidth   uint32
+	StepWidth  uint32
+	MinHeight  uint32
+	MaxHeight  uint32
+	StepHeight uint32
+}
+
+type FrameInterval struct {
+	Type FrameIntervalType
+	Min  Fract
+	Max  Fract
+	Step Fract
+}
+
+// GetFormatDescriptions enumerates all pixel formats supported by the driver (via VIDIOC_ENUM_FMT)
+func GetFormatDescriptions(fd uintptr, bufType BufType) ([]FormatDescription, error) {
+	return nil, nil
+}
+
+// GetFrameSizes enumerates all frame sizes supported for a pixel format (via VIDIOC_ENUM_FRAMESIZES)
+func GetFrameSizes(fd uintptr, pixelFormat FourCCType) ([]FrameSize, error) {
+	return nil, nil
+}
+
+// GetFrameIntervals enumerates all frame intervals supported for a pixel format and frame size (via VIDIOC_ENUM_FRAMEINTERVALS)
+func GetFrameIntervals(fd uintptr, pixelFormat FourCCType, width, height uint32) ([]FrameInterval, error) {
+	return nil, nil
+}
@@ -0,0 +1,169 @@
+//go:build linux
+
+package v4l2
+
+// #include <linux/videodev2.h>
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+type FormatFlag = uint32
+
+const (
+	FormatFlagCompressed FormatFlag = C.V4L2_FMT_FLAG_COMPRESSED
+	FormatFlagEmulated   FormatFlag = C.V4L2_FMT_FLAG_EMULATED
+)
+
+type FrameSizeType = uint32
+
+const (
+	FrameSizeTypeDiscrete   FrameSizeType = C.V4L2_FRMSIZE_TYPE_DISCRETE
+	FrameSizeTypeContinuous FrameSizeType = C.V4L2_FRMSIZE_TYPE_CONTINUOUS
+	FrameSizeTypeStepwise   FrameSizeType = C.V4L2_FRMSIZE_TYPE_STEPWISE
+)
+
+type FrameIntervalType = uint32
+
+const (
+	FrameIntervalTypeDiscrete   FrameIntervalType = C.V4L2_FRMIVAL_TYPE_DISCRETE
+	FrameIntervalTypeContinuous FrameIntervalType = C.V4L2_FRMIVAL_TYPE_CONTINUOUS
+	FrameIntervalTypeStepwise   FrameIntervalType = C.V4L2_FRMIVAL_TYPE_STEPWISE
+)
+
+// FormatDescription describes a pixel format supported by the driver (v4l2_fmtdesc)
+type FormatDescription struct {
+	Index       uint32
+	StreamType  BufType
+	Flags       FormatFlag
+	Description string
+	PixelFormat FourCCType
+}
+
+// FrameSize describes a supported frame size of a pixel format. For discrete sizes the min and max values are equal.
+type FrameSize struct {
+	Type       FrameSizeType
+	MinWidth   uint32
+	MaxWidth   uint32
+	StepWidth  uint32
+	MinHeight  uint32
+	MaxHeight  uint32
+	StepHeight uint32
+}
+
+// FrameInterval describes a supported frame interval (1/fps) of a frame size. For discrete intervals min and max are equal.
+type FrameInterval struct {
+	Type FrameIntervalType
+	Min  Fract
+	Max  Fract
+	Step Fract
+}
+
+// frameSizeEnum mirrors v4l2_frmsizeenum
+type frameSizeEnum struct {
+	Index       uint32
+	PixelFormat FourCCType
+	Type        FrameSizeType
+	Size        [6]uint32 // union of v4l2_frmsize_discrete and v4l2_frmsize_stepwise
+	_           [2]uint32
+}
+
+// frameIntervalEnum mirrors v4l2_frmivalenum
+type frameIntervalEnum struct {
+	Index       uint32
+	PixelFormat FourCCType
+	Width       uint32
+	Height      uint32
+	Type        FrameIntervalType
+	Interval    [3]Fract // union of v4l2_fract and v4l2_frmival_stepwise
+	_           [2]uint32
+}
+
+// GetFormatDescriptions enumerates all pixel formats supported by the driver (via VIDIOC_ENUM_FMT)
+func GetFormatDescriptions(fd uintptr, bufType BufType) ([]FormatDescription, error) {
+	var descriptions []FormatDescription
+	for index := uint32(0); ; index++ {
+		var desc C.struct_v4l2_fmtdesc
+		desc.index = C.uint(index)
+		desc._type = C.uint(bufType)
+
+		if err := send(fd, C.VIDIOC_ENUM_FMT, uintptr(unsafe.Pointer(&desc))); err != nil {
+			if errors.Is(err, ErrorBadArgument) {
+				// --> end of enumeration
+				return descriptions, nil
+			}
+			return nil, fmt.Errorf("format descriptions: %w", err)
+		}
+		descriptions = append(descriptions, FormatDescription{
+			Index:       uint32(desc.index),
+			StreamType:  uint32(desc._type),
+			Flags:       uint32(desc.flags),
+			Description: C.GoString((*C.char)(unsafe.Pointer(&desc.description[0]))),
+			PixelFormat: FourCCType(desc.pixelformat),
+		})
+	}
+}
+
+// GetFrameSizes enumerates all frame sizes supported for a pixel format (via VIDIOC_ENUM_FRAMESIZES)
+func GetFrameSizes(fd uintptr, pixelFormat FourCCType) ([]FrameSize, error) {
+	var sizes []FrameSize
+	for index := uint32(0); ; index++ {
+		sizeEnum := frameSizeEnum{Index: index, PixelFormat: pixelFormat}
+		if err := send(fd, C.VIDIOC_ENUM_FRAMESIZES, uintptr(unsafe.Pointer(&sizeEnum))); err != nil {
+			if errors.Is(err, ErrorBadArgument) {
+				// --> end of enumeration
+				return sizes, nil
+			}
+			return nil, fmt.Errorf("frame sizes: %w", err)
+		}
+
+		if sizeEnum.Type == FrameSizeTypeDiscrete {
+			width, height := sizeEnum.Size[0], sizeEnum.Size[1]
+			sizes = append(sizes, FrameSize{Type: sizeEnum.Type, MinWidth: width, MaxWidth: width, MinHeight: height, MaxHeight: height})
+			continue
+		}
+		// --> stepwise or continuous sizes are reported only once
+		sizes = append(sizes, FrameSize{
+			Type:       sizeEnum.Type,
+			MinWidth:   sizeEnum.Size[0],
+			MaxWidth:   sizeEnum.Size[1],
+			StepWidth:  sizeEnum.Size[2],
+			MinHeight:  sizeEnum.Size[3],
+			MaxHeight:  sizeEnum.Size[4],
+			StepHeight: sizeEnum.Size[5],
+		})
+		return sizes, nil
+	}
+}
+
+// GetFrameIntervals enumerates all frame intervals supported for a pixel format and frame size (via VIDIOC_ENUM_FRAMEINTERVALS)
+func GetFrameIntervals(fd uintptr, pixelFormat FourCCType, width, height uint32) ([]FrameInterval, error) {
+	var intervals []FrameInterval
+	for index := uint32(0); ; index++ {
+		intervalEnum := frameIntervalEnum{Index: index, PixelFormat: pixelFormat, Width: width, Height: height}
+		if err := send(fd, C.VIDIOC_ENUM_FRAMEINTERVALS, uintptr(unsafe.Pointer(&intervalEnum))); err != nil {
+			if errors.Is(err, ErrorBadArgument) {
+				// --> end of enumeration
+				return intervals, nil
+			}
+			return nil, fmt.Errorf("frame intervals: %w", err)
+		}
+
+		if intervalEnum.Type == FrameIntervalTypeDiscrete {
+			interval := intervalEnum.Interval[0]
+			intervals = append(intervals, FrameInterval{Type: intervalEnum.Type, Min: interval, Max: interval})
+			continue
+		}
+		// --> stepwise or continuous intervals are reported only once
+		intervals = append(intervals, FrameInterval{
+			Type: intervalEnum.Type,
+			Min:  intervalEnum.Interval[0],
+			Max:  intervalEnum.Interval[1],
+			Step: intervalEnum.Interval[2],
+		})
+		return intervals, nil
+	}
+}
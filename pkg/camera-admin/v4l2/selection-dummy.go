@@ -0,0 +1,29 @@
+//go:build !linux
+
+package v4l2
+
+type SelectionTarget = uint32
+
+const (
+	SelectionTargetCrop        SelectionTarget = 0
+	SelectionTargetCropDefault SelectionTarget = 0
+	SelectionTargetCropBounds  SelectionTarget = 0
+)
+
+type SelectionFlag = uint32
+
+const (
+	SelectionFlagGE SelectionFlag = 0
+	SelectionFlagLE SelectionFlag = 0
+)
+
+// GetSelection retrieves the selection rectangle of the given target (via VIDIOC_G_SELECTION)
+func GetSelection(fd uintptr, bufType BufType, target SelectionTarget) (Rect, error) {
+	return Rect{}, nil
+}
+
+// SetSelection sets the selection rectangle of the given target (via VIDIOC_S_SELECTION).
+// The driver may adjust the rectangle, the applied rectangle is returned.
+func SetSelection(fd uintptr, bufType BufType, target SelectionTarget, flags SelectionFlag, r Rect) (Rect, error) {
+	return r, nil
+}
@@ -0,0 +1,61 @@
+//go:build linux
+
+package v4l2
+
+// #include <linux/videodev2.h>
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+type SelectionTarget = uint32
+
+const (
+	SelectionTargetCrop        SelectionTarget = C.V4L2_SEL_TGT_CROP
+	SelectionTargetCropDefault SelectionTarget = C.V4L2_SEL_TGT_CROP_DEFAULT
+	SelectionTargetCropBounds  SelectionTarget = C.V4L2_SEL_TGT_CROP_BOUNDS
+)
+
+type SelectionFlag = uint32
+
+const (
+	SelectionFlagGE SelectionFlag = C.V4L2_SEL_FLAG_GE
+	SelectionFlagLE SelectionFlag = C.V4L2_SEL_FLAG_LE
+)
+
+type Selection struct {
+	StreamType uint32
+	Target     SelectionTarget
+	Flags      SelectionFlag
+	Rect       Rect
+	_          [9]uint32
+}
+
+// GetSelection retrieves the selection rectangle of the given target (via VIDIOC_G_SELECTION)
+func GetSelection(fd uintptr, bufType BufType, target SelectionTarget) (Rect, error) {
+	var sel C.struct_v4l2_selection
+	sel._type = C.uint(bufType)
+	sel.target = C.uint(target)
+
+	if err := send(fd, C.VIDIOC_G_SELECTION, uintptr(unsafe.Pointer(&sel))); err != nil {
+		return Rect{}, fmt.Errorf("get selection: %w", err)
+	}
+	return (*Selection)(unsafe.Pointer(&sel)).Rect, nil
+}
+
+// SetSelection sets the selection rectangle of the given target (via VIDIOC_S_SELECTION).
+// The driver may adjust the rectangle, the applied rectangle is returned.
+func SetSelection(fd uintptr, bufType BufType, target SelectionTarget, flags SelectionFlag, r Rect) (Rect, error) {
+	var sel C.struct_v4l2_selection
+	sel._type = C.uint(bufType)
+	sel.target = C.uint(target)
+	sel.flags = C.uint(flags)
+	sel.r = *(*C.struct_v4l2_rect)(unsafe.Pointer(&r))
+
+	if err := send(fd, C.VIDIOC_S_SELECTION, uintptr(unsafe.Pointer(&sel))); err != nil {
+		return Rect{}, fmt.Errorf("set selection: %w", err)
+	}
+	return (*Selection)(unsafe.Pointer(&sel)).Rect, nil
+}
@@ -2,6 +2,8 @@
 
 package v4l2
 
+import sys "golang.org/x/sys/unix"
+
 type BufType = uint32
 
 const (
@@ -30,6 +32,8 @@ type Buffer struct {
 	Index     uint32
 	BytesUsed uint32
 	Flags     uint32
+	Timestamp sys.Timeval
+	Sequence  uint32
 }
 
 // StreamOn requests streaming to be turned on for
@@ -0,0 +1,196 @@
+package dartdetector
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	pixelconverter "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/pixel-converter"
+)
+
+var (
+	// ErrNoDart is returned if the frames differ too little to contain a new dart.
+	ErrNoDart = errors.New("no dart found")
+	// ErrChangeTooLarge is returned if the frames differ too much for a single dart (e.g. a hand or a takeout).
+	ErrChangeTooLarge = errors.New("change too large for a dart")
+)
+
+type Config struct {
+	// Scale is the downscale factor of the frames before comparing them
+	Scale int
+	// ROI is the region of interest inside the frame in frame pixels. An empty rectangle means the full frame.
+	ROI image.Rectangle
+	// BlurRadius is the radius of the box blur that suppresses noise before the frames are compared. A negative radius
+	// turns the blur off.
+	BlurRadius int
+	// DiffThreshold is the minimum luma difference of a pixel to belong to the dart
+	DiffThreshold uint8
+	// MinBlobSize is the minimum amount of pixels (after downscaling) of a dart
+	MinBlobSize int
+	// MaxChangeRatio is the maximum ratio of changed pixels inside the roi. Larger changes are no single dart.
+	MaxChangeRatio float64
+	// TipDirection is the image direction in which the tip of a dart points (e.g. {0, 1} for a camera above the board).
+	// A zero vector detects the tip as the narrow end of the dart (the flights are wider than the tip).
+	TipDirection image.Point
+}
+
+// DefaultConfig returns a configuration that works for a 1920x1080 camera that looks at a dartboard.
+func DefaultConfig() Config {
+	return Config{
+		Scale:          2,
+		BlurRadius:     1,
+		DiffThreshold:  30,
+		MinBlobSize:    40,
+		MaxChangeRatio: 0.02,
+	}
+}
+
+// Detection is a dart found by a camera. The tip is in frame pixels.
+type Detection struct {
+	CameraID   int               `json:"cameraId"`
+	Tip        calibration.Point `json:"tip"`
+	Confidence float64           `json:"confidence"` // 0 (unsure) - 1 (sure)
+	BlobSize   int               `json:"blobSize"`   // pixels of the dart (after downscaling)
+	Bounds     image.Rectangle   `json:"bounds"`     // bounding box of the dart in frame pixels
+	Angle      float64           `json:"angle"`      // image angle (radians) of the dart axis from the flights to the tip
+}
+
+type Detector struct {
+	cameraID int
+	config   Config
+}
+
+// NewDetector returns a new dart detector for a camera. Zero values of the config are replaced by the default values.
+func NewDetector(cameraID int, config Config) *Detector {
+	d := &Detector{cameraID: cameraID}
+	d.SetConfig(config)
+	return d
+}
+
+// Config returns the configuration of the detector.
+func (d *Detector) Config() Config {
+	return d.config
+}
+
+// SetConfig replaces the configuration of the detector (e.g. while tuning a camera). Zero values are replaced by the default values.
+func (d *Detector) SetConfig(config Config) {
+	defaults := DefaultConfig()
+	if config.Scale == 0 {
+		config.Scale = defaults.Scale
+	}
+	if config.BlurRadius == 0 {
+		config.BlurRadius = defaults.BlurRadius
+	}
+	if config.DiffThreshold == 0 {
+		config.DiffThreshold = defaults.DiffThreshold
+	}
+	if config.MinBlobSize == 0 {
+		config.MinBlobSize = defaults.MinBlobSize
+	}
+	if config.MaxChangeRatio == 0 {
+		config.MaxChangeRatio = defaults.MaxChangeRatio
+	}
+	d.config = config
+}
+
+// Detect finds the dart that was added between the still frames before and after a motion.
+func (d *Detector) Detect(before, after frame.Frame) (Detection, error) {
+	beforeGray, err := before.Gray()
+	if err != nil {
+		return Detection{}, fmt.Errorf("Detect() - error: converting frame (camera-id: %d): %v", d.cameraID, err)
+	}
+	afterGray, err := after.Gray()
+	if err != nil {
+		return Detection{}, fmt.Errorf("Detect() - error: converting frame (camera-id: %d): %v", d.cameraID, err)
+	}
+	return d.DetectGray(beforeGray, afterGray)
+}
+
+// DetectGray finds the dart that was added between two grayscale images (e.g. frames of a recording).
+func (d *Detector) DetectGray(before, after *image.Gray) (Detection, error) {
+	if before.Bounds() != after.Bounds() {
+		return Detection{}, fmt.Errorf("DetectGray() - error: frame sizes differ (camera-id: %d): %v != %v", d.cameraID, before.Bounds(), after.Bounds())
+	}
+
+	scale := max(d.config.Scale, 1)
+	roi := d.scaledROI(before.Bounds(), scale)
+	beforeScaled := boxBlur(crop(pixelconverter.DownscaleGray(before, scale), roi), d.config.BlurRadius)
+	afterScaled := boxBlur(crop(pixelconverter.DownscaleGray(after, scale), roi), d.config.BlurRadius)
+
+	// changed pixels
+	width, height := afterScaled.Bounds().Dx(), afterScaled.Bounds().Dy()
+	mask := make([]bool, width*height)
+	changed := 0
+	for i := range mask {
+		diff := int(afterScaled.Pix[i]) - int(beforeScaled.Pix[i])
+		if diff > int(d.config.DiffThreshold) || -diff > int(d.config.DiffThreshold) {
+			mask[i] = true
+			changed++
+		}
+	}
+	if changed < d.config.MinBlobSize {
+		return Detection{}, fmt.Errorf("DetectGray() - error: %d changed pixels (camera-id: %d): %w", changed, d.cameraID, ErrNoDart)
+	}
+	if ratio := float64(changed) / float64(len(mask)); ratio > d.config.MaxChangeRatio {
+		return Detection{}, fmt.Errorf("DetectGray() - error: %.1f%% changed pixels (camera-id: %d): %w", ratio*100, d.cameraID, ErrChangeTooLarge)
+	}
+
+	dart := largestBlob(mask, width, height)
+	if len(dart) < d.config.MinBlobSize {
+		return Detection{}, fmt.Errorf("DetectGray() - error: largest blob has %d pixels (camera-id: %d): %w", len(dart), d.cameraID, ErrNoDart)
+	}
+
+	shape := analyzeShape(dart)
+	tip, direction, tipCertainty := shape.tip(d.config.TipDirection)
+
+	// back to frame pixels
+	toFrame := func(x, y float64) calibration.Point {
+		return calibration.Point{
+			X: float64(before.Bounds().Min.X) + (x+float64(roi.Min.X)+0.5)*float64(scale) - 0.5,
+			Y: float64(before.Bounds().Min.Y) + (y+float64(roi.Min.Y)+0.5)*float64(scale) - 0.5,
+		}
+	}
+	bounds := image.Rect(
+		(shape.bounds.Min.X+roi.Min.X)*scale, (shape.bounds.Min.Y+roi.Min.Y)*scale,
+		(shape.bounds.Max.X+roi.Min.X)*scale, (shape.bounds.Max.Y+roi.Min.Y)*scale,
+	).Add(before.Bounds().Min)
+
+	// darts are elongated, their blob dominates the changes and the tip end is clearly narrower than the flights
+	dominance := float64(len(dart)) / float64(changed)
+	confidence := shape.elongation() * dominance * (0.5 + 0.5*tipCertainty)
+
+	return Detection{
+		CameraID:   d.cameraID,
+		Tip:        toFrame(tip.X, tip.Y),
+		Confidence: confidence,
+		BlobSize:   len(dart),
+		Bounds:     bounds,
+		Angle:      math.Atan2(direction.Y, direction.X),
+	}, nil
+}
+
+// scaledROI returns the roi in downscaled pixels relative to the image origin.
+func (d *Detector) scaledROI(bounds image.Rectangle, scale int) image.Rectangle {
+	full := image.Rect(0, 0, bounds.Dx()/scale, bounds.Dy()/scale)
+	if d.config.ROI.Empty() {
+		return full
+	}
+	roi := image.Rect(d.config.ROI.Min.X/scale, d.config.ROI.Min.Y/scale, d.config.ROI.Max.X/scale, d.config.ROI.Max.Y/scale).Intersect(full)
+	if roi.Empty() {
+		return full
+	}
+	return roi
+}
+
+// crop returns the roi of the image as a new image with the origin at 0, 0.
+func crop(gray *image.Gray, roi image.Rectangle) *image.Gray {
+	cropped := image.NewGray(image.Rect(0, 0, roi.Dx(), roi.Dy()))
+	for y := 0; y < roi.Dy(); y++ {
+		offset := gray.PixOffset(gray.Bounds().Min.X+roi.Min.X, gray.Bounds().Min.Y+roi.Min.Y+y)
+		copy(cropped.Pix[y*cropped.Stride:(y+1)*cropped.Stride], gray.Pix[offset:offset+roi.Dx()])
+	}
+	return cropped
+}
@@ -0,0 +1,92 @@
+package dartdetector
+
+import (
+	"errors"
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+)
+
+// The frames in testdata are stored frames of two simulated cameras (640x480):
+// the empty board, a dart in T20, a second dart in D16 and a hand in front of the board.
+
+// maxTipError is the maximum distance (pixels) between the detected and the real tip
+const maxTipError = 4.0
+
+func readFrame(t *testing.T, name string) frame.Frame {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return frame.Frame{Data: data, PixFormat: v4l2.PixFormat{Width: 640, Height: 480, PixelFormat: v4l2.PixelFmtMJPEG}}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name          string
+		cameraID      int
+		before, after string
+		tip           calibration.Point // projected tip of the simulated dart
+	}{
+		{name: "first dart", cameraID: 1, before: "camera1-empty.jpg", after: "camera1-T20.jpg", tip: calibration.Point{X: 336.0, Y: 164.9}},
+		{name: "second dart", cameraID: 1, before: "camera1-T20.jpg", after: "camera1-T20-D16.jpg", tip: calibration.Point{X: 190.9, Y: 333.4}},
+		{name: "first dart", cameraID: 2, before: "camera2-empty.jpg", after: "camera2-T20.jpg", tip: calibration.Point{X: 325.0, Y: 143.3}},
+		{name: "second dart", cameraID: 2, before: "camera2-T20.jpg", after: "camera2-T20-D16.jpg", tip: calibration.Point{X: 220.1, Y: 328.9}},
+	}
+	for _, tt := range tests {
+		d := NewDetector(tt.cameraID, Config{})
+		detection, err := d.Detect(readFrame(t, tt.before), readFrame(t, tt.after))
+		if err != nil {
+			t.Errorf("camera %d, %s: %v", tt.cameraID, tt.name, err)
+			continue
+		}
+		if distance := detection.Tip.Distance(tt.tip); distance > maxTipError {
+			t.Errorf("camera %d, %s: tip at %v, want %v (%.1f px off)", tt.cameraID, tt.name, detection.Tip, tt.tip, distance)
+		}
+		if detection.Confidence <= 0 || detection.Confidence > 1 {
+			t.Errorf("camera %d, %s: confidence %.2f out of range", tt.cameraID, tt.name, detection.Confidence)
+		}
+	}
+}
+
+func TestDetectErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		err           error
+	}{
+		{name: "unchanged board", before: "camera1-T20.jpg", after: "camera1-T20.jpg", err: ErrNoDart},
+		{name: "hand in front of the board", before: "camera1-T20-D16.jpg", after: "camera1-hand.jpg", err: ErrChangeTooLarge},
+		{name: "hand in front of the board", before: "camera2-T20-D16.jpg", after: "camera2-hand.jpg", err: ErrChangeTooLarge},
+	}
+	for _, tt := range tests {
+		d := NewDetector(1, Config{})
+		if _, err := d.Detect(readFrame(t, tt.before), readFrame(t, tt.after)); !errors.Is(err, tt.err) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestDetectROI(t *testing.T) {
+	// the second dart is outside of the roi (upper half of the frame)
+	d := NewDetector(1, Config{ROI: image.Rect(0, 0, 640, 240)})
+	if _, err := d.Detect(readFrame(t, "camera1-T20.jpg"), readFrame(t, "camera1-T20-D16.jpg")); !errors.Is(err, ErrNoDart) {
+		t.Errorf("got error %v, want %v", err, ErrNoDart)
+	}
+}
+
+func TestSetConfigDefaults(t *testing.T) {
+	if got := NewDetector(1, Config{}).Config(); got != DefaultConfig() {
+		t.Errorf("config %+v, want the default config %+v", got, DefaultConfig())
+	}
+	// --> a negative blur radius turns the blur off instead of being replaced by the default
+	if got := NewDetector(1, Config{BlurRadius: -1}).Config(); got.BlurRadius != -1 {
+		t.Errorf("blur radius %d, want -1", got.BlurRadius)
+	}
+}
@@ -0,0 +1,214 @@
+package dartdetector
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// boxBlur blurs the image with a (2*radius+1)² box filter. A radius <= 0 returns the hand-overed image.
+func boxBlur(gray *image.Gray, radius int) *image.Gray {
+	if radius <= 0 {
+		return gray
+	}
+	width, height := gray.Bounds().Dx(), gray.Bounds().Dy()
+	tmp := make([]uint8, width*height)
+	blurred := image.NewGray(image.Rect(0, 0, width, height))
+
+	// horizontal pass
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sum, count := 0, 0
+			for dx := -radius; dx <= radius; dx++ {
+				if nx := x + dx; nx >= 0 && nx < width {
+					sum += int(gray.Pix[y*gray.Stride+nx])
+					count++
+				}
+			}
+			tmp[y*width+x] = uint8(sum / count)
+		}
+	}
+	// vertical pass
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sum, count := 0, 0
+			for dy := -radius; dy <= radius; dy++ {
+				if ny := y + dy; ny >= 0 && ny < height {
+					sum += int(tmp[ny*width+x])
+					count++
+				}
+			}
+			blurred.Pix[y*blurred.Stride+x] = uint8(sum / count)
+		}
+	}
+	return blurred
+}
+
+// largestBlob returns the pixels of the largest connected area (8-neighbourhood) of the mask.
+func largestBlob(mask []bool, width, height int) []image.Point {
+	visited := make([]bool, len(mask))
+	var largest []image.Point
+	for start := range mask {
+		if !mask[start] || visited[start] {
+			continue
+		}
+		var blob []image.Point
+		stack := []int{start}
+		visited[start] = true
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			x, y := i%width, i/width
+			blob = append(blob, image.Point{X: x, Y: y})
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= width || ny >= height {
+						continue
+					}
+					if n := ny*width + nx; mask[n] && !visited[n] {
+						visited[n] = true
+						stack = append(stack, n)
+					}
+				}
+			}
+		}
+		if len(blob) > len(largest) {
+			largest = blob
+		}
+	}
+	return largest
+}
+
+type vector struct {
+	X, Y float64
+}
+
+// shape describes a blob by its principal axis.
+type shape struct {
+	pixels []image.Point
+	mean   vector
+	axis   vector // unit vector of the principal axis
+	major  float64
+	minor  float64 // eigenvalues of the covariance (variance along / across the axis)
+	bounds image.Rectangle
+}
+
+func analyzeShape(pixels []image.Point) shape {
+	s := shape{pixels: pixels, bounds: image.Rectangle{Min: pixels[0], Max: pixels[0].Add(image.Point{X: 1, Y: 1})}}
+	for _, p := range pixels {
+		s.mean.X += float64(p.X)
+		s.mean.Y += float64(p.Y)
+		s.bounds = s.bounds.Union(image.Rectangle{Min: p, Max: p.Add(image.Point{X: 1, Y: 1})})
+	}
+	n := float64(len(pixels))
+	s.mean.X /= n
+	s.mean.Y /= n
+
+	var xx, xy, yy float64
+	for _, p := range pixels {
+		dx, dy := float64(p.X)-s.mean.X, float64(p.Y)-s.mean.Y
+		xx += dx * dx
+		xy += dx * dy
+		yy += dy * dy
+	}
+	xx, xy, yy = xx/n, xy/n, yy/n
+
+	// eigenvalues and the eigenvector of the larger eigenvalue of the 2x2 covariance matrix
+	trace, det := xx+yy, xx*yy-xy*xy
+	root := math.Sqrt(math.Max(trace*trace/4-det, 0))
+	s.major, s.minor = trace/2+root, trace/2-root
+	angle := 0.5 * math.Atan2(2*xy, xx-yy)
+	s.axis = vector{X: math.Cos(angle), Y: math.Sin(angle)}
+	return s
+}
+
+// elongation returns 0 for round and 1 for line-shaped blobs.
+func (s shape) elongation() float64 {
+	if s.major <= 0 {
+		return 0
+	}
+	return 1 - math.Sqrt(math.Max(s.minor, 0)/s.major)
+}
+
+// tip returns the tip of the dart, the direction from the flights to the tip and the certainty (0-1) of the tip end.
+// If no tip direction is configured, the tip is the narrower end of the blob.
+func (s shape) tip(tipDirection image.Point) (vector, vector, float64) {
+	type projection struct {
+		along, across float64
+	}
+	projections := make([]projection, len(s.pixels))
+	for i, p := range s.pixels {
+		dx, dy := float64(p.X)-s.mean.X, float64(p.Y)-s.mean.Y
+		projections[i] = projection{along: dx*s.axis.X + dy*s.axis.Y, across: -dx*s.axis.Y + dy*s.axis.X}
+	}
+	sort.Slice(projections, func(i, j int) bool { return projections[i].along < projections[j].along })
+
+	// the outer 20% of the length at both ends
+	first, last := projections[0].along, projections[len(projections)-1].along
+	zone := (last - first) * 0.2
+	var startWidth, endWidth spread
+	for _, p := range projections {
+		if p.along <= first+zone {
+			startWidth.add(p.across)
+		}
+		if p.along >= last-zone {
+			endWidth.add(p.across)
+		}
+	}
+
+	forward := true // tip at the end with the larger projection
+	certainty := 1.0
+	if tipDirection != (image.Point{}) {
+		forward = float64(tipDirection.X)*s.axis.X+float64(tipDirection.Y)*s.axis.Y >= 0
+	} else {
+		start, end := startWidth.width(), endWidth.width()
+		forward = end < start
+		if widest := math.Max(start, end); widest > 0 {
+			certainty = math.Min(math.Abs(start-end)/widest/0.3, 1)
+		} else {
+			certainty = 0
+		}
+	}
+
+	// tip: the outermost point along the axis, centered across the outermost pixels
+	n := max(len(projections)/50, 1)
+	tipPixels := projections[:n]
+	along := first
+	direction := vector{X: -s.axis.X, Y: -s.axis.Y}
+	if forward {
+		tipPixels = projections[len(projections)-n:]
+		along = last
+		direction = s.axis
+	}
+	var across float64
+	for _, p := range tipPixels {
+		across += p.across
+	}
+	across /= float64(n)
+
+	tip := vector{
+		X: s.mean.X + along*s.axis.X - across*s.axis.Y,
+		Y: s.mean.Y + along*s.axis.Y + across*s.axis.X,
+	}
+	return tip, direction, certainty
+}
+
+// spread measures the width of a blob section as standard deviation across the axis.
+type spread struct {
+	n, sum, sumSquares float64
+}
+
+func (s *spread) add(v float64) {
+	s.n++
+	s.sum += v
+	s.sumSquares += v * v
+}
+
+func (s spread) width() float64 {
+	if s.n == 0 {
+		return 0
+	}
+	mean := s.sum / s.n
+	return math.Sqrt(math.Max(s.sumSquares/s.n-mean*mean, 0))
+}
@@ -0,0 +1,132 @@
+package dartfusion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+var ErrNoObservations = errors.New("no observations")
+
+// Observation is the dart position of a single camera, projected to board coordinates (mm).
+type Observation struct {
+	CameraID   int               `json:"cameraId"`
+	Position   calibration.Point `json:"position"`
+	Confidence float64           `json:"confidence"`
+}
+
+type Config struct {
+	// MinConfidence is the minimum confidence of an observation to be taken into account
+	MinConfidence float64
+	// OutlierDistance is the distance (mm) from the consensus position above which an observation is rejected
+	OutlierDistance float64
+	// DisagreementDistance is the maximum spread (mm) of the accepted observations before a throw has to be confirmed manually
+	DisagreementDistance float64
+	// WireTolerance is the distance (mm) to a wire below which differing camera scores have to be confirmed manually
+	WireTolerance float64
+}
+
+// DefaultConfig returns the default fusion configuration.
+func DefaultConfig() Config {
+	return Config{
+		MinConfidence:        0.2,
+		OutlierDistance:      10,
+		DisagreementDistance: 6,
+		WireTolerance:        1.5,
+	}
+}
+
+// Result is the fused position and score of a dart.
+type Result struct {
+	Position          calibration.Point `json:"position"`
+	Score             dartboard.Score   `json:"score"`
+	Confidence        float64           `json:"confidence"`
+	Observations      []Observation     `json:"observations"` // accepted observations
+	Outliers          []Observation     `json:"outliers"`     // rejected observations
+	Spread            float64           `json:"spread"`       // maximum distance (mm) of an accepted observation to the position
+	NeedsConfirmation bool              `json:"needsConfirmation"`
+	Reason            string            `json:"reason,omitempty"` // why the throw has to be confirmed
+}
+
+// Fuse combines the observations of all cameras into one position: observations far away from the consensus are rejected
+// and the remaining positions are averaged weighted by their confidence.
+// If the cameras disagree, the result is flagged for manual confirmation instead of being scored silently.
+func Fuse(board *dartboard.Board, observations []Observation, config Config) (Result, error) {
+	var candidates, outliers []Observation
+	for _, o := range observations {
+		if o.Confidence >= config.MinConfidence && !math.IsNaN(o.Position.X) && !math.IsNaN(o.Position.Y) {
+			candidates = append(candidates, o)
+		} else {
+			outliers = append(outliers, o)
+		}
+	}
+	if len(candidates) == 0 {
+		return Result{}, fmt.Errorf("Fuse() - error: %d observations below the minimum confidence: %w", len(observations), ErrNoObservations)
+	}
+
+	// consensus: the observation with the smallest weighted distance to all others
+	consensus := candidates[0].Position
+	bestCost := math.Inf(1)
+	for _, a := range candidates {
+		var cost float64
+		for _, b := range candidates {
+			cost += b.Confidence * a.Position.Distance(b.Position)
+		}
+		if cost < bestCost {
+			consensus, bestCost = a.Position, cost
+		}
+	}
+
+	var accepted []Observation
+	for _, o := range candidates {
+		if o.Position.Distance(consensus) <= config.OutlierDistance {
+			accepted = append(accepted, o)
+		} else {
+			outliers = append(outliers, o)
+		}
+	}
+
+	// weighted mean of the accepted observations
+	var position calibration.Point
+	var weights float64
+	for _, o := range accepted {
+		position.X += o.Confidence * o.Position.X
+		position.Y += o.Confidence * o.Position.Y
+		weights += o.Confidence
+	}
+	position.X /= weights
+	position.Y /= weights
+
+	result := Result{
+		Position:     position,
+		Score:        board.Score(position.X, position.Y),
+		Confidence:   weights / float64(len(accepted)),
+		Observations: accepted,
+		Outliers:     outliers,
+	}
+	labels := make(map[string]bool)
+	for _, o := range accepted {
+		result.Spread = math.Max(result.Spread, o.Position.Distance(position))
+		labels[board.Score(o.Position.X, o.Position.Y).Label] = true
+	}
+
+	rejected := len(candidates) - len(accepted)
+	switch {
+	case rejected > 0 && len(accepted) <= rejected:
+		result.NeedsConfirmation = true
+		result.Reason = fmt.Sprintf("no camera majority: %d of %d cameras agree", len(accepted), len(candidates))
+	case result.Spread > config.DisagreementDistance:
+		result.NeedsConfirmation = true
+		result.Reason = fmt.Sprintf("cameras disagree by %.1f mm", result.Spread)
+	case len(labels) > 1 && result.Score.NearWire(config.WireTolerance):
+		result.NeedsConfirmation = true
+		result.Reason = fmt.Sprintf("cameras score differently close to a wire (%.1f mm)", result.Score.WireDistance)
+	}
+
+	// less accepted cameras --> less confidence
+	result.Confidence *= float64(len(accepted)) / float64(len(observations))
+	return result, nil
+}
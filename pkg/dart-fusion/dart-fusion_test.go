@@ -0,0 +1,161 @@
+package dartfusion
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+// polar returns the board point at the radius (mm) and the clockwise angle (degrees) from the top of the board.
+func polar(radius, angle float64) calibration.Point {
+	r := angle * math.Pi / 180
+	return calibration.Point{X: radius * math.Sin(r), Y: radius * math.Cos(r)}
+}
+
+func observation(cameraID int, p calibration.Point, confidence float64) Observation {
+	return Observation{CameraID: cameraID, Position: p, Confidence: confidence}
+}
+
+func TestFuse(t *testing.T) {
+	board := dartboard.Standard()
+	tests := []struct {
+		name         string
+		observations []Observation
+		wantLabel    string
+		wantReason   string // empty: no confirmation needed
+		wantOutliers []int  // camera ids
+	}{
+		{
+			name:         "single camera",
+			observations: []Observation{observation(1, polar(103, 0), 0.9)},
+			wantLabel:    "T20",
+		},
+		{
+			name: "three cameras agree",
+			observations: []Observation{
+				observation(1, polar(103, 0), 0.9), observation(2, polar(104, 1), 0.8), observation(3, polar(102, -1), 0.7),
+			},
+			wantLabel: "T20",
+		},
+		{
+			name: "two cameras agree, one outlier",
+			observations: []Observation{
+				observation(1, polar(103, 0), 0.9), observation(2, polar(104, 1), 0.8), observation(3, polar(103, 40), 0.9),
+			},
+			wantLabel:    "T20",
+			wantOutliers: []int{3},
+		},
+		{
+			name: "observation below the minimum confidence",
+			observations: []Observation{
+				observation(1, polar(103, 0), 0.9), observation(2, polar(103, 90), 0.1),
+			},
+			wantLabel:    "T20",
+			wantOutliers: []int{2},
+		},
+		{
+			name: "no camera majority",
+			observations: []Observation{
+				observation(1, polar(103, 0), 0.9), observation(2, polar(103, 40), 0.9),
+			},
+			wantLabel:    "T20",
+			wantReason:   "no camera majority: 1 of 2 cameras agree",
+			wantOutliers: []int{2},
+		},
+		{
+			// --> all observations are within the outlier distance of the consensus, but spread too much
+			name: "cameras disagree",
+			observations: []Observation{
+				observation(1, polar(120, 0), 0.9), observation(2, polar(128, 0), 0.9), observation(3, polar(136, 0), 0.9),
+			},
+			wantLabel:  "S20",
+			wantReason: "cameras disagree by 8.0 mm",
+		},
+		{
+			// --> the wire between the 20 and the 1 is 9 degrees clockwise from the top
+			name: "cameras disagree across a wire",
+			observations: []Observation{
+				observation(1, polar(130, 8.7), 0.9), observation(2, polar(130, 9.3), 0.8),
+			},
+			wantLabel:  "S20",
+			wantReason: "cameras score differently close to a wire",
+		},
+		{
+			name: "cameras agree close to a wire",
+			observations: []Observation{
+				observation(1, polar(130, 8.5), 0.9), observation(2, polar(130, 8.6), 0.8),
+			},
+			wantLabel: "S20",
+		},
+	}
+	for _, tt := range tests {
+		result, err := Fuse(board, tt.observations, DefaultConfig())
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if result.Score.Label != tt.wantLabel {
+			t.Errorf("%s: score %s, want %s", tt.name, result.Score.Label, tt.wantLabel)
+		}
+		if result.NeedsConfirmation != (tt.wantReason != "") || !strings.HasPrefix(result.Reason, tt.wantReason) {
+			t.Errorf("%s: needs confirmation %v (%q), want reason %q", tt.name, result.NeedsConfirmation, result.Reason, tt.wantReason)
+		}
+		var outliers []int
+		for _, o := range result.Outliers {
+			outliers = append(outliers, o.CameraID)
+		}
+		if len(outliers) != len(tt.wantOutliers) || len(result.Observations)+len(outliers) != len(tt.observations) {
+			t.Errorf("%s: outliers %v, want %v", tt.name, outliers, tt.wantOutliers)
+			continue
+		}
+		for i := range outliers {
+			if outliers[i] != tt.wantOutliers[i] {
+				t.Errorf("%s: outliers %v, want %v", tt.name, outliers, tt.wantOutliers)
+				break
+			}
+		}
+	}
+}
+
+// the position is the mean of the accepted observations weighted by their confidence, the confidence drops with every
+// rejected camera
+func TestFuseWeightedMean(t *testing.T) {
+	observations := []Observation{
+		observation(1, calibration.Point{X: 0, Y: 100}, 1),
+		observation(2, calibration.Point{X: 3, Y: 100}, 0.5),
+		observation(3, calibration.Point{X: 60, Y: 60}, 1), // outlier
+	}
+	result, err := Fuse(dartboard.Standard(), observations, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Position.X-1) > 1e-9 || math.Abs(result.Position.Y-100) > 1e-9 {
+		t.Errorf("position %v, want (1, 100)", result.Position)
+	}
+	if want := 0.75 * 2 / 3; math.Abs(result.Confidence-want) > 1e-9 {
+		t.Errorf("confidence %f, want %f", result.Confidence, want)
+	}
+	if math.Abs(result.Spread-2) > 1e-9 {
+		t.Errorf("spread %f, want 2", result.Spread)
+	}
+}
+
+func TestFuseWithoutObservations(t *testing.T) {
+	tests := []struct {
+		name         string
+		observations []Observation
+	}{
+		{name: "no observations"},
+		{name: "below the minimum confidence", observations: []Observation{observation(1, polar(103, 0), 0.1)}},
+		{name: "invalid position", observations: []Observation{observation(1, calibration.Point{X: math.NaN()}, 0.9)}},
+	}
+	for _, tt := range tests {
+		if _, err := Fuse(dartboard.Standard(), tt.observations, DefaultConfig()); !errors.Is(err, ErrNoObservations) {
+			t.Errorf("%s: error %v, want ErrNoObservations", tt.name, err)
+		}
+	}
+}
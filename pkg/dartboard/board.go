@@ -0,0 +1,117 @@
+package dartboard
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// dimensions of a standard steel-tip dartboard in millimetres (radius to the outer edge of the wire)
+const (
+	StandardInnerBullRadius   = 6.35
+	StandardOuterBullRadius   = 15.9
+	StandardTrebleInnerRadius = 99.0
+	StandardTrebleOuterRadius = 107.0
+	StandardDoubleInnerRadius = 162.0
+	StandardDoubleOuterRadius = 170.0
+)
+
+// SegmentOrder is the clockwise order of the segments, starting at the top of the board.
+var SegmentOrder = []int{20, 1, 18, 4, 13, 6, 10, 15, 2, 17, 3, 19, 7, 16, 8, 11, 14, 9, 12, 5}
+
+// segmentAngle is the angle (degrees) that is covered by one segment
+const segmentAngle = 360.0 / 20
+
+var ErrInvalidDimensions = errors.New("invalid board dimensions")
+
+// Dimensions are the radii (in mm) of the rings of a board.
+type Dimensions struct {
+	InnerBullRadius   float64 `json:"innerBullRadius"`
+	OuterBullRadius   float64 `json:"outerBullRadius"`
+	TrebleInnerRadius float64 `json:"trebleInnerRadius"`
+	TrebleOuterRadius float64 `json:"trebleOuterRadius"`
+	DoubleInnerRadius float64 `json:"doubleInnerRadius"`
+	DoubleOuterRadius float64 `json:"doubleOuterRadius"`
+}
+
+// StandardDimensions returns the dimensions of a standard steel-tip dartboard.
+func StandardDimensions() Dimensions {
+	return Dimensions{
+		InnerBullRadius:   StandardInnerBullRadius,
+		OuterBullRadius:   StandardOuterBullRadius,
+		TrebleInnerRadius: StandardTrebleInnerRadius,
+		TrebleOuterRadius: StandardTrebleOuterRadius,
+		DoubleInnerRadius: StandardDoubleInnerRadius,
+		DoubleOuterRadius: StandardDoubleOuterRadius,
+	}
+}
+
+// Validate checks that all radii are positive and strictly increasing from the bull to the double ring.
+func (d Dimensions) Validate() error {
+	radii := []float64{0, d.InnerBullRadius, d.OuterBullRadius, d.TrebleInnerRadius, d.TrebleOuterRadius, d.DoubleInnerRadius, d.DoubleOuterRadius}
+	for i := 1; i < len(radii); i++ {
+		if radii[i] <= radii[i-1] {
+			return fmt.Errorf("Validate() - error: radii have to increase from the bull to the double ring: %w", ErrInvalidDimensions)
+		}
+	}
+	return nil
+}
+
+// Board models a dartboard. Board coordinates are in mm with the origin at the bull and the y-axis pointing to the 20,
+// independent of how the board hangs.
+type Board struct {
+	dimensions Dimensions
+	rotation   float64 // degrees, clockwise
+}
+
+type Option func(*Board)
+
+// WithRotation sets the clockwise rotation of the hung board in degrees (e.g. for boards that are not hung with the 20 at the top).
+// The board coordinates are not rotated: the calibration reference points are named after the wires, so calibrated coordinates
+// always have the 20 at the top. The rotation only decides which segment is expected at the top of a camera image (see TopSegment).
+func WithRotation(degrees float64) Option {
+	return func(b *Board) {
+		b.rotation = degrees
+	}
+}
+
+// WithDimensions replaces the standard dimensions (e.g. for non-standard boards).
+func WithDimensions(dimensions Dimensions) Option {
+	return func(b *Board) {
+		b.dimensions = dimensions
+	}
+}
+
+// NewBoard returns a standard steel-tip dartboard with the 20 at the top, if no options are hand-overed.
+func NewBoard(options ...Option) (*Board, error) {
+	b := &Board{dimensions: StandardDimensions()}
+	for _, option := range options {
+		option(b)
+	}
+	if err := b.dimensions.Validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Standard returns a standard steel-tip dartboard with the 20 at the top.
+func Standard() *Board {
+	return &Board{dimensions: StandardDimensions()}
+}
+
+// Dimensions returns the ring radii of the board.
+func (b *Board) Dimensions() Dimensions {
+	return b.dimensions
+}
+
+// Rotation returns the clockwise rotation of the hung board in degrees.
+func (b *Board) Rotation() float64 {
+	return b.rotation
+}
+
+// TopSegment returns the segment at the top of the hung board (20 for a board without rotation).
+func (b *Board) TopSegment() int {
+	n := len(SegmentOrder)
+	steps := int(math.Round(b.rotation/segmentAngle)) % n
+	return SegmentOrder[(n-steps)%n]
+}
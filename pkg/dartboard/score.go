@@ -0,0 +1,161 @@
+package dartboard
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Ring int
+
+const (
+	RingMiss Ring = iota
+	RingInnerBull
+	RingOuterBull
+	RingInnerSingle // between the outer bull and the treble ring
+	RingTreble
+	RingOuterSingle // between the treble and the double ring
+	RingDouble
+)
+
+func (r Ring) String() string {
+	switch r {
+	case RingMiss:
+		return "miss"
+	case RingInnerBull:
+		return "inner bull"
+	case RingOuterBull:
+		return "outer bull"
+	case RingInnerSingle:
+		return "inner single"
+	case RingTreble:
+		return "treble"
+	case RingOuterSingle:
+		return "outer single"
+	case RingDouble:
+		return "double"
+	default:
+		return fmt.Sprintf("Ring(%d)", int(r))
+	}
+}
+
+// Score is the result of a hit on the board.
+type Score struct {
+	Segment    int    `json:"segment"`    // 1-20, 25 for the bull, 0 for a miss
+	Multiplier int    `json:"multiplier"` // 1-3, 0 for a miss
+	Ring       Ring   `json:"ring"`
+	Value      int    `json:"value"` // points of the hit
+	Label      string `json:"label"` // e.g. "T20", "D16", "S5", "BULL", "25" or "MISS"
+	// WireDistance is the distance (mm) to the closest wire. Hits close to a wire are uncertain.
+	WireDistance float64 `json:"wireDistance"`
+}
+
+// NearWire reports whether the hit is closer to a wire than the hand-overed tolerance (mm).
+func (s Score) NearWire(tolerance float64) bool {
+	return s.WireDistance < tolerance
+}
+
+func (s Score) String() string {
+	return s.Label
+}
+
+// Miss is the score of a dart outside the scoring area.
+var Miss = Score{Ring: RingMiss, Label: "MISS"}
+
+// Score maps a board coordinate (mm, origin at the bull, y pointing up) to the score.
+func (b *Board) Score(x, y float64) Score {
+	d := b.dimensions
+	r := math.Hypot(x, y)
+
+	// distance to the closest ring wire
+	wireDistance := math.Inf(1)
+	for _, radius := range []float64{d.InnerBullRadius, d.OuterBullRadius, d.TrebleInnerRadius, d.TrebleOuterRadius, d.DoubleInnerRadius, d.DoubleOuterRadius} {
+		wireDistance = math.Min(wireDistance, math.Abs(r-radius))
+	}
+
+	switch {
+	case r <= d.InnerBullRadius:
+		return Score{Segment: 25, Multiplier: 2, Ring: RingInnerBull, Value: 50, Label: "BULL", WireDistance: wireDistance}
+	case r <= d.OuterBullRadius:
+		return Score{Segment: 25, Multiplier: 1, Ring: RingOuterBull, Value: 25, Label: "25", WireDistance: wireDistance}
+	case r > d.DoubleOuterRadius:
+		miss := Miss
+		miss.WireDistance = wireDistance
+		return miss
+	}
+
+	// segment: clockwise angle from the left wire of the 20 (board coordinates are not rotated, see WithRotation)
+	angle := math.Atan2(y, x) * 180 / math.Pi
+	offset := math.Mod(90-angle+segmentAngle/2, 360)
+	if offset < 0 {
+		offset += 360
+	}
+	idx := int(offset/segmentAngle) % len(SegmentOrder)
+	within := offset - float64(idx)*segmentAngle
+	radialWireDistance := r * math.Sin(math.Min(within, segmentAngle-within)*math.Pi/180)
+	wireDistance = math.Min(wireDistance, radialWireDistance)
+
+	segment := SegmentOrder[idx]
+	s := Score{Segment: segment, WireDistance: wireDistance}
+	switch {
+	case r <= d.TrebleInnerRadius:
+		s.Ring, s.Multiplier, s.Label = RingInnerSingle, 1, fmt.Sprintf("S%d", segment)
+	case r <= d.TrebleOuterRadius:
+		s.Ring, s.Multiplier, s.Label = RingTreble, 3, fmt.Sprintf("T%d", segment)
+	case r <= d.DoubleInnerRadius:
+		s.Ring, s.Multiplier, s.Label = RingOuterSingle, 1, fmt.Sprintf("S%d", segment)
+	default:
+		s.Ring, s.Multiplier, s.Label = RingDouble, 2, fmt.Sprintf("D%d", segment)
+	}
+	s.Value = segment * s.Multiplier
+	return s
+}
+
+// SegmentAngle returns the angle (degrees, counterclockwise from the positive x-axis) of the center of a segment.
+func (b *Board) SegmentAngle(segment int) (float64, error) {
+	for i, s := range SegmentOrder {
+		if s == segment {
+			return 90 - float64(i)*segmentAngle, nil
+		}
+	}
+	return 0, fmt.Errorf("SegmentAngle() - error: unknown segment %d", segment)
+}
+
+// Target returns the board coordinate (mm) of the center of the area of a score label, e.g. "T20", "D16", "S5", "BULL" or "25".
+// "S<n>" is the center of the outer single area.
+func (b *Board) Target(label string) (float64, float64, error) {
+	d := b.dimensions
+	label = strings.ToUpper(strings.TrimSpace(label))
+	switch label {
+	case "BULL":
+		return 0, 0, nil
+	case "25":
+		radius := (d.InnerBullRadius + d.OuterBullRadius) / 2
+		return 0, radius, nil
+	case "":
+		return 0, 0, fmt.Errorf("Target() - error: empty label")
+	}
+
+	var radius float64
+	switch label[0] {
+	case 'S':
+		radius = (d.TrebleOuterRadius + d.DoubleInnerRadius) / 2
+	case 'T':
+		radius = (d.TrebleInnerRadius + d.TrebleOuterRadius) / 2
+	case 'D':
+		radius = (d.DoubleInnerRadius + d.DoubleOuterRadius) / 2
+	default:
+		return 0, 0, fmt.Errorf("Target() - error: invalid label %q", label)
+	}
+	segment, err := strconv.Atoi(label[1:])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Target() - error: invalid label %q", label)
+	}
+	angle, err := b.SegmentAngle(segment)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Target() - error: invalid label %q", label)
+	}
+	angle *= math.Pi / 180
+	return radius * math.Cos(angle), radius * math.Sin(angle), nil
+}
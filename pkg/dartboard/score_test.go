@@ -0,0 +1,94 @@
+package dartboard
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	b := Standard()
+	tests := []struct {
+		x, y  float64
+		label string
+		value int
+	}{
+		{0, 0, "BULL", 50},
+		{0, 10, "25", 25},
+		{0, 103, "T20", 60},
+		{0, 166, "D20", 40},
+		{0, 140, "S20", 20},
+		{0, 50, "S20", 20},
+		{103, 0, "T6", 18},
+		{-166, 0, "D11", 22},
+		{0, -103, "T3", 9},
+		{0, 200, "MISS", 0},
+	}
+	for _, tt := range tests {
+		if s := b.Score(tt.x, tt.y); s.Label != tt.label || s.Value != tt.value {
+			t.Errorf("Score(%v, %v) = %s (%d), want %s (%d)", tt.x, tt.y, s.Label, s.Value, tt.label, tt.value)
+		}
+	}
+}
+
+func TestTargetScoresItsLabel(t *testing.T) {
+	for _, rotation := range []float64{0, 18, 90, -45} {
+		b, err := NewBoard(WithRotation(rotation))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, segment := range SegmentOrder {
+			for _, prefix := range []string{"S", "D", "T"} {
+				label := prefix + strconv.Itoa(segment)
+				x, y, err := b.Target(label)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if s := b.Score(x, y); s.Label != label {
+					t.Errorf("rotation %v: Score(Target(%s)) = %s", rotation, label, s.Label)
+				}
+			}
+		}
+	}
+}
+
+// the rotation describes the hung board only, the board coordinates always have the 20 at the top
+func TestRotationDoesNotChangeBoardCoordinates(t *testing.T) {
+	standard := Standard()
+	rotated, err := NewBoard(WithRotation(18))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for angle := 0.0; angle < 360; angle += 7 {
+		for _, radius := range []float64{5, 50, 103, 140, 166} {
+			x, y := radius*math.Cos(angle*math.Pi/180), radius*math.Sin(angle*math.Pi/180)
+			if a, b := standard.Score(x, y), rotated.Score(x, y); a.Label != b.Label {
+				t.Errorf("Score(%.1f, %.1f): standard %s, rotated %s", x, y, a.Label, b.Label)
+			}
+		}
+	}
+}
+
+func TestTopSegment(t *testing.T) {
+	tests := []struct {
+		rotation float64
+		segment  int
+	}{
+		{0, 20},
+		{18, 5},
+		{-18, 1},
+		{90, 11},
+		{180, 3},
+		{360, 20},
+		{10, 5}, // rounded to the closest segment
+	}
+	for _, tt := range tests {
+		b, err := NewBoard(WithRotation(tt.rotation))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := b.TopSegment(); got != tt.segment {
+			t.Errorf("rotation %v: TopSegment() = %d, want %d", tt.rotation, got, tt.segment)
+		}
+	}
+}
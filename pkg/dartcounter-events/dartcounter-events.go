@@ -0,0 +1,217 @@
+package dartcounterevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Version is the version of the event schema. It is increased with every change of a payload that is not backwards
+// compatible (new event types and new optional fields keep the version).
+const Version = 1
+
+// Type is the sse event type ("event:" line) of an event. It determines the payload of the "data:" line.
+//
+// Every event has an id ("id:" line) that is increasing monotonically, also across restarts of the dartcounter server if
+// the matches are persisted.
+type Type string
+
+const (
+	// MatchCreated is sent when a match was started. It becomes the running match that counts the detections; the events
+	// of every match refer to it by the match id of the header. Payload: MatchCreatedEvent.
+	MatchCreated Type = "match-created"
+	// ThrowRegistered is sent for every dart that was counted. Payload: ThrowRegisteredEvent.
+	ThrowRegistered Type = "throw-registered"
+	// VisitEnded is sent after the last dart of a visit (three darts, bust, checkout or takeout). Payload: VisitEndedEvent.
+	VisitEnded Type = "visit-ended"
+	// Bust is sent if a visit exceeded the remaining score. The score is reverted to the start of the visit. Payload: BustEvent.
+	Bust Type = "bust"
+	// PlayerChanged is sent when the next player is up. Payload: PlayerEvent.
+	PlayerChanged Type = "player-changed"
+	// LegStarted is sent when a leg starts. The player throws first. Payload: LegEvent.
+	LegStarted Type = "leg-started"
+	// LegWon is sent when a player won a leg. Payload: LegEvent.
+	LegWon Type = "leg-won"
+	// SetWon is sent when a player won a set. Payload: LegEvent (without the leg).
+	SetWon Type = "set-won"
+	// MatchWon is sent when a player won the match. Payload: PlayerEvent.
+	MatchWon Type = "match-won"
+	// MatchEnded is sent when the match ended without a winner because nobody won the leg (e.g. a lost solo training leg).
+	// Payload: PlayerEvent (the current player).
+	MatchEnded Type = "match-ended"
+	// MatchAbandoned is sent when the match was ended without a winner. Payload: PlayerEvent (the current player).
+	MatchAbandoned Type = "match-abandoned"
+	// PlayerEliminated is sent when a player is out of the game (e.g. killer). Payload: PlayerEvent.
+	PlayerEliminated Type = "player-eliminated"
+	// ThrowUndone is sent when the last dart (or the early end of a visit) was undone. Payload: PlayerEvent.
+	ThrowUndone Type = "throw-undone"
+	// Correction is sent when a dart of the running or the previous visit was corrected. Payload: CorrectionEvent.
+	Correction Type = "correction"
+	// MatchState is sent after every change of a match. Payload: MatchStateEvent.
+	MatchState Type = "match-state"
+	// ThrowPending is sent when a detected throw needs a confirmation before it is counted. The detections after it wait
+	// until it is confirmed or rejected. Payload: PendingThrowEvent.
+	ThrowPending Type = "throw-pending"
+	// ThrowRejected is sent when a pending throw was rejected and is not counted. Payload: PendingThrowEvent.
+	ThrowRejected Type = "throw-rejected"
+
+	// ThrowDetected, TakeoutDetected and BounceOutDetected are sent for the events of the detection pipeline, even if no
+	// match is running. Payload: DetectionEvent.
+	ThrowDetected     Type = "throw"
+	TakeoutDetected   Type = "takeout"
+	BounceOutDetected Type = "bounce-out"
+)
+
+// Header is part of every payload.
+type Header struct {
+	Version int       `json:"version"`
+	Type    Type      `json:"type"`
+	MatchID int       `json:"matchId"` // id of the match (see the dartcounter matches api), 0 if no match is running
+	Time    time.Time `json:"time"`
+}
+
+// NewHeader returns the header of an event of the current schema version.
+func NewHeader(eventType Type, matchID int) Header {
+	return Header{Version: Version, Type: eventType, MatchID: matchID, Time: time.Now()}
+}
+
+// Dart is a counted dart.
+type Dart struct {
+	Segment    int    `json:"segment"`    // 1-20, 25 for the bull, 0 for a miss
+	Multiplier int    `json:"multiplier"` // 1-3, 0 for a miss
+	Label      string `json:"label"`      // e.g. "T20", "D16", "S5", "BULL", "25" or "MISS"
+}
+
+// MatchCreatedEvent is the payload of MatchCreated.
+type MatchCreatedEvent struct {
+	Header
+	Players     []string        `json:"players"` // the player fields of all other events are indexes into this list
+	Mode        string          `json:"mode"`
+	GameOptions json.RawMessage `json:"gameOptions,omitempty"` // options of the mode
+	Legs        int             `json:"legs"`                  // legs to win a set (or the match)
+	Sets        int             `json:"sets"`                  // best of N sets (odd), 0 if the match is not played in sets
+}
+
+// ThrowRegisteredEvent is the payload of ThrowRegistered.
+type ThrowRegisteredEvent struct {
+	Header
+	Player int  `json:"player"`
+	Dart   Dart `json:"dart"`
+	Points int  `json:"points"`          // points of the dart in the game mode
+	Marks  int  `json:"marks,omitempty"` // marks of the dart on a cricket number
+}
+
+// VisitEndedEvent is the payload of VisitEnded.
+type VisitEndedEvent struct {
+	Header
+	Player int    `json:"player"`
+	Darts  []Dart `json:"darts"`
+	Points int    `json:"points"` // points of the visit, negative if points were lost
+}
+
+// BustEvent is the payload of Bust.
+type BustEvent struct {
+	Header
+	Player int    `json:"player"`
+	Darts  []Dart `json:"darts"`
+}
+
+// PlayerEvent is the payload of the events that only refer to a player.
+type PlayerEvent struct {
+	Header
+	Player int `json:"player"`
+}
+
+// LegEvent is the payload of LegStarted, LegWon and SetWon.
+type LegEvent struct {
+	Header
+	Player int `json:"player"` // the starter (leg-started) or the winner
+	Set    int `json:"set"`    // number of the set, 1 if the match is not played in sets
+	Leg    int `json:"leg"`    // number of the leg inside the set, 0 for set-won
+}
+
+// CorrectionEvent is the payload of Correction.
+type CorrectionEvent struct {
+	Header
+	Player   int    `json:"player"`
+	Dart     Dart   `json:"dart"`     // the corrected dart
+	Previous Dart   `json:"previous"` // the replaced dart
+	Darts    []Dart `json:"darts"`    // counted darts of the corrected visit (without the darts after a bust or a checkout)
+}
+
+// PendingThrowEvent is the payload of ThrowPending and ThrowRejected.
+type PendingThrowEvent struct {
+	Header
+	Dart       Dart    `json:"dart"`       // the detected dart
+	Reason     string  `json:"reason"`     // why the throw needs a confirmation
+	Confidence float64 `json:"confidence"` // 0 (unsure) - 1 (sure)
+}
+
+// MatchStateEvent is the payload of MatchState. The state is the json of the dartcounter match state
+// (GET /dartcounter/matches/{matchID}), its game details depend on the mode.
+type MatchStateEvent struct {
+	Header
+	State json.RawMessage `json:"state"`
+}
+
+// DetectionEvent is the payload of ThrowDetected, TakeoutDetected and BounceOutDetected.
+type DetectionEvent struct {
+	Header
+	Timestamp time.Time      `json:"timestamp"`        // time of the detection
+	Manual    bool           `json:"manual,omitempty"` // the detection was triggered manually
+	Throw     *DetectedThrow `json:"throw,omitempty"`  // the detected dart (throw only)
+}
+
+// DetectedThrow is a dart detected by the cameras.
+type DetectedThrow struct {
+	Dart              Dart    `json:"dart"`
+	X                 float64 `json:"x"`          // position on the board (mm, origin in the center of the bull, 20 at the top)
+	Y                 float64 `json:"y"`          // position on the board (mm)
+	Confidence        float64 `json:"confidence"` // 0 (unsure) - 1 (sure)
+	NeedsConfirmation bool    `json:"needsConfirmation"`
+	Reason            string  `json:"reason,omitempty"` // why the throw needs a confirmation
+}
+
+// Decode decodes the data of an sse event of the hand-overed type into its payload (a pointer to one of the event types).
+// Events of an unknown type or a newer schema version return an error.
+func Decode(eventType string, data []byte) (any, error) {
+	var payload any
+	switch Type(eventType) {
+	case MatchCreated:
+		payload = &MatchCreatedEvent{}
+	case ThrowRegistered:
+		payload = &ThrowRegisteredEvent{}
+	case VisitEnded:
+		payload = &VisitEndedEvent{}
+	case Bust:
+		payload = &BustEvent{}
+	case PlayerChanged, MatchWon, MatchEnded, MatchAbandoned, PlayerEliminated, ThrowUndone:
+		payload = &PlayerEvent{}
+	case LegStarted, LegWon, SetWon:
+		payload = &LegEvent{}
+	case Correction:
+		payload = &CorrectionEvent{}
+	case MatchState:
+		payload = &MatchStateEvent{}
+	case ThrowPending, ThrowRejected:
+		payload = &PendingThrowEvent{}
+	case ThrowDetected, TakeoutDetected, BounceOutDetected:
+		payload = &DetectionEvent{}
+	default:
+		return nil, fmt.Errorf("Decode() - error: unknown event type %q", eventType)
+	}
+
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("Decode() - error: %v", err)
+	}
+	if header.Version > Version {
+		return nil, fmt.Errorf("Decode() - error: unsupported schema version %d (supported: %d)", header.Version, Version)
+	}
+	if err := json.Unmarshal(data, payload); err != nil {
+		return nil, fmt.Errorf("Decode() - error: %v", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,72 @@
+package detectionpipeline
+
+import (
+	"fmt"
+	"time"
+
+	motiondetector "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/motion-detector"
+	dartfusion "github.com/One-Hundred-Eighty/Circle/pkg/dart-fusion"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+type BounceOutConfig struct {
+	// MaxMotionDuration is the maximum duration of a motion that counts as thrown dart.
+	// Longer motions without a persistent change are ignored (e.g. a player walking in front of the board).
+	MaxMotionDuration time.Duration
+	// MaxMotionRatio is the maximum ratio of pixels that change between two frames during the motion of a thrown dart.
+	// Larger motions without a persistent change are ignored (e.g. a hand passing in front of the board).
+	MaxMotionRatio float64
+}
+
+// DefaultBounceOutConfig returns the default bounce-out configuration.
+func DefaultBounceOutConfig() BounceOutConfig {
+	return BounceOutConfig{
+		MaxMotionDuration: time.Second,
+		MaxMotionRatio:    0.01,
+	}
+}
+
+// detectBounceOut reports whether a group of settled motions without persistent change was a thrown dart that did not
+// stay in the board: every motion was short and small. The returned result is the missed dart, it needs a confirmation
+// unless every calibrated camera saw the motion.
+// The returned timestamp is the start of the earliest motion (the moment of the throw).
+func (p *Pipeline) detectBounceOut(group map[int]motiondetector.Event, motionStarts map[int]time.Time) (time.Time, dartfusion.Result, bool) {
+	var throwTime time.Time
+	for cameraID, event := range group {
+		start, ok := motionStarts[cameraID]
+		if !ok || event.Timestamp.Sub(start) > p.bounceOutConfig.MaxMotionDuration {
+			return time.Time{}, dartfusion.Result{}, false
+		}
+		if event.MotionRatio > p.bounceOutConfig.MaxMotionRatio {
+			// --> too large for a dart
+			return time.Time{}, dartfusion.Result{}, false
+		}
+		if throwTime.IsZero() || start.Before(throwTime) {
+			throwTime = start
+		}
+	}
+	if throwTime.IsZero() {
+		return time.Time{}, dartfusion.Result{}, false
+	}
+
+	var calibrated, seen int
+	for _, cameraID := range p.cameraAdmin.CameraIDs() {
+		if _, ok := p.calibrationStore.Get(cameraID); !ok {
+			continue
+		}
+		calibrated++
+		if _, ok := group[cameraID]; ok {
+			seen++
+		}
+	}
+	result := dartfusion.Result{Score: dartboard.Miss, Confidence: 1}
+	if calibrated == 0 || seen < calibrated {
+		result.NeedsConfirmation = true
+		result.Reason = fmt.Sprintf("bounce-out seen by %d of %d calibrated cameras", seen, calibrated)
+		result.Confidence = 0
+		if calibrated > 0 {
+			result.Confidence = float64(seen) / float64(calibrated)
+		}
+	}
+	return throwTime, result, true
+}
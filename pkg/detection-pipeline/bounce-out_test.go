@@ -0,0 +1,129 @@
+package detectionpipeline
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	motiondetector "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/motion-detector"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+// frame sequences of a camera (30 fps)
+var (
+	// a dart flies through the picture and drops out of the board
+	flyingDart = [][]image.Rectangle{nil, nil, nil, {image.Rect(30, 10, 34, 13)}, {image.Rect(31, 20, 35, 23)}, nil, nil, nil, nil}
+	// a hand passes in front of the board
+	handPass = [][]image.Rectangle{nil, nil, nil, {image.Rect(0, 0, 16, testHeight)}, {image.Rect(16, 0, 32, testHeight)},
+		{image.Rect(32, 0, 48, testHeight)}, {image.Rect(48, 0, 64, testHeight)}, nil, nil, nil, nil}
+)
+
+// slowly returns a sequence that moves for more than a second.
+func slowly(sequence [][]image.Rectangle) [][]image.Rectangle {
+	var slow [][]image.Rectangle
+	for _, rects := range sequence {
+		for i := 0; i < 15; i++ {
+			slow = append(slow, rects)
+		}
+	}
+	return slow
+}
+
+// motionEvents feeds the frame sequence of a camera into a motion detector and returns its events.
+func motionEvents(t *testing.T, cameraID int, sequence [][]image.Rectangle) []motiondetector.Event {
+	t.Helper()
+	start := time.Now()
+	detector := motiondetector.NewDetector(cameraID, motiondetector.Config{Scale: 1, SettleFrames: 3})
+	var events []motiondetector.Event
+	for i, rects := range sequence {
+		f := grayFrame(cameraID, rects...)
+		f.Timestamp = start.Add(time.Duration(i) * time.Second / 30)
+		frameEvents, err := detector.Process(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, frameEvents...)
+	}
+	return events
+}
+
+func TestBounceOut(t *testing.T) {
+	tests := []struct {
+		name       string
+		sequences  map[int][][]image.Rectangle // per camera, cameras 1 and 2 are calibrated
+		want       bool
+		wantReason string // empty: no confirmation needed
+	}{
+		{name: "all cameras see the dart", sequences: map[int][][]image.Rectangle{1: flyingDart, 2: flyingDart}, want: true},
+		{
+			name:       "one camera sees the dart",
+			sequences:  map[int][][]image.Rectangle{1: flyingDart},
+			want:       true,
+			wantReason: "bounce-out seen by 1 of 2 calibrated cameras",
+		},
+		{name: "hand passes", sequences: map[int][][]image.Rectangle{1: handPass, 2: handPass}},
+		{name: "hand passes one camera", sequences: map[int][][]image.Rectangle{1: handPass, 2: flyingDart}},
+		{name: "motion too long", sequences: map[int][][]image.Rectangle{1: slowly(flyingDart), 2: slowly(flyingDart)}},
+	}
+	for _, tt := range tests {
+		calibrationStore := testCalibrationStore{1: calibration.Calibration{CameraID: 1}, 2: calibration.Calibration{CameraID: 2}}
+		p := NewPipeline(newTestCameraAdmin(1, 2, 3), calibrationStore, dartboard.Standard())
+		eventCh := p.Subscribe()
+
+		group := make(map[int]motiondetector.Event)
+		motionStarts := make(map[int]time.Time)
+		for cameraID, sequence := range tt.sequences {
+			for _, event := range motionEvents(t, cameraID, sequence) {
+				switch event.Type {
+				case motiondetector.MotionStarted:
+					motionStarts[cameraID] = event.Timestamp
+				case motiondetector.MotionSettled, motiondetector.PersistentChange:
+					group[cameraID] = event
+				}
+			}
+		}
+		if _, ok := group[1]; !ok {
+			t.Fatalf("%s: motion of camera 1 not settled", tt.name)
+		}
+		p.processGroup(group, motionStarts)
+
+		events := receive(eventCh, 50*time.Millisecond)
+		p.Unsubscribe(eventCh)
+		if !tt.want {
+			if len(events) > 0 {
+				t.Errorf("%s: events %+v, want none", tt.name, events)
+			}
+			continue
+		}
+		if len(events) != 1 || events[0].Type != BounceOut || events[0].Throw == nil {
+			t.Errorf("%s: events %+v, want a bounce-out", tt.name, events)
+			continue
+		}
+		result := events[0].Throw
+		if result.Score.Label != dartboard.Miss.Label {
+			t.Errorf("%s: score %s, want a miss", tt.name, result.Score.Label)
+		}
+		if result.NeedsConfirmation != (tt.wantReason != "") || result.Reason != tt.wantReason {
+			t.Errorf("%s: needs confirmation %v (%q), want reason %q", tt.name, result.NeedsConfirmation, result.Reason, tt.wantReason)
+		}
+	}
+}
+
+// the motion ratio of a hand pass is far above the motion of a dart
+func TestMotionRatio(t *testing.T) {
+	ratio := func(sequence [][]image.Rectangle) float64 {
+		events := motionEvents(t, 1, sequence)
+		if len(events) == 0 {
+			t.Fatal("no motion")
+		}
+		return events[len(events)-1].MotionRatio
+	}
+	config := DefaultBounceOutConfig()
+	if r := ratio(flyingDart); r == 0 || r > config.MaxMotionRatio {
+		t.Errorf("motion ratio of a dart %f, want up to %f", r, config.MaxMotionRatio)
+	}
+	if r := ratio(handPass); r <= config.MaxMotionRatio {
+		t.Errorf("motion ratio of a hand %f, want more than %f", r, config.MaxMotionRatio)
+	}
+}
@@ -0,0 +1,362 @@
+package detectionpipeline
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	motiondetector "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/motion-detector"
+	dartdetector "github.com/One-Hundred-Eighty/Circle/pkg/dart-detector"
+	dartfusion "github.com/One-Hundred-Eighty/Circle/pkg/dart-fusion"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+	dartmasterlogger "github.com/One-Hundred-Eighty/Circle/pkg/dartmaster-logger"
+	subscriptionhandler "github.com/One-Hundred-Eighty/Circle/pkg/subscription-handler"
+)
+
+const subscriberName = "detection-pipeline"
+
+// CameraAdmin provides the motion events of the cameras.
+type CameraAdmin interface {
+	CameraIDs() []int
+	SubscribeMotion(cameraID int, subscriberName string) (<-chan motiondetector.Event, error)
+	UnsubscribeMotion(cameraID int, eventCh <-chan motiondetector.Event, subscriberName string)
+}
+
+// CalibrationStore provides the calibrations of the cameras.
+type CalibrationStore interface {
+	Get(cameraID int) (calibration.Calibration, bool)
+}
+
+type EventType string
+
+const (
+	// Throw is emitted when a new dart was detected in the board.
+	Throw EventType = "throw"
+	// Takeout is emitted when the darts were removed from the board.
+	Takeout EventType = "takeout"
+	// BounceOut is emitted for a throw without a dart in the board (e.g. a bounce-out). It counts as missed dart, the
+	// throw of the event is the missed dart (it may need a confirmation).
+	BounceOut EventType = "bounce-out"
+)
+
+// Event is a detection event of the pipeline.
+type Event struct {
+	Type       EventType                `json:"type"`
+	Timestamp  time.Time                `json:"timestamp"`
+	Throw      *dartfusion.Result       `json:"throw,omitempty"`
+	Detections []dartdetector.Detection `json:"detections,omitempty"` // per camera detections of a throw (image pixels)
+	Manual     bool                     `json:"manual,omitempty"`     // the event was triggered manually
+}
+
+// Pipeline turns the motion events of all cameras into throw events:
+// motion events --> dart detection per camera --> calibration (board coordinates) --> multi-camera fusion --> score.
+type Pipeline struct {
+	logger           *dartmasterlogger.DartmasterLogger
+	cameraAdmin      CameraAdmin
+	calibrationStore CalibrationStore
+	board            *dartboard.Board
+
+	mu              sync.Mutex
+	detectors       map[int]*dartdetector.Detector
+	detectorConfigs map[int]dartdetector.Config
+	fusionConfig    dartfusion.Config
+	takeoutConfig   TakeoutConfig
+	bounceOutConfig BounceOutConfig
+	groupWindow     time.Duration
+	boardStates     map[int]*boardState
+	visit           []Event // throws since the last takeout
+
+	subscriptionHandler *subscriptionhandler.SubscriptionHandler[Event]
+	stopCh              chan struct{}
+	wg                  sync.WaitGroup
+}
+
+// NewPipeline returns a new detection pipeline for all cameras of the camera admin.
+func NewPipeline(cameraAdmin CameraAdmin, calibrationStore CalibrationStore, board *dartboard.Board, options ...Option) *Pipeline {
+	p := &Pipeline{
+		logger:              dartmasterlogger.NewDartmasterLogger("[detection-pipeline] "),
+		cameraAdmin:         cameraAdmin,
+		calibrationStore:    calibrationStore,
+		board:               board,
+		detectors:           make(map[int]*dartdetector.Detector),
+		detectorConfigs:     make(map[int]dartdetector.Config),
+		fusionConfig:        dartfusion.DefaultConfig(),
+		takeoutConfig:       DefaultTakeoutConfig(),
+		bounceOutConfig:     DefaultBounceOutConfig(),
+		boardStates:         make(map[int]*boardState),
+		groupWindow:         500 * time.Millisecond,
+		subscriptionHandler: subscriptionhandler.NewSubscriptionHandler[Event](),
+	}
+
+	// apply options
+	for _, o := range options {
+		o(p)
+	}
+	for _, cameraID := range cameraAdmin.CameraIDs() {
+		p.detectors[cameraID] = dartdetector.NewDetector(cameraID, p.detectorConfigs[cameraID])
+	}
+	return p
+}
+
+// Start subscribes on the motion events of all cameras and starts the detection.
+func (p *Pipeline) Start() error {
+	if p.stopCh != nil {
+		return errors.New("Start() - error: pipeline is already running")
+	}
+
+	eventCh := make(chan motiondetector.Event)
+	stopCh := make(chan struct{})
+	for _, cameraID := range p.cameraAdmin.CameraIDs() {
+		motionCh, err := p.cameraAdmin.SubscribeMotion(cameraID, subscriberName)
+		if err != nil {
+			close(stopCh)
+			p.wg.Wait()
+			return fmt.Errorf("Start() - error: %v", err)
+		}
+
+		// forward the events of all cameras into one channel
+		p.wg.Add(1)
+		go func(cameraID int, motionCh <-chan motiondetector.Event) {
+			defer p.wg.Done()
+			defer p.cameraAdmin.UnsubscribeMotion(cameraID, motionCh, subscriberName)
+			for {
+				select {
+				case <-stopCh:
+					return
+				case event, ok := <-motionCh:
+					if !ok {
+						p.logger.PrintfErr("motion events of camera %d closed", cameraID)
+						return
+					}
+					select {
+					case eventCh <- event:
+					case <-stopCh:
+						return
+					}
+				}
+			}
+		}(cameraID, motionCh)
+	}
+	p.stopCh = stopCh
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.run(eventCh, stopCh)
+	}()
+	p.logger.Println("pipeline started")
+	return nil
+}
+
+// Stop stops the detection and closes all subscriber channels.
+func (p *Pipeline) Stop() {
+	if p.stopCh == nil {
+		return
+	}
+	close(p.stopCh)
+	p.wg.Wait()
+	p.stopCh = nil
+	p.subscriptionHandler.UnsubscribeAll()
+	p.logger.Println("pipeline stopped")
+}
+
+// Subscribe subscribes on the detection events.
+func (p *Pipeline) Subscribe() <-chan Event {
+	return p.subscriptionHandler.Subscribe()
+}
+
+// Unsubscribe unsubscribes from the detection events and closes the channel.
+func (p *Pipeline) Unsubscribe(eventCh <-chan Event) {
+	p.subscriptionHandler.Unsubscribe(eventCh)
+}
+
+// SetDetectorConfig replaces the dart detector configuration of a camera at runtime (e.g. while tuning a camera).
+func (p *Pipeline) SetDetectorConfig(cameraID int, config dartdetector.Config) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	detector, ok := p.detectors[cameraID]
+	if !ok {
+		return fmt.Errorf("SetDetectorConfig() - error: unknown camera (camera-id: %d)", cameraID)
+	}
+	detector.SetConfig(config)
+	return nil
+}
+
+// DetectorConfig returns the dart detector configuration of a camera.
+func (p *Pipeline) DetectorConfig(cameraID int) (dartdetector.Config, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	detector, ok := p.detectors[cameraID]
+	if !ok {
+		return dartdetector.Config{}, fmt.Errorf("DetectorConfig() - error: unknown camera (camera-id: %d)", cameraID)
+	}
+	return detector.Config(), nil
+}
+
+// run groups the settled motion events of all cameras that belong to the same throw and processes them together.
+func (p *Pipeline) run(eventCh <-chan motiondetector.Event, stopCh <-chan struct{}) {
+	group := make(map[int]motiondetector.Event)
+	motionStarts := make(map[int]time.Time) // start of the current motion per camera
+	var groupTimer <-chan time.Time
+	for {
+		select {
+		case <-stopCh:
+			return
+		case event := <-eventCh:
+			switch event.Type {
+			case motiondetector.MotionStarted:
+				if _, ok := motionStarts[event.CameraID]; !ok {
+					motionStarts[event.CameraID] = event.Timestamp
+				}
+			case motiondetector.MotionSettled, motiondetector.PersistentChange:
+				// the persistent change event follows the settled event of the same camera and replaces it
+				group[event.CameraID] = event
+				if groupTimer == nil {
+					groupTimer = time.After(p.groupWindow)
+				}
+			}
+		case <-groupTimer:
+			p.processGroup(group, motionStarts)
+			for cameraID := range group {
+				delete(motionStarts, cameraID)
+			}
+			group = make(map[int]motiondetector.Event)
+			groupTimer = nil
+		}
+	}
+}
+
+// processGroup detects takeouts, bounce-outs and the dart in all cameras with a persistent change and publishes the resulting event.
+func (p *Pipeline) processGroup(group map[int]motiondetector.Event, motionStarts map[int]time.Time) {
+	if p.detectTakeout(group) {
+		p.logger.Println("takeout detected")
+		p.publish(Event{
+			Type:      Takeout,
+			Timestamp: latestTimestamp(group),
+		})
+		return
+	}
+
+	var changes []motiondetector.Event
+	for _, event := range group {
+		if event.Type == motiondetector.PersistentChange {
+			changes = append(changes, event)
+		}
+	}
+	if len(changes) == 0 {
+		if timestamp, result, ok := p.detectBounceOut(group, motionStarts); ok {
+			if result.NeedsConfirmation {
+				p.logger.Printf("bounce-out needs confirmation: %s", result.Reason)
+			} else {
+				p.logger.Println("bounce-out detected")
+			}
+			p.publish(Event{
+				Type:      BounceOut,
+				Timestamp: timestamp,
+				Throw:     &result,
+			})
+		}
+		return
+	}
+
+	detections, observations, obstructed := p.detect(changes)
+	if 2*obstructed > len(changes) {
+		// --> most cameras see a change that is too large for a dart (e.g. a hand in front of the board)
+		p.logger.Printf("board obstructed on %d of %d cameras --> no throw", obstructed, len(changes))
+		return
+	}
+	if len(observations) == 0 {
+		p.logger.PrintfErr("persistent change on %d cameras, but no dart detected", len(changes))
+		return
+	}
+
+	result, err := dartfusion.Fuse(p.board, observations, p.fusionConfig)
+	if err != nil {
+		p.logger.PrintlnErr(err)
+		return
+	}
+	if result.NeedsConfirmation {
+		p.logger.Printf("throw %s needs confirmation: %s", result.Score.Label, result.Reason)
+	} else {
+		p.logger.Printf("throw %s (confidence: %.2f)", result.Score.Label, result.Confidence)
+	}
+	p.publish(Event{
+		Type:       Throw,
+		Timestamp:  latestTimestamp(group),
+		Throw:      &result,
+		Detections: detections,
+	})
+}
+
+// detect runs the dart detection on the before/after frames of each event and projects the tips to the board.
+// The amount of cameras with a change that is too large for a dart is returned as well.
+func (p *Pipeline) detect(events []motiondetector.Event) ([]dartdetector.Detection, []dartfusion.Observation, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var detections []dartdetector.Detection
+	var observations []dartfusion.Observation
+	obstructed := 0
+	for _, event := range events {
+		detector, ok := p.detectors[event.CameraID]
+		if !ok {
+			continue
+		}
+		c, ok := p.calibrationStore.Get(event.CameraID)
+		if !ok {
+			p.logger.Printf("camera %d is not calibrated --> ignored", event.CameraID)
+			continue
+		}
+		if event.Before.IsEmpty() || event.After.IsEmpty() {
+			continue
+		}
+
+		detection, err := detector.Detect(event.Before, event.After)
+		if errors.Is(err, dartdetector.ErrChangeTooLarge) {
+			obstructed++
+		}
+		if err != nil {
+			p.logger.Printf("camera %d: %v", event.CameraID, err)
+			continue
+		}
+		detections = append(detections, detection)
+		observations = append(observations, dartfusion.Observation{
+			CameraID:   event.CameraID,
+			Position:   c.ImageToBoard(detection.Tip),
+			Confidence: detection.Confidence,
+		})
+	}
+	return detections, observations, obstructed
+}
+
+// publish records the throws of the current visit and publishes the event with all subscribers.
+func (p *Pipeline) publish(event Event) {
+	p.mu.Lock()
+	switch event.Type {
+	case Throw:
+		p.visit = append(p.visit, event)
+	case Takeout:
+		p.visit = nil
+	}
+	p.mu.Unlock()
+	p.subscriptionHandler.Publish(event)
+}
+
+// Throws returns the throws since the last takeout.
+func (p *Pipeline) Throws() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Event(nil), p.visit...)
+}
+
+func latestTimestamp(events map[int]motiondetector.Event) time.Time {
+	var latest time.Time
+	for _, event := range events {
+		if event.Timestamp.After(latest) {
+			latest = event.Timestamp
+		}
+	}
+	return latest
+}
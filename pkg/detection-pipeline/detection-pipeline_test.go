@@ -0,0 +1,148 @@
+package detectionpipeline
+
+import (
+	"image"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	motiondetector "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/motion-detector"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+// testCameraAdmin provides a motion channel per camera that is fed by the test.
+type testCameraAdmin struct {
+	motionChs map[int]chan motiondetector.Event
+}
+
+func newTestCameraAdmin(cameraIDs ...int) *testCameraAdmin {
+	a := &testCameraAdmin{motionChs: make(map[int]chan motiondetector.Event)}
+	for _, cameraID := range cameraIDs {
+		a.motionChs[cameraID] = make(chan motiondetector.Event, 10)
+	}
+	return a
+}
+
+func (a *testCameraAdmin) CameraIDs() []int {
+	var cameraIDs []int
+	for cameraID := range a.motionChs {
+		cameraIDs = append(cameraIDs, cameraID)
+	}
+	sort.Ints(cameraIDs)
+	return cameraIDs
+}
+
+func (a *testCameraAdmin) SubscribeMotion(cameraID int, subscriberName string) (<-chan motiondetector.Event, error) {
+	return a.motionChs[cameraID], nil
+}
+
+func (a *testCameraAdmin) UnsubscribeMotion(cameraID int, eventCh <-chan motiondetector.Event, subscriberName string) {
+}
+
+// testCalibrationStore holds the calibrated cameras.
+type testCalibrationStore map[int]calibration.Calibration
+
+func (s testCalibrationStore) Get(cameraID int) (calibration.Calibration, bool) {
+	c, ok := s[cameraID]
+	return c, ok
+}
+
+const (
+	testWidth  = 64
+	testHeight = 48
+)
+
+// grayFrame returns a GREY frame with a background of 100 and the hand-overed rectangles filled dark.
+func grayFrame(cameraID int, rects ...image.Rectangle) frame.Frame {
+	data := make([]byte, testWidth*testHeight)
+	for i := range data {
+		data[i] = 100
+	}
+	for _, rect := range rects {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				data[y*testWidth+x] = 20
+			}
+		}
+	}
+	return frame.Frame{
+		CameraID:  cameraID,
+		Data:      data,
+		PixFormat: v4l2.PixFormat{Width: testWidth, Height: testHeight, PixelFormat: v4l2.PixelFmtGrey},
+	}
+}
+
+func emptyBoard(cameraID int) frame.Frame { return grayFrame(cameraID) }
+
+func dartInBoard(cameraID int) frame.Frame { return grayFrame(cameraID, image.Rect(20, 20, 32, 24)) }
+
+// change returns the persistent change of a camera between two still frames.
+func change(cameraID int, before, after func(cameraID int) frame.Frame, timestamp time.Time) motiondetector.Event {
+	return motiondetector.Event{
+		CameraID:  cameraID,
+		Type:      motiondetector.PersistentChange,
+		Timestamp: timestamp,
+		Before:    before(cameraID),
+		After:     after(cameraID),
+	}
+}
+
+// receive returns the events of the pipeline until no event arrives for the hand-overed duration.
+func receive(eventCh <-chan Event, quiet time.Duration) []Event {
+	var events []Event
+	for {
+		select {
+		case event := <-eventCh:
+			events = append(events, event)
+		case <-time.After(quiet):
+			return events
+		}
+	}
+}
+
+// the settled cameras of a throw are grouped within the group window, later events start a new group
+func TestGroupWindow(t *testing.T) {
+	const window = 50 * time.Millisecond
+	tests := []struct {
+		name  string
+		pause time.Duration // between the events of the first and the second camera
+		want  int           // takeouts
+	}{
+		// --> both cameras see the takeout in one group
+		{name: "one group", pause: 0, want: 1},
+		// --> every camera is a group of its own and votes for a takeout
+		{name: "two groups", pause: 4 * window, want: 2},
+	}
+	for _, tt := range tests {
+		cameraAdmin := newTestCameraAdmin(1, 2)
+		p := NewPipeline(cameraAdmin, testCalibrationStore{}, dartboard.Standard(), WithGroupWindow(window))
+		eventCh := p.Subscribe()
+		if err := p.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		// the dart lands (the empty board reference is the frame before), the next group removes it
+		now := time.Now()
+		for _, cameraID := range []int{1, 2} {
+			cameraAdmin.motionChs[cameraID] <- change(cameraID, emptyBoard, dartInBoard, now)
+		}
+		time.Sleep(4 * window)
+		cameraAdmin.motionChs[1] <- change(1, dartInBoard, emptyBoard, now)
+		time.Sleep(tt.pause)
+		cameraAdmin.motionChs[2] <- change(2, dartInBoard, emptyBoard, now)
+
+		takeouts := 0
+		for _, event := range receive(eventCh, 4*window) {
+			if event.Type == Takeout {
+				takeouts++
+			}
+		}
+		p.Stop()
+		if takeouts != tt.want {
+			t.Errorf("%s: %d takeouts, want %d", tt.name, takeouts, tt.want)
+		}
+	}
+}
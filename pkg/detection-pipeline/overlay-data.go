@@ -0,0 +1,42 @@
+package detectionpipeline
+
+import (
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/overlay"
+)
+
+// OverlayData returns the calibration and the darts of the current visit of a camera for its overlay stream.
+// It is used as overlay source of the camera admin.
+func (p *Pipeline) OverlayData(cameraID int) overlay.Data {
+	data := overlay.Data{Board: p.board}
+	c, ok := p.calibrationStore.Get(cameraID)
+	if ok {
+		data.Calibration = &c
+	}
+
+	for _, event := range p.Throws() {
+		if event.Throw == nil {
+			continue
+		}
+		dart := overlay.Dart{
+			Label:     event.Throw.Score.Label,
+			Uncertain: event.Throw.NeedsConfirmation,
+		}
+
+		// the tip as seen by the camera, otherwise the fused position projected into the camera
+		found := false
+		for _, detection := range event.Detections {
+			if detection.CameraID == cameraID {
+				dart.Image, found = detection.Tip, true
+				break
+			}
+		}
+		if !found {
+			if !ok {
+				continue
+			}
+			dart.Image = c.BoardToImage(event.Throw.Position)
+		}
+		data.Darts = append(data.Darts, dart)
+	}
+	return data
+}
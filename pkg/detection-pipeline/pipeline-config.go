@@ -0,0 +1,45 @@
+package detectionpipeline
+
+import (
+	"time"
+
+	dartdetector "github.com/One-Hundred-Eighty/Circle/pkg/dart-detector"
+	dartfusion "github.com/One-Hundred-Eighty/Circle/pkg/dart-fusion"
+)
+
+type Option func(*Pipeline)
+
+// WithDetectorConfig sets the dart detector configuration of a camera.
+func WithDetectorConfig(cameraID int, config dartdetector.Config) Option {
+	return func(p *Pipeline) {
+		p.detectorConfigs[cameraID] = config
+	}
+}
+
+// WithFusionConfig replaces the default configuration of the multi-camera fusion.
+func WithFusionConfig(config dartfusion.Config) Option {
+	return func(p *Pipeline) {
+		p.fusionConfig = config
+	}
+}
+
+// WithGroupWindow sets the time to wait for the motion events of the other cameras after the first camera has settled.
+func WithGroupWindow(window time.Duration) Option {
+	return func(p *Pipeline) {
+		p.groupWindow = window
+	}
+}
+
+// WithTakeoutConfig replaces the default configuration of the takeout detection.
+func WithTakeoutConfig(config TakeoutConfig) Option {
+	return func(p *Pipeline) {
+		p.takeoutConfig = config
+	}
+}
+
+// WithBounceOutConfig replaces the default configuration of the bounce-out detection.
+func WithBounceOutConfig(config BounceOutConfig) Option {
+	return func(p *Pipeline) {
+		p.bounceOutConfig = config
+	}
+}
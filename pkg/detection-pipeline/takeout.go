@@ -0,0 +1,128 @@
+package detectionpipeline
+
+import (
+	"time"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	motiondetector "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/motion-detector"
+)
+
+// comparison of a still frame with the empty board
+const (
+	emptyBoardScale          = 4
+	emptyBoardPixelThreshold = 25
+)
+
+type TakeoutConfig struct {
+	// EmptyRatio is the maximum ratio of pixels that may differ from the empty board reference to count as empty board
+	EmptyRatio float64
+}
+
+// DefaultTakeoutConfig returns the default takeout configuration.
+func DefaultTakeoutConfig() TakeoutConfig {
+	return TakeoutConfig{
+		EmptyRatio: 0.0005,
+	}
+}
+
+// boardState is the empty board reference and the last still frame of a camera.
+type boardState struct {
+	emptyReference frame.Frame
+	lastStill      frame.Frame
+}
+
+// Takeout publishes a takeout event manually (e.g. if the automatic detection failed) and adopts the current still frames as empty board references.
+func (p *Pipeline) Takeout() {
+	p.mu.Lock()
+	for _, state := range p.boardStates {
+		if !state.lastStill.IsEmpty() {
+			state.emptyReference = state.lastStill
+		}
+	}
+	p.mu.Unlock()
+
+	p.logger.Println("manual takeout")
+	p.publish(Event{
+		Type:      Takeout,
+		Timestamp: time.Now(),
+		Manual:    true,
+	})
+}
+
+// detectTakeout reports whether the darts were removed: the board held darts before the motion and matches the empty board reference afterwards.
+// The majority of the cameras with an empty board reference has to agree.
+func (p *Pipeline) detectTakeout(group map[int]motiondetector.Event) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var votes, cameras int
+	for cameraID, event := range group {
+		state, ok := p.boardStates[cameraID]
+		if !ok {
+			state = &boardState{}
+			p.boardStates[cameraID] = state
+		}
+		if state.emptyReference.IsEmpty() {
+			// --> the board is expected to be empty when the pipeline starts
+			state.emptyReference = event.Before
+		}
+		if !event.After.IsEmpty() {
+			state.lastStill = event.After
+		}
+		if state.emptyReference.IsEmpty() || event.Before.IsEmpty() || event.After.IsEmpty() {
+			continue
+		}
+
+		cameras++
+		beforeEmpty, err := p.matchesEmptyBoard(state, event.Before)
+		if err != nil {
+			p.logger.Printf("camera %d: %v", cameraID, err)
+			continue
+		}
+		afterEmpty, err := p.matchesEmptyBoard(state, event.After)
+		if err != nil {
+			p.logger.Printf("camera %d: %v", cameraID, err)
+			continue
+		}
+		if !beforeEmpty && afterEmpty {
+			votes++
+		}
+	}
+	if cameras == 0 || 2*votes <= cameras {
+		return false
+	}
+
+	// refresh the empty board references (e.g. the light has changed)
+	for cameraID, event := range group {
+		if state, ok := p.boardStates[cameraID]; ok && !event.After.IsEmpty() {
+			state.emptyReference = event.After
+		}
+	}
+	return true
+}
+
+// matchesEmptyBoard compares a still frame with the empty board reference of a camera.
+func (p *Pipeline) matchesEmptyBoard(state *boardState, f frame.Frame) (bool, error) {
+	reference, err := state.emptyReference.GrayScaled(emptyBoardScale)
+	if err != nil {
+		return false, err
+	}
+	gray, err := f.GrayScaled(emptyBoardScale)
+	if err != nil {
+		return false, err
+	}
+	if reference.Bounds() != gray.Bounds() {
+		// --> the frame size has changed --> the reference is outdated
+		state.emptyReference = f
+		return true, nil
+	}
+
+	changed := 0
+	for i := range gray.Pix {
+		diff := int(gray.Pix[i]) - int(reference.Pix[i])
+		if diff > emptyBoardPixelThreshold || -diff > emptyBoardPixelThreshold {
+			changed++
+		}
+	}
+	return float64(changed)/float64(len(gray.Pix)) <= p.takeoutConfig.EmptyRatio, nil
+}
@@ -0,0 +1,85 @@
+package detectionpipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	motiondetector "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/motion-detector"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+func TestDetectTakeout(t *testing.T) {
+	type frames struct {
+		before, after func(cameraID int) frame.Frame
+	}
+	var (
+		landed  = frames{emptyBoard, dartInBoard}
+		removed = frames{dartInBoard, emptyBoard}
+		stays   = frames{dartInBoard, dartInBoard}
+	)
+	tests := []struct {
+		name    string
+		cameras []frames // camera i+1 sees the frames of the group
+		want    bool
+	}{
+		{name: "all cameras see the takeout", cameras: []frames{removed, removed, removed}, want: true},
+		{name: "majority sees the takeout", cameras: []frames{removed, removed, stays}, want: true},
+		{name: "minority sees the takeout", cameras: []frames{removed, stays, stays}, want: false},
+		{name: "tie", cameras: []frames{removed, stays}, want: false},
+		{name: "second dart lands", cameras: []frames{stays, stays, stays}, want: false},
+	}
+	for _, tt := range tests {
+		p := NewPipeline(newTestCameraAdmin(), testCalibrationStore{}, dartboard.Standard())
+
+		// --> the first group sets the empty board references
+		first := make(map[int]motiondetector.Event)
+		for i := range tt.cameras {
+			first[i+1] = change(i+1, landed.before, landed.after, time.Now())
+		}
+		if p.detectTakeout(first) {
+			t.Errorf("%s: takeout detected for a landed dart", tt.name)
+		}
+
+		group := make(map[int]motiondetector.Event)
+		for i, f := range tt.cameras {
+			group[i+1] = change(i+1, f.before, f.after, time.Now())
+		}
+		if got := p.detectTakeout(group); got != tt.want {
+			t.Errorf("%s: takeout %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// a detected takeout adopts the still frames as new empty board references (e.g. the light has changed)
+func TestDetectTakeoutRefreshesReference(t *testing.T) {
+	p := NewPipeline(newTestCameraAdmin(), testCalibrationStore{}, dartboard.Standard())
+	p.detectTakeout(map[int]motiondetector.Event{1: change(1, emptyBoard, dartInBoard, time.Now())})
+	if !p.detectTakeout(map[int]motiondetector.Event{1: change(1, dartInBoard, emptyBoard, time.Now())}) {
+		t.Fatal("takeout not detected")
+	}
+	if got := p.boardStates[1].emptyReference.Data; string(got) != string(emptyBoard(1).Data) {
+		t.Error("empty board reference not refreshed")
+	}
+}
+
+func TestManualTakeout(t *testing.T) {
+	p := NewPipeline(newTestCameraAdmin(), testCalibrationStore{}, dartboard.Standard())
+	eventCh := p.Subscribe()
+	defer p.Unsubscribe(eventCh)
+
+	// --> the board holds a dart that was not detected as removed, the manual takeout adopts it as empty board
+	p.detectTakeout(map[int]motiondetector.Event{1: change(1, emptyBoard, dartInBoard, time.Now())})
+	p.Takeout()
+	select {
+	case event := <-eventCh:
+		if event.Type != Takeout || !event.Manual {
+			t.Errorf("event %+v, want a manual takeout", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no takeout event")
+	}
+	if got := p.boardStates[1].emptyReference.Data; string(got) != string(dartInBoard(1).Data) {
+		t.Error("still frame not adopted as empty board reference")
+	}
+}
@@ -0,0 +1,75 @@
+package simulator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+)
+
+// CameraView is the perspective of a simulated camera on the board.
+type CameraView struct {
+	Width  int
+	Height int
+	// BoardToImage maps board coordinates (mm) to image pixels
+	BoardToImage calibration.Homography
+	// DartDirection is the image direction from the tip to the flights of a dart in the board
+	DartDirection calibration.Point
+}
+
+// NewCameraView returns the view of a camera that looks at the board from the side given by azimuth (degrees, counterclockwise from the board's right).
+// The board is foreshortened along the viewing direction by the tilt factor (1 = frontal view) and scaled by pixelsPerMM.
+func NewCameraView(width, height int, azimuth, tilt, pixelsPerMM float64) CameraView {
+	a := azimuth * math.Pi / 180
+	cos, sin := math.Cos(a), math.Sin(a)
+
+	// rotate into the viewing direction, foreshorten, rotate back and flip the y-axis (image y points down)
+	m00 := pixelsPerMM * (cos*cos + tilt*sin*sin)
+	m01 := pixelsPerMM * (cos*sin - tilt*cos*sin)
+	m10 := pixelsPerMM * (sin*cos - tilt*sin*cos)
+	m11 := pixelsPerMM * (sin*sin + tilt*cos*cos)
+
+	// slight perspective: the side of the board closer to the camera appears larger
+	perspective := 0.0006 * (1 - tilt)
+	return CameraView{
+		Width:  width,
+		Height: height,
+		BoardToImage: calibration.Homography{
+			m00, -m01, float64(width) / 2,
+			m10, -m11, float64(height) / 2,
+			-perspective * cos, -perspective * sin, 1,
+		},
+		DartDirection: calibration.Point{X: cos*0.6 - sin*0.8, Y: -sin*0.6 - cos*0.8},
+	}
+}
+
+// DefaultViews returns the views of three cameras around the board (left, top and right).
+func DefaultViews(width, height int) []CameraView {
+	pixelsPerMM := 0.9 * float64(min(width, height)) / (2 * 225)
+	return []CameraView{
+		NewCameraView(width, height, 150, 0.7, pixelsPerMM),
+		NewCameraView(width, height, 90, 0.75, pixelsPerMM),
+		NewCameraView(width, height, 30, 0.7, pixelsPerMM),
+	}
+}
+
+// Calibration returns the exact calibration of a view, computed from the projected reference points.
+func (v CameraView) Calibration(cameraID int) (calibration.Calibration, error) {
+	var correspondences []calibration.Correspondence
+	names := append([]string{"BULL"}, calibration.DefaultReferencePoints...)
+	for _, name := range calibration.DefaultReferencePoints {
+		names = append(names, "T"+name)
+	}
+	for _, name := range names {
+		boardPoint, err := calibration.BoardPoint(name)
+		if err != nil {
+			return calibration.Calibration{}, err
+		}
+		correspondences = append(correspondences, calibration.Correspondence{Name: name, Image: v.BoardToImage.Apply(boardPoint)})
+	}
+	c, err := calibration.Calibrate(cameraID, v.Width, v.Height, correspondences)
+	if err != nil {
+		return calibration.Calibration{}, fmt.Errorf("Calibration() - error: %v", err)
+	}
+	return c, nil
+}
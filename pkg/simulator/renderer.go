@@ -0,0 +1,140 @@
+package simulator
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+// colors of the synthetic board
+var (
+	colorRed      = color.RGBA{R: 200, G: 30, B: 40, A: 255}
+	colorGreen    = color.RGBA{R: 20, G: 140, B: 60, A: 255}
+	colorBlack    = color.RGBA{R: 20, G: 20, B: 20, A: 255}
+	colorCream    = color.RGBA{R: 230, G: 220, B: 190, A: 255}
+	colorSurround = color.RGBA{R: 10, G: 10, B: 10, A: 255}
+	colorWall     = color.RGBA{R: 110, G: 110, B: 110, A: 255}
+	colorHand     = color.RGBA{R: 225, G: 180, B: 150, A: 255}
+)
+
+// surroundRadius is the outer radius (mm) of the black board surround
+const surroundRadius = 225.0
+
+// dart dimensions in image pixels per board mm
+const (
+	dartLength      = 80.0 // mm
+	dartBarrelRatio = 0.6  // share of the barrel (incl. the tip) on the dart length
+	dartBarrelWidth = 1.5  // mm, half width
+	dartFlightWidth = 7.0  // mm, half width
+)
+
+// renderBoard renders the empty board from the perspective of a camera view.
+func renderBoard(board *dartboard.Board, view CameraView) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, view.Width, view.Height))
+	imageToBoard, err := view.BoardToImage.Inverse()
+	if err != nil {
+		return img
+	}
+
+	for y := 0; y < view.Height; y++ {
+		for x := 0; x < view.Width; x++ {
+			p := imageToBoard.Apply(calibration.Point{X: float64(x), Y: float64(y)})
+			img.SetRGBA(x, y, boardColor(board, p))
+		}
+	}
+	return img
+}
+
+// boardColor returns the color of a board point: red/green doubles, trebles and bull, black/cream singles.
+func boardColor(board *dartboard.Board, p calibration.Point) color.RGBA {
+	r := math.Hypot(p.X, p.Y)
+	if r > surroundRadius {
+		return colorWall
+	}
+	score := board.Score(p.X, p.Y)
+	even := segmentIndex(score.Segment)%2 == 0
+	switch score.Ring {
+	case dartboard.RingMiss:
+		return colorSurround
+	case dartboard.RingInnerBull:
+		return colorRed
+	case dartboard.RingOuterBull:
+		return colorGreen
+	case dartboard.RingTreble, dartboard.RingDouble:
+		if even {
+			return colorRed
+		}
+		return colorGreen
+	default:
+		if even {
+			return colorBlack
+		}
+		return colorCream
+	}
+}
+
+func segmentIndex(segment int) int {
+	for i, s := range dartboard.SegmentOrder {
+		if s == segment {
+			return i
+		}
+	}
+	return -1
+}
+
+// drawDart draws a dart stuck in the board at a board point. The dart is drawn with maximum contrast to the board,
+// so the detection is tested by its geometry: a thin barrel at the tip and wide flights at the end.
+func drawDart(img *image.RGBA, view CameraView, tip calibration.Point) {
+	imageTip := view.BoardToImage.Apply(tip)
+	scale := pixelsPerMM(view, tip)
+	direction := view.DartDirection
+	normal := calibration.Point{X: -direction.Y, Y: direction.X}
+
+	length := dartLength * scale
+	drawn := make(map[image.Point]bool)
+	for t := 0.0; t <= length; t += 0.5 {
+		halfWidth := dartBarrelWidth * scale
+		if t > length*dartBarrelRatio {
+			halfWidth = dartFlightWidth * scale
+		}
+		for w := -halfWidth; w <= halfWidth; w += 0.5 {
+			x := int(math.Round(imageTip.X + t*direction.X + w*normal.X))
+			y := int(math.Round(imageTip.Y + t*direction.Y + w*normal.Y))
+			if pixel := (image.Point{X: x, Y: y}); !pixel.In(img.Rect) || drawn[pixel] {
+				continue
+			}
+			drawn[image.Point{X: x, Y: y}] = true
+			img.SetRGBA(x, y, contrastColor(img.RGBAAt(x, y)))
+		}
+	}
+}
+
+// drawHand draws a hand (e.g. while pulling the darts) that covers the center of the board.
+func drawHand(img *image.RGBA, view CameraView) {
+	center := view.BoardToImage.Apply(calibration.Point{})
+	size := 120 * pixelsPerMM(view, calibration.Point{})
+	for y := int(center.Y - size); y < int(center.Y+size); y++ {
+		for x := int(center.X - size/2); x < int(center.X+size/2); x++ {
+			if (image.Point{X: x, Y: y}).In(img.Rect) {
+				img.SetRGBA(x, y, colorHand)
+			}
+		}
+	}
+}
+
+// contrastColor returns white on dark and black on bright backgrounds.
+func contrastColor(background color.RGBA) color.RGBA {
+	luma := 0.299*float64(background.R) + 0.587*float64(background.G) + 0.114*float64(background.B)
+	if luma < 128 {
+		return color.RGBA{R: 250, G: 250, B: 250, A: 255}
+	}
+	return color.RGBA{R: 5, G: 5, B: 5, A: 255}
+}
+
+// pixelsPerMM returns the local image scale of the view at a board point.
+func pixelsPerMM(view CameraView, p calibration.Point) float64 {
+	return math.Sqrt(math.Abs(view.BoardToImage.JacobianDeterminant(p)))
+}
@@ -0,0 +1,225 @@
+package simulator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"sync"
+	"time"
+
+	"github.com/One-Hundred-Eighty/Circle/pkg/calibration"
+	cameraadmin "github.com/One-Hundred-Eighty/Circle/pkg/camera-admin"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/frame"
+	"github.com/One-Hundred-Eighty/Circle/pkg/camera-admin/v4l2"
+	"github.com/One-Hundred-Eighty/Circle/pkg/dartboard"
+)
+
+// Simulator renders a synthetic board with darts from the perspective of virtual cameras.
+// The virtual cameras are used as frame sources of the camera admin to test the detection without a board.
+type Simulator struct {
+	mu          sync.Mutex
+	board       *dartboard.Board
+	views       []CameraView
+	darts       []calibration.Point
+	flying      *calibration.Point // dart that is visible for a moment only (e.g. a bounce-out)
+	hand        bool
+	version     uint64 // incremented on each change of the scene
+	fps         int
+	emptyBoards []*image.RGBA // rendered empty board per view
+}
+
+// NewSimulator returns a simulator for the hand-overed camera views. The virtual cameras deliver fps frames per second.
+func NewSimulator(board *dartboard.Board, views []CameraView, fps int) *Simulator {
+	s := &Simulator{
+		board: board,
+		views: views,
+		fps:   fps,
+	}
+	for _, view := range views {
+		s.emptyBoards = append(s.emptyBoards, renderBoard(board, view))
+	}
+	return s
+}
+
+// Sources returns the virtual cameras of all views. The camera-id of a view is its position + 1.
+func (s *Simulator) Sources() []cameraadmin.FrameSource {
+	var sources []cameraadmin.FrameSource
+	for i := range s.views {
+		sources = append(sources, &VirtualCamera{simulator: s, viewIdx: i})
+	}
+	return sources
+}
+
+// Views returns the camera views of the simulator.
+func (s *Simulator) Views() []CameraView {
+	return s.views
+}
+
+// Throw puts a dart into the board at a board point (mm).
+func (s *Simulator) Throw(p calibration.Point) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.darts = append(s.darts, p)
+	s.version++
+}
+
+// ThrowAt puts a dart into the center of the area of a score label (e.g. "T20") and returns the board point.
+func (s *Simulator) ThrowAt(label string) (calibration.Point, error) {
+	x, y, err := s.board.Target(label)
+	if err != nil {
+		return calibration.Point{}, err
+	}
+	p := calibration.Point{X: x, Y: y}
+	s.Throw(p)
+	return p, nil
+}
+
+// BounceOut shows a dart at a board point for the hand-overed duration and removes it again.
+func (s *Simulator) BounceOut(p calibration.Point, visible time.Duration) {
+	s.mu.Lock()
+	s.flying = &p
+	s.version++
+	s.mu.Unlock()
+
+	time.Sleep(visible)
+
+	s.mu.Lock()
+	s.flying = nil
+	s.version++
+	s.mu.Unlock()
+}
+
+// Takeout covers the board with a hand for the hand-overed duration and removes all darts.
+func (s *Simulator) Takeout(duration time.Duration) {
+	s.mu.Lock()
+	s.hand = true
+	s.version++
+	s.mu.Unlock()
+
+	time.Sleep(duration)
+
+	s.mu.Lock()
+	s.hand = false
+	s.darts = nil
+	s.version++
+	s.mu.Unlock()
+}
+
+// Clear removes all darts from the board at once (without a hand in front of the board).
+func (s *Simulator) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.darts = nil
+	s.version++
+}
+
+// Darts returns the board points of the darts in the board.
+func (s *Simulator) Darts() []calibration.Point {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]calibration.Point(nil), s.darts...)
+}
+
+// render renders the current scene of a view and returns the scene version.
+func (s *Simulator) render(viewIdx int) (*image.RGBA, uint64) {
+	s.mu.Lock()
+	darts := append([]calibration.Point(nil), s.darts...)
+	if s.flying != nil {
+		darts = append(darts, *s.flying)
+	}
+	hand, version := s.hand, s.version
+	s.mu.Unlock()
+
+	view := s.views[viewIdx]
+	img := image.NewRGBA(s.emptyBoards[viewIdx].Rect)
+	draw.Draw(img, img.Rect, s.emptyBoards[viewIdx], image.Point{}, draw.Src)
+	for _, dart := range darts {
+		drawDart(img, view, dart)
+	}
+	if hand {
+		drawHand(img, view)
+	}
+	return img, version
+}
+
+func (s *Simulator) sceneVersion() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version
+}
+
+// VirtualCamera delivers the rendered scene of a camera view as mjpeg frames.
+type VirtualCamera struct {
+	simulator *Simulator
+	viewIdx   int
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// Start starts delivering frames. The requested resolution has to match the resolution of the camera view.
+func (vc *VirtualCamera) Start(width, height int) (<-chan frame.Frame, error) {
+	if vc.stopCh != nil {
+		return nil, errors.New("Start() - error: virtual camera is already running")
+	}
+	view := vc.simulator.views[vc.viewIdx]
+	if width != view.Width || height != view.Height {
+		return nil, fmt.Errorf("Start() - error: resolution %dx%d not supported, the view has %dx%d", width, height, view.Width, view.Height)
+	}
+
+	frameCh := make(chan frame.Frame, 1)
+	vc.stopCh = make(chan struct{})
+	vc.wg.Add(1)
+	go vc.run(frameCh, vc.stopCh)
+	return frameCh, nil
+}
+
+// Stop stops delivering frames and closes the frame channel.
+func (vc *VirtualCamera) Stop() {
+	if vc.stopCh == nil {
+		return
+	}
+	close(vc.stopCh)
+	vc.wg.Wait()
+	vc.stopCh = nil
+}
+
+// run delivers a frame per tick. The scene is only rendered again if it has changed.
+func (vc *VirtualCamera) run(frameCh chan<- frame.Frame, stopCh <-chan struct{}) {
+	defer vc.wg.Done()
+	defer close(frameCh)
+
+	view := vc.simulator.views[vc.viewIdx]
+	ticker := time.NewTicker(time.Second / time.Duration(max(vc.simulator.fps, 1)))
+	defer ticker.Stop()
+
+	var data []byte
+	var renderedVersion uint64
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if data == nil || vc.simulator.sceneVersion() != renderedVersion {
+				img, version := vc.simulator.render(vc.viewIdx)
+				var buf bytes.Buffer
+				if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+					continue
+				}
+				data, renderedVersion = buf.Bytes(), version
+			}
+			f := frame.Frame{
+				Data:      data,
+				PixFormat: v4l2.PixFormat{Width: uint32(view.Width), Height: uint32(view.Height), PixelFormat: v4l2.PixelFmtMJPEG},
+				Timestamp: time.Now(),
+			}
+			select {
+			case frameCh <- f:
+			default:
+				// --> consumer is busy --> drop the frame like a camera driver
+			}
+		}
+	}
+}
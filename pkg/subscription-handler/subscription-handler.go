@@ -44,6 +44,8 @@ func (sh *SubscriptionHandler[T]) Publish(item T) {
 
 // Subscriptions return the current amount of subscriptions.
 func (sh *SubscriptionHandler[T]) Subscriptions() int {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
 	return len(sh.subscriptions)
 }
 
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 
+	dartcounterevents "github.com/One-Hundred-Eighty/Circle/pkg/dartcounter-events"
 	dartmasterlogger "github.com/One-Hundred-Eighty/Circle/pkg/dartmaster-logger"
 )
 
@@ -21,7 +22,12 @@ type uriBuilder struct{}
 type eventStream struct {
 	Id    string
 	Event string
-	Data  []byte // dynamic data (e.g. binary-status-change or binary-output-change)
+	Data  []byte // json payload of the event type (see dartcounterevents)
+}
+
+// Decode decodes the data into the payload of the event type (a pointer to one of the dartcounterevents event types).
+func (e eventStream) Decode() (any, error) {
+	return dartcounterevents.Decode(e.Event, e.Data)
 }
 
 func NewDartcounterUriCaller() *dartcounterUriCaller {